@@ -14,14 +14,26 @@ import (
 	"rectaify/internal/cache"
 	"rectaify/internal/config"
 	"rectaify/internal/evidence"
+	"rectaify/internal/landing"
 	"rectaify/internal/llm"
+	"rectaify/internal/money"
+	"rectaify/internal/report"
 	"rectaify/internal/schema"
 	"rectaify/internal/score"
+	"rectaify/internal/scrub"
 	"rectaify/internal/search"
 	"rectaify/internal/store"
+	"rectaify/internal/taxonomy"
+	"rectaify/internal/webhook"
 	"rectaify/pkg/httpx"
 )
 
+// Limits for fetching landing pages submitted via source_url
+const (
+	landingPageMaxBytes = 2 << 20 // 2 MiB
+	landingPageTimeout  = 15 * time.Second
+)
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
@@ -29,23 +41,37 @@ func main() {
 		log.Fatalf("Configuration validation failed: %v", err)
 	}
 
-	// Initialize database
-	ctx := context.Background()
-	db, err := schema.InitDatabase(ctx, cfg.DatabaseDSN)
+	// Initialize database and run migrations, waiting for the database to come up
+	ctx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	db, err := schema.ConnectAndMigrate(ctx, cfg.DatabaseDSN, cfg.DBConnectAttempts, cfg.DBConnectDelay)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer db.Close()
 
-	// Run migrations
-	if err := schema.Migrate(ctx, db); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
-	}
-
 	// Initialize components
-	llmClient := llm.NewClient(cfg.OpenAIAPIKey, cfg.OpenAIRPS, cfg.OpenAIBurst)
+	killSwitch := llm.NewKillSwitch(cfg.MaintenanceMode) // switches every LLM call off
+	rootCAs, _ := cfg.OutboundRootCAs()                  // validated above
+	llmClient := llm.NewClient(llm.ClientConfig{
+		APIKey:          cfg.OpenAIAPIKey,
+		BaseURL:         cfg.OpenAIBaseURL,
+		APIVersion:      cfg.OpenAIAPIVersion,
+		Model:           cfg.OpenAIModel,
+		SearchModel:     cfg.OpenAISearchModel,
+		FallbackModels:  cfg.OpenAIFallbackModels,
+		RPS:             cfg.OpenAIRPS,
+		Burst:           cfg.OpenAIBurst,
+		StrictRateLimit: cfg.OpenAIStrictRate,
+		TPM:             cfg.OpenAITPM,
+		MaxRetries:      cfg.OpenAIMaxRetries,
+		RequestTimeout:  cfg.OpenAIRequestTimeout,
+		KillSwitch:      killSwitch,
+		RootCAs:         rootCAs,
+	})
 
-	evidenceCache, err := cache.NewEvidenceCache(db, cfg.CacheLRUSize, cfg.CacheTTL)
+	cacheTTLs, _ := cfg.EvidenceCacheTTLs() // validated above
+	evidenceCache, err := cache.NewEvidenceCache(db, cfg.CacheLRUSize, cfg.CacheTTL, cacheTTLs)
 	if err != nil {
 		log.Fatalf("Failed to initialize evidence cache: %v", err)
 	}
@@ -53,55 +79,223 @@ func main() {
 	// Start cache cleanup worker
 	go evidenceCache.StartCleanupWorker(ctx, time.Hour)
 
-	planner := search.NewPlanner(cfg.MaxQueries)
-	executor := search.NewExecutor(llmClient, evidenceCache, cfg.AnalysisTimeout)
-	normalizer := evidence.NewNormalizer()
-	calculator := score.NewCalculator(nil) // Use default weights
-	coordinator := analyzers.NewCoordinator(llmClient, calculator)
+	var analysisCache *cache.AnalysisCache
+	if cfg.AnalysisCacheTTL > 0 {
+		analysisCache, err = cache.NewAnalysisCache(db, cfg.CacheLRUSize, cfg.AnalysisCacheTTL)
+		if err != nil {
+			log.Fatalf("Failed to initialize analysis cache: %v", err)
+		}
+	}
+
+	var analyzerCache *cache.AnalyzerCache
+	if cfg.AnalyzerCacheTTL > 0 {
+		analyzerCache, err = cache.NewAnalyzerCache(db, cfg.CacheLRUSize, cfg.AnalyzerCacheTTL)
+		if err != nil {
+			log.Fatalf("Failed to initialize analyzer cache: %v", err)
+		}
+	}
+
+	planner := search.NewPlanner(cfg.MaxQueries, cfg.QuerySimilarity, cfg.PlannerStopWords, cfg.PlannerMaxKeyTerms)
+	executor := search.NewExecutor(llmClient, evidenceCache, cfg.SearchConcurrency, cfg.SearchOverlap)
+	sourceWeights, _ := cfg.SourceTypeWeights() // validated above
+	var scrubber *scrub.Scrubber
+	if cfg.ScrubEnabled {
+		scrubber = scrub.NewScrubber(cfg.ScrubWordlist)
+	}
+	var redirects *evidence.RedirectResolver
+	if cfg.ResolveRedirects {
+		redirects = evidence.NewRedirectResolver(cfg.RedirectTimeout, cfg.RedirectConcurrency, rootCAs)
+	}
+	sourceCaps, _ := cfg.SourceTypeCaps() // validated above
+	normalizer := evidence.NewNormalizer(sourceWeights, cfg.DefaultSourceTypeWeight, scrubber, redirects, sourceCaps, cfg.IncludePDFEvidence)
+	// Default weights with the configured no-failure graveyard baselines
+	calculator := score.NewCalculator(nil, &score.GraveyardBaselines{
+		NoFailures: cfg.GraveyardSearched,
+		Unsearched: cfg.GraveyardUnsearched,
+	})
+	categories := taxonomy.New(taxonomy.DefaultCategories())
+	if configured, _ := cfg.CategoryTaxonomy(); configured != nil { // validated above
+		categories = taxonomy.New(configured)
+	}
+	promptHints, _ := cfg.CategoryPromptHints() // validated above
+	snippetLimits, _ := cfg.SnippetLimits()     // validated above
+	fxOverrides, _ := cfg.FXRates()             // validated above
+	fxRates := money.DefaultRates().Merge(fxOverrides)
+	evidencePolicy, _ := analyzers.ParseEvidencePolicy(cfg.EvidencePolicy) // validated above
+	coordinator := analyzers.NewCoordinator(llmClient, calculator, cfg.AnalyzerConcurrency, promptHints, snippetLimits, fxRates, evidencePolicy, analyzers.VerdictPayload{
+		MaxEvidence: cfg.VerdictMaxEvidence,
+		Condensed:   cfg.VerdictCondensed,
+		Timeout:     cfg.VerdictRequestTimeout,
+		Strict:      cfg.VerdictStrict,
+	}, analyzerCache, cfg.AnalyzerTimeout)
 	repository := store.NewRepository(db)
 
-	orchestrator := app.NewOrchestrator(
-		planner,
-		executor,
-		normalizer,
-		coordinator,
-		repository,
-		cfg.MaxEvidencePerQuery,
-		cfg.AnalysisTimeout,
-	)
+	// Deliver analysis lifecycle events to configured webhooks
+	events := app.NewEventBus()
+	webhookSubscribers, _ := cfg.WebhookSubscribers() // validated above
+	var dispatcher *webhook.Dispatcher
+	if len(webhookSubscribers) > 0 {
+		subscribers := make([]webhook.Subscriber, len(webhookSubscribers))
+		for i, s := range webhookSubscribers {
+			subscribers[i] = webhook.Subscriber{URL: s.URL, Events: s.Events}
+		}
+		dispatcher = webhook.NewDispatcher(subscribers, cfg.WebhookSecret, cfg.WebhookTimeout, cfg.WebhookMaxAttempts, repository, rootCAs)
+		dispatcher.Start(cfg.WebhookWorkers)
+		events.Subscribe(dispatcher.Enqueue)
+	}
+
+	orchestrator := app.NewOrchestrator(app.OrchestratorOptions{
+		Planner:          planner,
+		Executor:         executor,
+		Normalizer:       normalizer,
+		Coordinator:      coordinator,
+		Repository:       repository,
+		Fetcher:          landing.NewFetcher(landingPageMaxBytes, landingPageTimeout, rootCAs),
+		Scrubber:         scrubber,
+		AnalysisCache:    analysisCache,
+		Events:           events,
+		KillSwitch:       killSwitch,
+		Categories:       categories,
+		Defaults:         app.IdeaDefaults{Category: cfg.DefaultCategory, Location: cfg.DefaultLocation},
+		MaxEvidence:      cfg.MaxEvidencePerQuery,
+		MaxEvidenceBytes: cfg.MaxEvidenceBytes,
+		MinEvidence:      cfg.MinEvidence(),
+		RetryBudget:      cfg.OpenAIRetryBudget,
+		AnalysisTimeout:  cfg.AnalysisTimeout,
+		SearchTimeout:    cfg.SearchTimeout,
+		MinTimeout:       cfg.MinAnalysisTimeout,
+		MaxTimeout:       cfg.MaxAnalysisTimeout,
+		StaleEvidenceAge: cfg.StaleEvidenceAge,
+		EvidenceMaxAge:   cfg.EvidenceCutoffAge,
+		KeepUndated:      cfg.KeepUndatedEvidence,
+		NextSteps:        cfg.NextStepsEnabled,
+		ReviewThreshold:  cfg.ReviewScoreThreshold,
+		MinSourceDomains: cfg.MinSourceDomains,
+		IdeaExpansion:    cfg.IdeaExpansionEnabled,
+		MinCoherence:     cfg.MinCoherenceScore,
+		DebugMeta:        cfg.AnalyzerDebugMeta,
+		MinInsights:      cfg.InsightsMinAnalyses,
+		AuditLog:         cfg.AuditLog,
+	})
+
+	// Start retention worker (opt-in)
+	if cfg.RetentionEnabled {
+		go orchestrator.StartRetentionWorker(ctx, cfg.RetentionInterval, cfg.EvidenceMaxAge, cfg.AnalysisRetention)
+	}
+
+	// Analyses run on a fixed worker pool rather than on request goroutines
+	queue := app.NewAnalysisQueue(orchestrator, cfg.AnalysisWorkers, cfg.AnalysisQueueDepth)
+	queue.Start()
 
 	// Initialize HTTP handlers
-	handlers := httpx.NewAPIHandlers(orchestrator)
+	evidenceOrder, _ := report.ParseEvidenceOrder(cfg.ReportEvidenceOrder) // validated above
+	reportCaps := report.SectionCaps{
+		Competitors: cfg.ReportMaxCompetitors,
+		Risks:       cfg.ReportMaxRisks,
+		Barriers:    cfg.ReportMaxBarriers,
+		Graveyard:   cfg.ReportMaxGraveyard,
+	}
+	reportTheme := report.Theme{
+		PrimaryColor: cfg.ReportPrimaryColor,
+		LogoURL:      cfg.ReportLogoURL,
+		FooterText:   cfg.ReportFooterText,
+	}
+	handlers := httpx.NewAPIHandlers(orchestrator, queue, cfg.EvidenceMaxAge, evidenceOrder, normalizer, reportCaps, reportTheme, cfg.BatchMaxSize, cfg.AdminToken)
 
 	// Setup HTTP server
 	mux := http.NewServeMux()
 
-	// API routes
-	mux.HandleFunc("/v1/analyze", handlers.HandleAnalyze)
-	mux.HandleFunc("/v1/analyses/", handlers.HandleGetAnalysis)
-	mux.HandleFunc("/v1/analyses", handlers.HandleListAnalyses)
-	mux.HandleFunc("/v1/stats", handlers.HandleStats)
-	mux.HandleFunc("/health", handlers.HandleHealthCheck)
+	adminOnly := httpx.AdminMiddleware(cfg.AdminToken)
+	// Routes that run analyses or stream outlast the server write timeout
+	longRunning := httpx.WriteTimeoutMiddleware(cfg.HTTPLongWriteTimeout)
+	reverdict := longRunning(adminOnly(httpx.Methods{http.MethodPost: handlers.HandleReverdict}))
+	refresh := longRunning(httpx.Methods{http.MethodPost: handlers.HandleRefreshEvidence})
+	cancelRun := httpx.Methods{http.MethodDelete: handlers.HandleCancelAnalysis}
+	// An analysis can be read, edited or deleted; its reports, diff and sensitivity
+	// views are read-only
+	analysis := httpx.Methods{
+		http.MethodGet:    handlers.HandleGetAnalysis,
+		http.MethodPatch:  handlers.HandleUpdateAnalysis,
+		http.MethodDelete: handlers.HandleDeleteAnalysis,
+	}
+	analysisView := httpx.Methods{http.MethodGet: handlers.HandleGetAnalysis}
+
+	// API routes; each declares the methods it answers
+	mux.Handle("/v1/analyze", longRunning(httpx.Methods{http.MethodPost: handlers.HandleAnalyze}))
+	mux.Handle("/v1/analyze/stream", longRunning(httpx.Methods{http.MethodPost: handlers.HandleAnalyzeStream}))
+	mux.Handle("/v1/analyze/batch", longRunning(httpx.Methods{http.MethodPost: handlers.HandleAnalyzeBatch}))
+	// Actions share the analysis prefix; verdict regeneration is admin-only
+	mux.Handle("/v1/analyses/", httpx.AnalysisRoutes{
+		Analysis:  analysis,
+		View:      analysisView,
+		Reverdict: reverdict,
+		Refresh:   refresh,
+		Cancel:    cancelRun,
+	})
+	mux.Handle("/v1/analyses", httpx.Methods{http.MethodGet: handlers.HandleListAnalyses})
+	mux.Handle("/v1/evidence/", httpx.Methods{http.MethodGet: handlers.HandleGetEvidence})
+	mux.Handle("/v1/ideas/refine", longRunning(httpx.Methods{http.MethodPost: handlers.HandleRefineIdea}))
+	mux.Handle("/v1/stats", httpx.Methods{http.MethodGet: handlers.HandleStats})
+	mux.Handle("/v1/competitors", httpx.Methods{http.MethodGet: handlers.HandleListCompetitors})
+	mux.Handle("/v1/analytics", httpx.Methods{http.MethodGet: handlers.HandleAnalytics})
+	mux.Handle("/v1/insights", httpx.Methods{http.MethodGet: handlers.HandleInsights})
+	mux.Handle("/health", httpx.Methods{http.MethodGet: handlers.HandleHealthCheck})
+	mux.Handle("/health/ready", httpx.Methods{http.MethodGet: handlers.HandleReadiness})
+	mux.HandleFunc("/", handlers.HandleNotFound)
+
+	// Admin routes
+	mux.Handle("/v1/export", longRunning(adminOnly(httpx.Methods{http.MethodGet: handlers.HandleExport})))
+	mux.Handle("/v1/import", adminOnly(httpx.Methods{http.MethodPost: handlers.HandleImport}))
+	mux.Handle("/v1/audit", adminOnly(httpx.Methods{http.MethodGet: handlers.HandleAuditLog}))
+	mux.Handle("/v1/audit/verify", adminOnly(httpx.Methods{http.MethodGet: handlers.HandleVerifyAuditLog}))
+	mux.Handle("/v1/maintenance/mode", adminOnly(httpx.Methods{
+		http.MethodGet:  handlers.HandleMaintenanceMode,
+		http.MethodPost: handlers.HandleMaintenanceMode,
+	}))
+	mux.Handle("/v1/maintenance/cleanup", longRunning(adminOnly(httpx.Methods{
+		http.MethodGet:  handlers.HandleMaintenanceCleanup,
+		http.MethodPost: handlers.HandleMaintenanceCleanup,
+	})))
+
+	// Routes being retired are announced through response headers
+	routes := httpx.NewRouteRegistry()
+	deprecations, _ := cfg.APIDeprecations() // validated above
+	for _, d := range deprecations {
+		routes.Deprecate(d.Route, httpx.Deprecation{
+			Since:         d.SinceTime(),
+			Sunset:        d.SunsetTime(),
+			Link:          d.Link,
+			BeforeVersion: d.BeforeVersion,
+		})
+	}
 
 	// Apply middleware
 	var handler http.Handler = mux
+	handler = httpx.VersioningMiddleware(routes)(handler)
 	handler = httpx.AuthMiddleware(cfg.BearerToken)(handler)
+	if cfg.CompressionEnabled {
+		handler = httpx.GzipMiddleware(cfg.CompressionMinBytes)(handler)
+	}
 	handler = httpx.LoggingMiddleware(handler)
-	handler = httpx.CORSMiddleware(handler)
+	handler = httpx.CORSMiddleware(cfg.CORSAllowedOrigins)(handler)
+	if cfg.SecurityHeadersEnabled {
+		handler = httpx.SecurityHeadersMiddleware(cfg.HSTSMaxAge)(handler)
+	}
 
 	server := &http.Server{
 		Addr:    cfg.HTTPAddr,
 		Handler: handler,
 
-		// Timeouts
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 120 * time.Second, // Long timeout for analysis requests
-		IdleTimeout:  60 * time.Second,
+		// Timeouts; long-running routes replace the write timeout per request
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
 	}
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting RectAIfy API server on %s", cfg.HTTPAddr)
+		log.Printf("Starting %s API server on %s", report.ProductName, cfg.HTTPAddr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
 		}
@@ -115,12 +309,25 @@ func main() {
 	log.Println("Shutting down server...")
 
 	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Server shutdown error: %v", err)
 	}
+	// Let queued and running analyses finish
+	if err := queue.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Analysis queue shutdown error: %v", err)
+	}
+	// Then deliver the webhooks they raised; any left undelivered are dead-lettered
+	if dispatcher != nil {
+		if err := dispatcher.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Webhook dispatcher shutdown error: %v", err)
+		}
+	}
+
+	// Stop background workers
+	stopWorkers()
 
 	log.Println("Server stopped")
 }
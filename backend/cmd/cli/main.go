@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"rectaify/internal/analyzers"
@@ -16,11 +17,14 @@ import (
 	"rectaify/internal/config"
 	"rectaify/internal/evidence"
 	"rectaify/internal/llm"
+	"rectaify/internal/money"
 	"rectaify/internal/report"
 	"rectaify/internal/schema"
 	"rectaify/internal/score"
+	"rectaify/internal/scrub"
 	"rectaify/internal/search"
 	"rectaify/internal/store"
+	"rectaify/internal/taxonomy"
 	"rectaify/pkg/types"
 )
 
@@ -28,24 +32,32 @@ func main() {
 	var (
 		title      = flag.String("title", "", "Startup title (required)")
 		oneLiner   = flag.String("one-liner", "", "One-liner description (required)")
-		category   = flag.String("category", "", "Optional category")
-		location   = flag.String("location", "", "Optional location (country or region)")
+		category   = flag.String("category", "", "Optional category (defaults to DEFAULT_CATEGORY)")
+		location   = flag.String("location", "", "Optional location, country or region (defaults to DEFAULT_LOCATION)")
 		output     = flag.String("out", "", "Output file path (default: stdout)")
 		format     = flag.String("format", "markdown", "Output format: markdown, html, json")
+		formats    = flag.String("formats", "", "Comma-separated formats to write from one analysis into --out-dir, e.g. markdown,html,json")
+		outDir     = flag.String("out-dir", "", "Directory for the --formats reports (default: current directory)")
 		timeout    = flag.Duration("timeout", 60*time.Second, "Analysis timeout")
 		maxEvidence = flag.Int("max-evidence", 20, "Maximum evidence items to collect")
 		dbDSN      = flag.String("db", "", "Database DSN (uses config if not provided)")
+		diffAgainst = flag.String("diff", "", "Compare the new analysis against a previous analysis ID")
+		refreshID  = flag.String("refresh", "", "Re-gather evidence for a previous analysis ID instead of analyzing a new idea")
+		freshSearch = flag.Bool("fresh-search", false, "Bypass cached search results")
 		help       = flag.Bool("help", false, "Show help message")
 	)
 
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "RectAIfy CLI - Startup Idea Analysis Tool\n\n")
+		fmt.Fprintf(os.Stderr, "%s CLI - Startup Idea Analysis Tool\n\n", report.ProductName)
 		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s --title \"Loom\" --one-liner \"Agentic coding assistant\" --out report.md\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --title \"TaskAI\" --one-liner \"AI task automation\" --format html --out report.html\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --title \"TaskAI\" --one-liner \"AI task automation\" --formats markdown,html,json --out-dir reports\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --title \"TaskAI\" --one-liner \"AI task automation\" --diff <previous-analysis-id>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --refresh <previous-analysis-id> --fresh-search --out report.md\n", os.Args[0])
 	}
 
 	flag.Parse()
@@ -56,16 +68,30 @@ func main() {
 	}
 
 	// Validate required arguments
-	if *title == "" || *oneLiner == "" {
-		fmt.Fprintf(os.Stderr, "Error: --title and --one-liner are required\n\n")
+	if *refreshID == "" && (*title == "" || *oneLiner == "") {
+		fmt.Fprintf(os.Stderr, "Error: --title and --one-liner are required unless --refresh is given\n\n")
 		flag.Usage()
 		os.Exit(1)
 	}
 
 	// Validate format
-	if *format != "markdown" && *format != "html" && *format != "json" {
-		fmt.Fprintf(os.Stderr, "Error: --format must be one of: markdown, html, json\n")
-		os.Exit(1)
+	outputs := []string{*format}
+	if *formats != "" {
+		if *output != "" {
+			fmt.Fprintf(os.Stderr, "Error: --out cannot be combined with --formats; use --out-dir\n")
+			os.Exit(1)
+		}
+		outputs = parseFormats(*formats)
+		if len(outputs) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: --formats must list at least one format\n")
+			os.Exit(1)
+		}
+	}
+	for _, f := range outputs {
+		if _, ok := reportFiles[f]; !ok {
+			fmt.Fprintf(os.Stderr, "Error: formats must be one of: markdown, html, json\n")
+			os.Exit(1)
+		}
 	}
 
 	// Load configuration
@@ -81,36 +107,137 @@ func main() {
 	}
 
 	// Run analysis
-	result, err := runAnalysis(cfg, *title, *oneLiner, *category, *location, *timeout, *maxEvidence)
+	result, err := runAnalysis(cfg, *title, *oneLiner, *category, *location, *timeout, *maxEvidence, *refreshID, *freshSearch)
 	if err != nil {
 		log.Fatalf("Analysis failed: %v", err)
 	}
 
-	// Generate output
-	var content string
-	switch *format {
-	case "markdown":
-		builder := report.NewMarkdownBuilder()
-		content = builder.Build(result)
+	evidenceOrder, scorer, err := reportOrdering(cfg)
+	if err != nil {
+		log.Fatalf("Invalid report configuration: %v", err)
+	}
+
+	// Generate output; every format renders the same analysis
+	var saved []string
+	if *formats != "" {
+		for _, f := range outputs {
+			path := filepath.Join(*outDir, reportFiles[f])
+			if err := writeOutput(renderReport(f, result, cfg, evidenceOrder, scorer), path); err != nil {
+				log.Fatalf("Failed to write output: %v", err)
+			}
+			saved = append(saved, path)
+		}
+	} else {
+		if err := writeOutput(renderReport(*format, result, cfg, evidenceOrder, scorer), *output); err != nil {
+			log.Fatalf("Failed to write output: %v", err)
+		}
+		if *output != "" {
+			saved = append(saved, *output)
+		}
+	}
+
+	fmt.Printf("Analysis completed successfully. Overall score: %.1f/100\n", result.Verdict.OverallScore)
+	for _, path := range saved {
+		fmt.Printf("Report saved to: %s\n", path)
+	}
+
+	if *diffAgainst != "" {
+		diff, err := diffAnalyses(cfg, *diffAgainst, result)
+		if err != nil {
+			log.Fatalf("Diff failed: %v", err)
+		}
+		fmt.Println()
+		fmt.Print(diff)
+	}
+}
+
+// reportFiles maps each output format to its file name under --out-dir
+var reportFiles = map[string]string{
+	"markdown": "report.md",
+	"html":     "report.html",
+	"json":     "report.json",
+}
+
+// parseFormats splits a comma-separated format list, dropping blanks and repeats
+func parseFormats(list string) []string {
+	var formats []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(list, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" || seen[f] {
+			continue
+		}
+		seen[f] = true
+		formats = append(formats, f)
+	}
+	return formats
+}
+
+// renderReport renders the analysis in the given output format
+func renderReport(format string, result types.Analysis, cfg *config.Config, order report.EvidenceOrder, scorer report.EvidenceScorer) string {
+	switch format {
 	case "html":
-		builder := report.NewHTMLBuilder()
-		content = builder.Build(result)
+		return report.NewHTMLBuilder(order, scorer, reportCaps(cfg), reportTheme(cfg)).Build(result)
 	case "json":
-		content = formatJSON(result)
+		return formatJSON(result)
+	default:
+		return report.NewMarkdownBuilder(order, scorer, reportCaps(cfg)).Build(result)
 	}
+}
+
+// diffAnalyses loads a previous analysis and renders what changed in result since then
+func diffAnalyses(cfg *config.Config, baseID string, result types.Analysis) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	// Write output
-	if err := writeOutput(content, *output); err != nil {
-		log.Fatalf("Failed to write output: %v", err)
+	db, err := schema.InitDatabase(ctx, cfg.DatabaseDSN)
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize database: %w", err)
 	}
+	defer db.Close()
 
-	fmt.Printf("Analysis completed successfully. Overall score: %.1f/100\n", result.Verdict.OverallScore)
-	if *output != "" {
-		fmt.Printf("Report saved to: %s\n", *output)
+	base, err := store.NewRepository(db).GetAnalysisWithEvidence(ctx, baseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load analysis %s: %w", baseID, err)
 	}
+
+	builder := report.NewDiffBuilder()
+	return builder.Markdown(builder.Build(base, result)), nil
 }
 
-func runAnalysis(cfg *config.Config, title, oneLiner, category, location string, timeout time.Duration, maxEvidence int) (types.Analysis, error) {
+// reportOrdering returns the configured order of report evidence and a scorer
+// rating evidence with the configured source weights
+func reportOrdering(cfg *config.Config) (report.EvidenceOrder, report.EvidenceScorer, error) {
+	order, err := report.ParseEvidenceOrder(cfg.ReportEvidenceOrder)
+	if err != nil {
+		return "", nil, err
+	}
+	sourceWeights, err := cfg.SourceTypeWeights()
+	if err != nil {
+		return "", nil, err
+	}
+	return order, evidence.NewNormalizer(sourceWeights, cfg.DefaultSourceTypeWeight, nil, nil, nil, cfg.IncludePDFEvidence), nil
+}
+
+// reportCaps returns the configured number of items each report section lists
+func reportCaps(cfg *config.Config) report.SectionCaps {
+	return report.SectionCaps{
+		Competitors: cfg.ReportMaxCompetitors,
+		Risks:       cfg.ReportMaxRisks,
+		Barriers:    cfg.ReportMaxBarriers,
+		Graveyard:   cfg.ReportMaxGraveyard,
+	}
+}
+
+func reportTheme(cfg *config.Config) report.Theme {
+	return report.Theme{
+		PrimaryColor: cfg.ReportPrimaryColor,
+		LogoURL:      cfg.ReportLogoURL,
+		FooterText:   cfg.ReportFooterText,
+	}
+}
+
+func runAnalysis(cfg *config.Config, title, oneLiner, category, location string, timeout time.Duration, maxEvidence int, refreshID string, freshSearch bool) (types.Analysis, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout+30*time.Second) // Add buffer for setup
 	defer cancel()
 
@@ -127,29 +254,125 @@ func runAnalysis(cfg *config.Config, title, oneLiner, category, location string,
 	}
 
 	// Initialize components
-	llmClient := llm.NewClient(cfg.OpenAIAPIKey, cfg.OpenAIRPS, cfg.OpenAIBurst)
+	rootCAs, err := cfg.OutboundRootCAs()
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	killSwitch := llm.NewKillSwitch(cfg.MaintenanceMode) // switches every LLM call off
+	llmClient := llm.NewClient(llm.ClientConfig{
+		APIKey:      cfg.OpenAIAPIKey,
+		BaseURL:     cfg.OpenAIBaseURL,
+		APIVersion:  cfg.OpenAIAPIVersion,
+		Model:       cfg.OpenAIModel,
+		SearchModel: cfg.OpenAISearchModel,
+		FallbackModels:  cfg.OpenAIFallbackModels,
+		RPS:             cfg.OpenAIRPS,
+		Burst:           cfg.OpenAIBurst,
+		StrictRateLimit: cfg.OpenAIStrictRate,
+		TPM:             cfg.OpenAITPM,
+		MaxRetries:      cfg.OpenAIMaxRetries,
+		RequestTimeout:  cfg.OpenAIRequestTimeout,
+		KillSwitch:      killSwitch,
+		RootCAs:         rootCAs,
+	})
 	
-	evidenceCache, err := cache.NewEvidenceCache(db, cfg.CacheLRUSize, cfg.CacheTTL)
+	cacheTTLs, err := cfg.EvidenceCacheTTLs()
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	evidenceCache, err := cache.NewEvidenceCache(db, cfg.CacheLRUSize, cfg.CacheTTL, cacheTTLs)
 	if err != nil {
 		return types.Analysis{}, fmt.Errorf("failed to initialize evidence cache: %w", err)
 	}
 
-	planner := search.NewPlanner(cfg.MaxQueries)
-	executor := search.NewExecutor(llmClient, evidenceCache, cfg.AnalysisTimeout)
-	normalizer := evidence.NewNormalizer()
-	calculator := score.NewCalculator(nil) // Use default weights
-	coordinator := analyzers.NewCoordinator(llmClient, calculator)
+	planner := search.NewPlanner(cfg.MaxQueries, cfg.QuerySimilarity, cfg.PlannerStopWords, cfg.PlannerMaxKeyTerms)
+	executor := search.NewExecutor(llmClient, evidenceCache, cfg.SearchConcurrency, cfg.SearchOverlap)
+	sourceWeights, err := cfg.SourceTypeWeights()
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	var scrubber *scrub.Scrubber
+	if cfg.ScrubEnabled {
+		scrubber = scrub.NewScrubber(cfg.ScrubWordlist)
+	}
+	var redirects *evidence.RedirectResolver
+	if cfg.ResolveRedirects {
+		redirects = evidence.NewRedirectResolver(cfg.RedirectTimeout, cfg.RedirectConcurrency, rootCAs)
+	}
+	sourceCaps, err := cfg.SourceTypeCaps()
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	normalizer := evidence.NewNormalizer(sourceWeights, cfg.DefaultSourceTypeWeight, scrubber, redirects, sourceCaps, cfg.IncludePDFEvidence)
+	categories := taxonomy.New(taxonomy.DefaultCategories())
+	configuredCategories, err := cfg.CategoryTaxonomy()
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	if configuredCategories != nil {
+		categories = taxonomy.New(configuredCategories)
+	}
+	promptHints, err := cfg.CategoryPromptHints()
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	snippetLimits, err := cfg.SnippetLimits()
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	fxOverrides, err := cfg.FXRates()
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	evidencePolicy, err := analyzers.ParseEvidencePolicy(cfg.EvidencePolicy)
+	if err != nil {
+		return types.Analysis{}, err
+	}
+	// Default weights with the configured no-failure graveyard baselines
+	calculator := score.NewCalculator(nil, &score.GraveyardBaselines{
+		NoFailures: cfg.GraveyardSearched,
+		Unsearched: cfg.GraveyardUnsearched,
+	})
+	coordinator := analyzers.NewCoordinator(llmClient, calculator, cfg.AnalyzerConcurrency, promptHints, snippetLimits, money.DefaultRates().Merge(fxOverrides), evidencePolicy, analyzers.VerdictPayload{
+		MaxEvidence: cfg.VerdictMaxEvidence,
+		Condensed:   cfg.VerdictCondensed,
+		Timeout:     cfg.VerdictRequestTimeout,
+		Strict:      cfg.VerdictStrict,
+	}, nil, cfg.AnalyzerTimeout) // every CLI run performs a fresh analysis
 	repository := store.NewRepository(db)
 
-	orchestrator := app.NewOrchestrator(
-		planner,
-		executor,
-		normalizer,
-		coordinator,
-		repository,
-		maxEvidence,
-		timeout,
-	)
+	// CLI analyses are given title and one-liner directly, run fresh every
+	// time and have no lifecycle event subscribers
+	orchestrator := app.NewOrchestrator(app.OrchestratorOptions{
+		Planner:          planner,
+		Executor:         executor,
+		Normalizer:       normalizer,
+		Coordinator:      coordinator,
+		Repository:       repository,
+		Scrubber:         scrubber,
+		KillSwitch:       killSwitch,
+		Categories:       categories,
+		Defaults:         app.IdeaDefaults{Category: cfg.DefaultCategory, Location: cfg.DefaultLocation},
+		MaxEvidence:      maxEvidence,
+		MaxEvidenceBytes: cfg.MaxEvidenceBytes,
+		MinEvidence:      cfg.MinEvidence(),
+		RetryBudget:      cfg.OpenAIRetryBudget,
+		AnalysisTimeout:  timeout,
+		SearchTimeout:    cfg.SearchTimeout,
+		MinTimeout:       cfg.MinAnalysisTimeout,
+		MaxTimeout:       cfg.MaxAnalysisTimeout,
+		StaleEvidenceAge: cfg.StaleEvidenceAge,
+		EvidenceMaxAge:   cfg.EvidenceCutoffAge,
+		KeepUndated:      cfg.KeepUndatedEvidence,
+		NextSteps:        cfg.NextStepsEnabled,
+		ReviewThreshold:  cfg.ReviewScoreThreshold,
+		MinSourceDomains: cfg.MinSourceDomains,
+		IdeaExpansion:    cfg.IdeaExpansionEnabled,
+		MinCoherence:     cfg.MinCoherenceScore,
+		DebugMeta:        cfg.AnalyzerDebugMeta,
+		MinInsights:      cfg.InsightsMinAnalyses,
+		AuditLog:         cfg.AuditLog,
+	})
 
 	// Create analysis request
 	idea := types.IdeaInput{
@@ -172,9 +395,22 @@ func runAnalysis(cfg *config.Config, title, oneLiner, category, location string,
 			MaxEvidence: maxEvidence,
 			Location:    analysisLocation,
 			Timeout:     &timeout,
+			FreshSearch: freshSearch,
 		},
 	}
 
+	if refreshID != "" {
+		fmt.Printf("Refreshing evidence for analysis: %s\n", refreshID)
+		fmt.Printf("Fresh search: %v\n", freshSearch)
+		fmt.Println()
+
+		analysisID, err := orchestrator.RefreshEvidence(ctx, refreshID, freshSearch)
+		if err != nil {
+			return types.Analysis{}, fmt.Errorf("refresh failed: %w", err)
+		}
+		return orchestrator.GetAnalysis(ctx, analysisID)
+	}
+
 	// Run analysis
 	fmt.Printf("Analyzing startup idea: %s\n", title)
 	fmt.Printf("Description: %s\n", oneLiner)
@@ -182,7 +418,7 @@ func runAnalysis(cfg *config.Config, title, oneLiner, category, location string,
 	fmt.Printf("Max evidence: %d\n", maxEvidence)
 	fmt.Println()
 
-	analysisID, err := orchestrator.AnalyzeIdea(ctx, request)
+	analysisID, _, err := orchestrator.AnalyzeIdea(ctx, request)
 	if err != nil {
 		return types.Analysis{}, fmt.Errorf("analysis failed: %w", err)
 	}
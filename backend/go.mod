@@ -8,6 +8,7 @@ require (
 	github.com/joho/godotenv v1.5.1
 	golang.org/x/sync v0.6.0
 	golang.org/x/time v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
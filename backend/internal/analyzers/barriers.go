@@ -22,7 +22,7 @@ func NewBarriersAnalyzer(llmClient *llm.Client) *BarriersAnalyzer {
 }
 
 // Analyze performs barrier analysis
-func (ba *BarriersAnalyzer) Analyze(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.BarrierAnalysis, error) {
+func (ba *BarriersAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, promptSuffix string) (types.BarrierAnalysis, error) {
 	systemPrompt := `You are a business execution expert. Analyze the provided startup idea and evidence to identify execution barriers.
 
 CRITICAL REQUIREMENTS:
@@ -48,11 +48,6 @@ Rate barrier weight based on Evidence:
 
 Be evidence-based - only identify barriers you can substantiate with provided Evidence.`
 
-	userPrompt := map[string]interface{}{
-		"idea":     idea,
-		"evidence": evidence,
-	}
-
 	schema := []byte(`{
 		"type": "object",
 		"properties": {
@@ -89,7 +84,9 @@ Be evidence-based - only identify barriers you can substantiate with provided Ev
 		"additionalProperties": false
 	}`)
 
-	response, err := ba.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	systemPrompt = appendPromptSuffix(systemPrompt, promptSuffix)
+
+	response, err := ba.llmClient.ConstrainedJSON(ctx, systemPrompt, input.UserPrompt(DimensionBarriers), schema)
 	if err != nil {
 		return types.BarrierAnalysis{}, fmt.Errorf("barriers analysis failed: %w", err)
 	}
@@ -99,7 +96,10 @@ Be evidence-based - only identify barriers you can substantiate with provided Ev
 		return types.BarrierAnalysis{}, fmt.Errorf("failed to parse barriers analysis response: %w", err)
 	}
 
-	result = ba.validateEvidenceIDs(result, evidence)
+	result = ba.validateEvidenceIDs(result, input.Evidence)
+	for i := range result.Barriers {
+		result.Barriers[i].Type = barrierTypeEnum.normalize(result.Barriers[i].Type)
+	}
 	return result, nil
 }
 
@@ -0,0 +1,81 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"rectaify/internal/llm"
+	"rectaify/pkg/types"
+)
+
+// CoherenceAnalyzer checks that the findings of an analysis are about the
+// idea that was asked about, catching analyzers that drifted onto a related
+// but different product
+type CoherenceAnalyzer struct {
+	llmClient *llm.Client
+}
+
+// NewCoherenceAnalyzer creates a new coherence analyzer
+func NewCoherenceAnalyzer(llmClient *llm.Client) *CoherenceAnalyzer {
+	return &CoherenceAnalyzer{
+		llmClient: llmClient,
+	}
+}
+
+// Check scores from 0 to 1 how closely the dimension findings match the
+// idea, naming the dimensions that drifted. Only the findings are sent, not
+// the evidence, which keeps the call small.
+func (ca *CoherenceAnalyzer) Check(ctx context.Context, analysis types.Analysis) (types.Coherence, error) {
+	systemPrompt := `You are reviewing a startup analysis for relevance before it is published. Judge whether its findings are about the idea that was submitted, not a related product, a different market or a different customer.
+
+CRITICAL REQUIREMENTS:
+1. Output ONLY valid JSON matching the required schema
+2. score: from 0 (the findings are about something else) to 1 (every finding is about this idea)
+3. off_topic: the dimensions whose findings are mostly about something other than the idea; empty if none
+4. reason: one sentence explaining the score, naming what the off-topic findings are about instead
+
+Judge relevance only, not whether the findings are positive, negative or correct. Competitors and failed companies in the same space as the idea are relevant even if they differ in detail.`
+
+	userPrompt := map[string]interface{}{
+		"idea": analysis.Idea,
+		"findings": map[string]interface{}{
+			DimensionMarket:    analysis.Market,
+			DimensionProblem:   analysis.Problem,
+			DimensionBarriers:  analysis.Barriers,
+			DimensionExecution: analysis.Execution,
+			DimensionRisks:     analysis.Risks,
+			DimensionGraveyard: analysis.Graveyard,
+		},
+		"recommendation": analysis.Verdict.Recommendation,
+	}
+
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"score": {"type": "number", "minimum": 0, "maximum": 1},
+			"off_topic": {
+				"type": "array",
+				"items": {"type": "string", "enum": ["market", "problem", "barriers", "execution", "risks", "graveyard"]}
+			},
+			"reason": {"type": "string"}
+		},
+		"required": ["score", "off_topic", "reason"],
+		"additionalProperties": false
+	}`)
+
+	response, err := ca.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	if err != nil {
+		return types.Coherence{}, fmt.Errorf("coherence check failed: %w", err)
+	}
+
+	var coherence types.Coherence
+	if err := json.Unmarshal(response, &coherence); err != nil {
+		return types.Coherence{}, fmt.Errorf("failed to parse coherence response: %w", err)
+	}
+
+	coherence.Score = min(max(coherence.Score, 0), 1)
+	coherence.Reason = strings.TrimSpace(coherence.Reason)
+	return coherence, nil
+}
@@ -2,13 +2,17 @@ package analyzers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"rectaify/internal/cache"
 	"rectaify/internal/llm"
+	"rectaify/internal/money"
 	"rectaify/internal/score"
 	"rectaify/pkg/types"
 )
@@ -22,130 +26,85 @@ type Coordinator struct {
 	risksAnalyzer      *RisksAnalyzer
 	graveyardAnalyzer  *GraveyardAnalyzer
 	verdictAnalyzer    *VerdictAnalyzer
+	summaryAnalyzer    *SummaryAnalyzer
+	refineAnalyzer     *RefineAnalyzer
+	nextStepsAnalyzer  *NextStepsAnalyzer
+	ideaExpander       *IdeaExpander
+	coherenceAnalyzer  *CoherenceAnalyzer
+	calculator         *score.Calculator
+	concurrency        int // max analyzers running at once
+	promptHints        CategoryPromptHints
+	snippetLimits      SnippetLimits
+	evidencePolicy     EvidencePolicy       // handling of items citing no valid evidence
+	analyzerCache      *cache.AnalyzerCache // nil when dimension results are not cached
+	analyzerTimeout    time.Duration        // per dimension analyzer; 0 shares the analysis deadline
 }
 
-// NewCoordinator creates a new analyzer coordinator
-func NewCoordinator(llmClient *llm.Client, calculator *score.Calculator) *Coordinator {
+// NewCoordinator creates a new analyzer coordinator. concurrency bounds how
+// many analyzers run at once; values below 1 run them all simultaneously.
+// promptHints and snippetLimits may be nil; nil fxRates uses money.DefaultRates.
+// evidencePolicy decides what happens to items citing no valid evidence. A nil
+// analyzerCache runs the dimension analyzers for every analysis. A positive
+// analyzerTimeout bounds each dimension analyzer separately.
+func NewCoordinator(llmClient *llm.Client, calculator *score.Calculator, concurrency int, promptHints CategoryPromptHints, snippetLimits SnippetLimits, fxRates money.Rates, evidencePolicy EvidencePolicy, verdictPayload VerdictPayload, analyzerCache *cache.AnalyzerCache, analyzerTimeout time.Duration) *Coordinator {
 	return &Coordinator{
-		marketAnalyzer:     NewMarketAnalyzer(llmClient),
+		marketAnalyzer:     NewMarketAnalyzer(llmClient, fxRates),
 		problemAnalyzer:    NewProblemAnalyzer(llmClient),
 		barriersAnalyzer:   NewBarriersAnalyzer(llmClient),
 		executionAnalyzer:  NewExecutionAnalyzer(llmClient),
 		risksAnalyzer:      NewRisksAnalyzer(llmClient),
 		graveyardAnalyzer:  NewGraveyardAnalyzer(llmClient),
-		verdictAnalyzer:    NewVerdictAnalyzer(llmClient, calculator),
+		verdictAnalyzer:    NewVerdictAnalyzer(llmClient, calculator, snippetLimits.Limit(DimensionVerdict), verdictPayload),
+		summaryAnalyzer:    NewSummaryAnalyzer(llmClient),
+		refineAnalyzer:     NewRefineAnalyzer(llmClient, snippetLimits.Limit(DimensionVerdict)),
+		nextStepsAnalyzer:  NewNextStepsAnalyzer(llmClient, snippetLimits.Limit(DimensionVerdict)),
+		ideaExpander:       NewIdeaExpander(llmClient),
+		coherenceAnalyzer:  NewCoherenceAnalyzer(llmClient),
+		calculator:         calculator,
+		concurrency:        concurrency,
+		promptHints:        promptHints,
+		snippetLimits:      snippetLimits,
+		evidencePolicy:     evidencePolicy,
+		analyzerCache:      analyzerCache,
+		analyzerTimeout:    analyzerTimeout,
 	}
 }
 
-// AnalyzeAll runs all analyzers in parallel and returns complete analysis
-func (c *Coordinator) AnalyzeAll(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.Analysis, error) {
-	// Run all analyzers in parallel except verdict (which depends on others)
-	var market types.MarketAnalysis
-	var problem types.ProblemAnalysis
-	var barriers types.BarrierAnalysis
-	var execution types.ExecutionAnalysis
-	var risks types.RiskAnalysis
-	var graveyard types.GraveyardAnalysis
+// AnalyzeAll runs all analyzers in parallel and returns complete analysis.
+// postmortems describes how thoroughly failures were searched for and is
+// attached to the graveyard analysis so the verdict can weigh an empty graveyard.
+// Dimension results are reused from the analyzer cache when the idea, evidence
+// and prompt settings are unchanged, unless fresh is set.
+func (c *Coordinator) AnalyzeAll(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence, postmortems *types.PostmortemSearch, fresh bool) (types.Analysis, error) {
+	// Serialize the idea and evidence once for all analyzers
+	input, err := NewAnalysisInput(idea, evidence, c.snippetLimits)
+	if err != nil {
+		return types.Analysis{}, err
+	}
 
-	var mu sync.Mutex
+	// Run all analyzers in parallel except verdict (which depends on others)
+	cacheKey := input.CacheKey(c.promptHints)
+	dimensions, cacheHit := c.cachedDimensions(ctx, cacheKey, fresh)
 	var analysisErrors []error
-
-	g, ctx := errgroup.WithContext(ctx)
-
-	// Market analysis
-	g.Go(func() error {
-		result, err := c.marketAnalyzer.Analyze(ctx, idea, evidence)
+	if cacheHit {
+		reportDimensions(ctx, dimensions)
+	} else {
+		dimensions, analysisErrors, err = c.analyzeDimensions(ctx, input)
 		if err != nil {
-			mu.Lock()
-			analysisErrors = append(analysisErrors, fmt.Errorf("market analysis failed: %w", err))
-			mu.Unlock()
-			return nil // Don't fail the entire group
+			return types.Analysis{}, err
 		}
-		mu.Lock()
-		market = result
-		mu.Unlock()
-		return nil
-	})
-
-	// Problem analysis
-	g.Go(func() error {
-		result, err := c.problemAnalyzer.Analyze(ctx, idea, evidence)
-		if err != nil {
-			mu.Lock()
-			analysisErrors = append(analysisErrors, fmt.Errorf("problem analysis failed: %w", err))
-			mu.Unlock()
-			return nil
-		}
-		mu.Lock()
-		problem = result
-		mu.Unlock()
-		return nil
-	})
-
-	// Barriers analysis
-	g.Go(func() error {
-		result, err := c.barriersAnalyzer.Analyze(ctx, idea, evidence)
-		if err != nil {
-			mu.Lock()
-			analysisErrors = append(analysisErrors, fmt.Errorf("barriers analysis failed: %w", err))
-			mu.Unlock()
-			return nil
-		}
-		mu.Lock()
-		barriers = result
-		mu.Unlock()
-		return nil
-	})
-
-	// Execution analysis
-	g.Go(func() error {
-		result, err := c.executionAnalyzer.Analyze(ctx, idea, evidence)
-		if err != nil {
-			mu.Lock()
-			analysisErrors = append(analysisErrors, fmt.Errorf("execution analysis failed: %w", err))
-			mu.Unlock()
-			return nil
+		// Only complete results are reused
+		if len(analysisErrors) == 0 {
+			c.cacheDimensions(ctx, cacheKey, dimensions)
 		}
-		mu.Lock()
-		execution = result
-		mu.Unlock()
-		return nil
-	})
-
-	// Risks analysis
-	g.Go(func() error {
-		result, err := c.risksAnalyzer.Analyze(ctx, idea, evidence)
-		if err != nil {
-			mu.Lock()
-			analysisErrors = append(analysisErrors, fmt.Errorf("risks analysis failed: %w", err))
-			mu.Unlock()
-			return nil
-		}
-		mu.Lock()
-		risks = result
-		mu.Unlock()
-		return nil
-	})
+	}
+	market, problem, barriers := dimensions.Market, dimensions.Problem, dimensions.Barriers
+	execution, risks, graveyard := dimensions.Execution, dimensions.Risks, dimensions.Graveyard
 
-	// Graveyard analysis
-	g.Go(func() error {
-		result, err := c.graveyardAnalyzer.Analyze(ctx, idea, evidence)
-		if err != nil {
-			mu.Lock()
-			analysisErrors = append(analysisErrors, fmt.Errorf("graveyard analysis failed: %w", err))
-			mu.Unlock()
-			return nil
-		}
-		mu.Lock()
-		graveyard = result
-		mu.Unlock()
-		return nil
-	})
+	graveyard.Search = postmortems
 
-	// Wait for all analyzers to complete
-	if err := g.Wait(); err != nil {
-		return types.Analysis{}, err
-	}
+	// Flag or drop items no provided evidence supports, before they are scored
+	unsupported := enforceEvidence(c.evidencePolicy, &market, &barriers, &risks, &graveyard)
 
 	// Create preliminary analysis for verdict
 	preliminaryAnalysis := types.Analysis{
@@ -160,11 +119,10 @@ func (c *Coordinator) AnalyzeAll(ctx context.Context, idea types.IdeaInput, evid
 	}
 
 	// Run verdict analysis
-	verdict, err := c.verdictAnalyzer.Analyze(ctx, preliminaryAnalysis)
+	// A failed verdict still carries the calculated scores, marked as a fallback
+	verdict, err := c.verdictAnalyzer.Analyze(llm.WithModelLabel(ctx, DimensionVerdict), preliminaryAnalysis)
 	if err != nil {
 		analysisErrors = append(analysisErrors, fmt.Errorf("verdict analysis failed: %w", err))
-		// Use empty verdict if it fails
-		verdict = types.Viability{}
 	}
 
 	// Final analysis
@@ -181,45 +139,230 @@ func (c *Coordinator) AnalyzeAll(ctx context.Context, idea types.IdeaInput, evid
 		Partial:   len(analysisErrors) > 0,
 	}
 
+	// Condense everything into a short TL;DR
+	finalAnalysis.Summary = c.summaryAnalyzer.Analyze(llm.WithModelLabel(ctx, "summary"), finalAnalysis)
+
 	// Include error information in meta if there were issues
 	if len(analysisErrors) > 0 {
-		errorMeta := map[string]interface{}{
-			"errors": analysisErrors,
-		}
-		if metaBytes, err := json.Marshal(errorMeta); err == nil {
-			finalAnalysis.Meta = metaBytes
+		errorMessages := make([]string, len(analysisErrors))
+		for i, analysisErr := range analysisErrors {
+			errorMessages[i] = analysisErr.Error()
 		}
+		finalAnalysis.SetMeta("errors", errorMessages)
+	}
+
+	if unsupported.Total() > 0 {
+		finalAnalysis.SetMeta("unsupported_claims", unsupported)
 	}
 
+	// Record how much analyzer input was sent
+	finalAnalysis.SetMeta("prompt_stats", input.Stats())
+	if cacheHit {
+		finalAnalysis.SetMeta("analyzer_cache_hit", true)
+	}
+
+	// Record the weights so later comparisons can tell if scoring changed
+	finalAnalysis.SetMeta("score_weights", c.calculator.Weights())
+
 	return finalAnalysis, nil
 }
 
+// analyzeDimensions runs the six dimension analyzers in parallel. Each runs
+// under its own timeout, when one is configured, so a slow dimension fails on
+// its own instead of using up the time the others need. A failed analyzer
+// leaves its dimension empty and is reported in the returned errors.
+func (c *Coordinator) analyzeDimensions(ctx context.Context, input *AnalysisInput) (types.DimensionResults, []error, error) {
+	var results types.DimensionResults
+	idea := input.Idea
+
+	var mu sync.Mutex
+	var analysisErrors []error
+
+	g, ctx := errgroup.WithContext(ctx)
+	if c.concurrency > 0 {
+		// Run in waves so a tight OpenAI rate limit sees a smooth request pattern
+		g.SetLimit(c.concurrency)
+	}
+
+	// run starts one analyzer. Each writes only its own field of results.
+	// Failures are collected rather than returned so they don't cancel the
+	// other analyzers.
+	run := func(dimension string, analyze func(ctx context.Context, hint string) (interface{}, error)) {
+		g.Go(func() error {
+			dimensionCtx, cancel := ctx, context.CancelFunc(func() {})
+			if c.analyzerTimeout > 0 {
+				dimensionCtx, cancel = context.WithTimeout(ctx, c.analyzerTimeout)
+			}
+			defer cancel()
+
+			dimensionCtx = llm.WithModelLabel(dimensionCtx, dimension)
+			result, err := analyze(dimensionCtx, c.promptHints.Hint(idea.Category, dimension))
+			if err != nil && errors.Is(dimensionCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+				err = fmt.Errorf("timed out after %s: %w", c.analyzerTimeout, err)
+			}
+			reportDimension(ctx, dimension, result, err)
+			if err == nil {
+				return nil
+			}
+			mu.Lock()
+			analysisErrors = append(analysisErrors, fmt.Errorf("%s analysis failed: %w", dimension, err))
+			mu.Unlock()
+			return nil // Don't fail the entire group
+		})
+	}
+
+	run(DimensionMarket, func(ctx context.Context, hint string) (interface{}, error) {
+		var err error
+		results.Market, err = c.marketAnalyzer.Analyze(ctx, input, hint)
+		return results.Market, err
+	})
+	run(DimensionProblem, func(ctx context.Context, hint string) (interface{}, error) {
+		var err error
+		results.Problem, err = c.problemAnalyzer.Analyze(ctx, input, hint)
+		return results.Problem, err
+	})
+	run(DimensionBarriers, func(ctx context.Context, hint string) (interface{}, error) {
+		var err error
+		results.Barriers, err = c.barriersAnalyzer.Analyze(ctx, input, hint)
+		return results.Barriers, err
+	})
+	run(DimensionExecution, func(ctx context.Context, hint string) (interface{}, error) {
+		var err error
+		results.Execution, err = c.executionAnalyzer.Analyze(ctx, input, hint)
+		return results.Execution, err
+	})
+	run(DimensionRisks, func(ctx context.Context, hint string) (interface{}, error) {
+		var err error
+		results.Risks, err = c.risksAnalyzer.Analyze(ctx, input, hint)
+		return results.Risks, err
+	})
+	run(DimensionGraveyard, func(ctx context.Context, hint string) (interface{}, error) {
+		var err error
+		results.Graveyard, err = c.graveyardAnalyzer.Analyze(ctx, input, hint)
+		return results.Graveyard, err
+	})
+
+	// Wait for all analyzers to complete
+	if err := g.Wait(); err != nil {
+		return types.DimensionResults{}, nil, err
+	}
+	return results, analysisErrors, nil
+}
+
+// cachedDimensions returns dimension results cached for the key, unless fresh
+// results were asked for or no analyzer cache is configured
+func (c *Coordinator) cachedDimensions(ctx context.Context, key string, fresh bool) (types.DimensionResults, bool) {
+	if c.analyzerCache == nil || fresh {
+		return types.DimensionResults{}, false
+	}
+	results, found, err := c.analyzerCache.GetDimensions(ctx, key)
+	if err != nil {
+		log.Printf("Analyzer cache lookup failed: %v", err)
+		return types.DimensionResults{}, false
+	}
+	return results, found
+}
+
+// cacheDimensions stores dimension results for reuse by later analyses with
+// the same inputs. Failures are logged; caching is best effort.
+func (c *Coordinator) cacheDimensions(ctx context.Context, key string, results types.DimensionResults) {
+	if c.analyzerCache == nil {
+		return
+	}
+	if err := c.analyzerCache.SetDimensions(ctx, key, results); err != nil {
+		log.Printf("Analyzer cache write failed: %v", err)
+	}
+}
+
+// Reverdict recomputes the verdict of a stored analysis from its dimension
+// results and evidence, without re-running the dimension analyzers
+func (c *Coordinator) Reverdict(ctx context.Context, analysis types.Analysis) (types.Viability, error) {
+	return c.verdictAnalyzer.Analyze(ctx, analysis)
+}
+
+// Refine suggests changes to an analyzed idea that address its lowest-scoring dimensions
+func (c *Coordinator) Refine(ctx context.Context, analysis types.Analysis) ([]types.DimensionScore, []types.Refinement, error) {
+	return c.refineAnalyzer.Suggest(ctx, analysis)
+}
+
+// PlanNextSteps proposes a validation plan for an analysis's weakest dimensions
+func (c *Coordinator) PlanNextSteps(ctx context.Context, analysis types.Analysis) ([]types.ValidationStep, error) {
+	return c.nextStepsAnalyzer.Plan(llm.WithModelLabel(ctx, "next_steps"), analysis)
+}
+
+// ExpandIdea reads a vague idea as a fuller description to plan searches from
+func (c *Coordinator) ExpandIdea(ctx context.Context, idea types.IdeaInput) (types.IdeaExpansion, error) {
+	return c.ideaExpander.Expand(llm.WithModelLabel(ctx, "idea_expansion"), idea)
+}
+
+// CheckCoherence scores how closely an analysis's findings match its idea
+func (c *Coordinator) CheckCoherence(ctx context.Context, analysis types.Analysis) (types.Coherence, error) {
+	return c.coherenceAnalyzer.Check(llm.WithModelLabel(ctx, "coherence"), analysis)
+}
+
+// Sensitivity measures how robust an analysis's overall score is, weighting
+// dimensions as they were when the analysis was scored
+func (c *Coordinator) Sensitivity(analysis types.Analysis, delta float64) types.Sensitivity {
+	calculator := c.calculator
+	if weights, ok := score.WeightsFromMeta(analysis.Meta); ok {
+		calculator = score.NewCalculator(&weights, nil)
+	}
+
+	sensitivity := calculator.Sensitivity(analysis.Verdict, delta)
+	sensitivity.AnalysisID = analysis.ID
+	return sensitivity
+}
+
 // AnalyzeMarket runs only market analysis (for testing/debugging)
 func (c *Coordinator) AnalyzeMarket(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.MarketAnalysis, error) {
-	return c.marketAnalyzer.Analyze(ctx, idea, evidence)
+	input, err := NewAnalysisInput(idea, evidence, c.snippetLimits)
+	if err != nil {
+		return types.MarketAnalysis{}, err
+	}
+	return c.marketAnalyzer.Analyze(ctx, input, c.promptHints.Hint(idea.Category, DimensionMarket))
 }
 
 // AnalyzeProblem runs only problem analysis (for testing/debugging)
 func (c *Coordinator) AnalyzeProblem(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.ProblemAnalysis, error) {
-	return c.problemAnalyzer.Analyze(ctx, idea, evidence)
+	input, err := NewAnalysisInput(idea, evidence, c.snippetLimits)
+	if err != nil {
+		return types.ProblemAnalysis{}, err
+	}
+	return c.problemAnalyzer.Analyze(ctx, input, c.promptHints.Hint(idea.Category, DimensionProblem))
 }
 
 // AnalyzeBarriers runs only barriers analysis (for testing/debugging)
 func (c *Coordinator) AnalyzeBarriers(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.BarrierAnalysis, error) {
-	return c.barriersAnalyzer.Analyze(ctx, idea, evidence)
+	input, err := NewAnalysisInput(idea, evidence, c.snippetLimits)
+	if err != nil {
+		return types.BarrierAnalysis{}, err
+	}
+	return c.barriersAnalyzer.Analyze(ctx, input, c.promptHints.Hint(idea.Category, DimensionBarriers))
 }
 
 // AnalyzeExecution runs only execution analysis (for testing/debugging)
 func (c *Coordinator) AnalyzeExecution(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.ExecutionAnalysis, error) {
-	return c.executionAnalyzer.Analyze(ctx, idea, evidence)
+	input, err := NewAnalysisInput(idea, evidence, c.snippetLimits)
+	if err != nil {
+		return types.ExecutionAnalysis{}, err
+	}
+	return c.executionAnalyzer.Analyze(ctx, input, c.promptHints.Hint(idea.Category, DimensionExecution))
 }
 
 // AnalyzeRisks runs only risks analysis (for testing/debugging)
 func (c *Coordinator) AnalyzeRisks(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.RiskAnalysis, error) {
-	return c.risksAnalyzer.Analyze(ctx, idea, evidence)
+	input, err := NewAnalysisInput(idea, evidence, c.snippetLimits)
+	if err != nil {
+		return types.RiskAnalysis{}, err
+	}
+	return c.risksAnalyzer.Analyze(ctx, input, c.promptHints.Hint(idea.Category, DimensionRisks))
 }
 
 // AnalyzeGraveyard runs only graveyard analysis (for testing/debugging)
 func (c *Coordinator) AnalyzeGraveyard(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.GraveyardAnalysis, error) {
-	return c.graveyardAnalyzer.Analyze(ctx, idea, evidence)
+	input, err := NewAnalysisInput(idea, evidence, c.snippetLimits)
+	if err != nil {
+		return types.GraveyardAnalysis{}, err
+	}
+	return c.graveyardAnalyzer.Analyze(ctx, input, c.promptHints.Hint(idea.Category, DimensionGraveyard))
 }
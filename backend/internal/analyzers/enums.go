@@ -0,0 +1,110 @@
+package analyzers
+
+import (
+	"log"
+	"strings"
+)
+
+// enumField lists the canonical values of an enum the analyzers ask the LLM
+// for, and synonyms seen in place of them. Strict schema mode should prevent
+// drift, but non-strict providers and repaired responses still produce it,
+// and the calculator scores any value it doesn't know as a neutral default.
+type enumField struct {
+	name      string
+	canonical []string
+	synonyms  map[string]string
+}
+
+var (
+	marketStageEnum = enumField{
+		name:      "market_stage",
+		canonical: []string{"early", "growing", "mature", "declining"},
+		synonyms: map[string]string{
+			"emerging":    "early",
+			"nascent":     "early",
+			"new":         "early",
+			"early stage": "early",
+			"growth":      "growing",
+			"expanding":   "growing",
+			"scaling":     "growing",
+			"developing":  "growing",
+			"saturated":   "mature",
+			"established": "mature",
+			"stable":      "mature",
+			"crowded":     "mature",
+			"shrinking":   "declining",
+			"contracting": "declining",
+			"dying":       "declining",
+			"decline":     "declining",
+		},
+	}
+
+	capitalRequirementEnum = enumField{
+		name:      "capital_requirement",
+		canonical: []string{"low", "medium", "high", "very high"},
+		synonyms: map[string]string{
+			"minimal":     "low",
+			"small":       "low",
+			"moderate":    "medium",
+			"mid":         "medium",
+			"large":       "high",
+			"significant": "high",
+			"very_high":   "very high",
+			"very-high":   "very high",
+			"veryhigh":    "very high",
+			"extreme":     "very high",
+			"massive":     "very high",
+		},
+	}
+
+	talentRarityEnum = enumField{
+		name:      "talent_rarity",
+		canonical: []string{"common", "available", "scarce", "rare"},
+		synonyms: map[string]string{
+			"abundant":    "common",
+			"plentiful":   "common",
+			"moderate":    "available",
+			"findable":    "available",
+			"specialized": "available",
+			"limited":     "scarce",
+			"uncommon":    "scarce",
+			"very rare":   "rare",
+			"very scarce": "rare",
+		},
+	}
+
+	barrierTypeEnum = enumField{
+		name:      "barrier type",
+		canonical: []string{"regulation", "supply", "distribution", "trust", "tech"},
+		synonyms: map[string]string{
+			"regulatory":    "regulation",
+			"legal":         "regulation",
+			"compliance":    "regulation",
+			"supply chain":  "supply",
+			"supplier":      "supply",
+			"channel":       "distribution",
+			"go-to-market":  "distribution",
+			"reputation":    "trust",
+			"credibility":   "trust",
+			"technical":     "tech",
+			"technology":    "tech",
+			"technological": "tech",
+		},
+	}
+)
+
+// normalize coerces a value to its canonical form, logging any coercion.
+// Values that are neither canonical nor a known synonym are left unchanged.
+func (e enumField) normalize(value string) string {
+	key := strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	for _, canonical := range e.canonical {
+		if key == canonical {
+			return canonical
+		}
+	}
+	if canonical, ok := e.synonyms[key]; ok {
+		log.Printf("Coerced %s %q to %q", e.name, value, canonical)
+		return canonical
+	}
+	return value
+}
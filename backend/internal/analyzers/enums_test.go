@@ -0,0 +1,54 @@
+package analyzers
+
+import "testing"
+
+func TestEnumNormalize(t *testing.T) {
+	tests := []struct {
+		enum  enumField
+		value string
+		want  string
+	}{
+		{marketStageEnum, "early", "early"},
+		{marketStageEnum, "Growing", "growing"},
+		{marketStageEnum, "emerging", "early"},
+		{marketStageEnum, "Saturated", "mature"},
+		{marketStageEnum, "  early   stage ", "early"},
+		{marketStageEnum, "shrinking", "declining"},
+		{capitalRequirementEnum, "very_high", "very high"},
+		{capitalRequirementEnum, "VERY HIGH", "very high"},
+		{capitalRequirementEnum, "moderate", "medium"},
+		{talentRarityEnum, "abundant", "common"},
+		{talentRarityEnum, "very scarce", "rare"},
+		{barrierTypeEnum, "Regulatory", "regulation"},
+		{barrierTypeEnum, "supply chain", "supply"},
+		{barrierTypeEnum, "technical", "tech"},
+		// Unknown values are left for the calculator's default
+		{marketStageEnum, "booming", "booming"},
+		{barrierTypeEnum, "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.enum.normalize(tt.value); got != tt.want {
+			t.Errorf("%s %q: got %q, want %q", tt.enum.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestEnumSynonymsAreCanonical guards against a synonym mapping to a value
+// the calculator doesn't score
+func TestEnumSynonymsAreCanonical(t *testing.T) {
+	for _, enum := range []enumField{marketStageEnum, capitalRequirementEnum, talentRarityEnum, barrierTypeEnum} {
+		canonical := make(map[string]bool, len(enum.canonical))
+		for _, value := range enum.canonical {
+			canonical[value] = true
+		}
+		for synonym, value := range enum.synonyms {
+			if !canonical[value] {
+				t.Errorf("%s synonym %q maps to %q, which isn't canonical", enum.name, synonym, value)
+			}
+			if canonical[synonym] {
+				t.Errorf("%s synonym %q is itself canonical", enum.name, synonym)
+			}
+		}
+	}
+}
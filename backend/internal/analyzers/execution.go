@@ -22,7 +22,7 @@ func NewExecutionAnalyzer(llmClient *llm.Client) *ExecutionAnalyzer {
 }
 
 // Analyze performs execution complexity analysis
-func (ea *ExecutionAnalyzer) Analyze(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.ExecutionAnalysis, error) {
+func (ea *ExecutionAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, promptSuffix string) (types.ExecutionAnalysis, error) {
 	systemPrompt := `You are a startup execution expert. Analyze the provided startup idea and evidence to assess execution complexity.
 
 CRITICAL REQUIREMENTS:
@@ -55,11 +55,6 @@ Talent rarity guidelines:
 
 Base assessments on Evidence, not assumptions.`
 
-	userPrompt := map[string]interface{}{
-		"idea":     idea,
-		"evidence": evidence,
-	}
-
 	schema := []byte(`{
 		"type": "object",
 		"properties": {
@@ -89,7 +84,9 @@ Base assessments on Evidence, not assumptions.`
 		"additionalProperties": false
 	}`)
 
-	response, err := ea.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	systemPrompt = appendPromptSuffix(systemPrompt, promptSuffix)
+
+	response, err := ea.llmClient.ConstrainedJSON(ctx, systemPrompt, input.UserPrompt(DimensionExecution), schema)
 	if err != nil {
 		return types.ExecutionAnalysis{}, fmt.Errorf("execution analysis failed: %w", err)
 	}
@@ -99,7 +96,9 @@ Base assessments on Evidence, not assumptions.`
 		return types.ExecutionAnalysis{}, fmt.Errorf("failed to parse execution analysis response: %w", err)
 	}
 
-	result = ea.validateEvidenceIDs(result, evidence)
+	result = ea.validateEvidenceIDs(result, input.Evidence)
+	result.CapitalRequirement = capitalRequirementEnum.normalize(result.CapitalRequirement)
+	result.TalentRarity = talentRarityEnum.normalize(result.TalentRarity)
 	return result, nil
 }
 
@@ -22,7 +22,7 @@ func NewGraveyardAnalyzer(llmClient *llm.Client) *GraveyardAnalyzer {
 }
 
 // Analyze performs graveyard analysis
-func (ga *GraveyardAnalyzer) Analyze(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.GraveyardAnalysis, error) {
+func (ga *GraveyardAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, promptSuffix string) (types.GraveyardAnalysis, error) {
 	systemPrompt := `You are a startup postmortem analyst. Analyze the provided startup idea and evidence to identify failed similar companies and extract lessons.
 
 CRITICAL REQUIREMENTS:
@@ -50,11 +50,6 @@ Types of failure causes to look for:
 
 Extract specific, actionable lessons rather than generic advice. Only include cases with solid evidence backing.`
 
-	userPrompt := map[string]interface{}{
-		"idea":     idea,
-		"evidence": evidence,
-	}
-
 	schema := []byte(`{
 		"type": "object",
 		"properties": {
@@ -85,7 +80,9 @@ Extract specific, actionable lessons rather than generic advice. Only include ca
 		"additionalProperties": false
 	}`)
 
-	response, err := ga.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	systemPrompt = appendPromptSuffix(systemPrompt, promptSuffix)
+
+	response, err := ga.llmClient.ConstrainedJSON(ctx, systemPrompt, input.UserPrompt(DimensionGraveyard), schema)
 	if err != nil {
 		return types.GraveyardAnalysis{}, fmt.Errorf("graveyard analysis failed: %w", err)
 	}
@@ -95,7 +92,7 @@ Extract specific, actionable lessons rather than generic advice. Only include ca
 		return types.GraveyardAnalysis{}, fmt.Errorf("failed to parse graveyard analysis response: %w", err)
 	}
 
-	result = ga.validateEvidenceIDs(result, evidence)
+	result = ga.validateEvidenceIDs(result, input.Evidence)
 	return result, nil
 }
 
@@ -0,0 +1,109 @@
+package analyzers
+
+import (
+	"fmt"
+	"strings"
+
+	"rectaify/pkg/types"
+)
+
+// EvidencePolicy decides what happens to competitors, barriers, risks and
+// failure cases left citing no valid evidence once unknown IDs are removed.
+// Such items are likely hallucinated.
+type EvidencePolicy string
+
+const (
+	EvidencePolicyKeep EvidencePolicy = "keep" // leave unsupported items as they are
+	EvidencePolicyFlag EvidencePolicy = "flag" // keep them, marked unsupported
+	EvidencePolicyDrop EvidencePolicy = "drop" // remove them before scoring
+)
+
+// ParseEvidencePolicy parses a policy name; empty means keep
+func ParseEvidencePolicy(name string) (EvidencePolicy, error) {
+	switch policy := EvidencePolicy(strings.ToLower(strings.TrimSpace(name))); policy {
+	case "":
+		return EvidencePolicyKeep, nil
+	case EvidencePolicyKeep, EvidencePolicyFlag, EvidencePolicyDrop:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown evidence policy %q", name)
+	}
+}
+
+// UnsupportedClaims counts the items citing no valid evidence and records what
+// was done with them
+type UnsupportedClaims struct {
+	Policy         EvidencePolicy `json:"policy"`
+	Competitors    int            `json:"competitors"`
+	Barriers       int            `json:"barriers"`
+	Risks          int            `json:"risks"`
+	GraveyardCases int            `json:"graveyard_cases"`
+}
+
+// Total is the number of unsupported items across all dimensions
+func (u UnsupportedClaims) Total() int {
+	return u.Competitors + u.Barriers + u.Risks + u.GraveyardCases
+}
+
+// enforceEvidence applies the policy to items with no evidence IDs. It runs
+// after each analyzer has removed IDs that don't match provided evidence.
+func enforceEvidence(policy EvidencePolicy, market *types.MarketAnalysis, barriers *types.BarrierAnalysis, risks *types.RiskAnalysis, graveyard *types.GraveyardAnalysis) UnsupportedClaims {
+	claims := UnsupportedClaims{Policy: policy}
+	if policy == EvidencePolicyKeep {
+		return claims
+	}
+
+	competitors := market.Competitors[:0]
+	for _, competitor := range market.Competitors {
+		if len(competitor.EvidenceIDs) == 0 {
+			claims.Competitors++
+			if policy == EvidencePolicyDrop {
+				continue
+			}
+			competitor.Unsupported = true
+		}
+		competitors = append(competitors, competitor)
+	}
+	market.Competitors = competitors
+
+	barrierItems := barriers.Barriers[:0]
+	for _, barrier := range barriers.Barriers {
+		if len(barrier.EvidenceIDs) == 0 {
+			claims.Barriers++
+			if policy == EvidencePolicyDrop {
+				continue
+			}
+			barrier.Unsupported = true
+		}
+		barrierItems = append(barrierItems, barrier)
+	}
+	barriers.Barriers = barrierItems
+
+	riskItems := risks.Risks[:0]
+	for _, risk := range risks.Risks {
+		if len(risk.EvidenceIDs) == 0 {
+			claims.Risks++
+			if policy == EvidencePolicyDrop {
+				continue
+			}
+			risk.Unsupported = true
+		}
+		riskItems = append(riskItems, risk)
+	}
+	risks.Risks = riskItems
+
+	cases := graveyard.Cases[:0]
+	for _, graveyardCase := range graveyard.Cases {
+		if len(graveyardCase.EvidenceIDs) == 0 {
+			claims.GraveyardCases++
+			if policy == EvidencePolicyDrop {
+				continue
+			}
+			graveyardCase.Unsupported = true
+		}
+		cases = append(cases, graveyardCase)
+	}
+	graveyard.Cases = cases
+
+	return claims
+}
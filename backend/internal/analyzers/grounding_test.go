@@ -0,0 +1,114 @@
+package analyzers
+
+import (
+	"slices"
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func TestEnforceEvidence(t *testing.T) {
+	tests := []struct {
+		policy          EvidencePolicy
+		wantClaims      UnsupportedClaims
+		wantCompetitors []string
+		wantFlagged     []string // competitors marked unsupported
+		wantItems       int      // barriers and risks kept
+		wantCases       int      // graveyard cases kept
+	}{
+		{
+			policy:          EvidencePolicyKeep,
+			wantClaims:      UnsupportedClaims{Policy: EvidencePolicyKeep},
+			wantCompetitors: []string{"Tutorly", "Phantom Learning"},
+			wantItems:       2,
+			wantCases:       1,
+		},
+		{
+			policy:          EvidencePolicyFlag,
+			wantClaims:      UnsupportedClaims{Policy: EvidencePolicyFlag, Competitors: 1, Barriers: 1, Risks: 1, GraveyardCases: 1},
+			wantCompetitors: []string{"Tutorly", "Phantom Learning"},
+			wantFlagged:     []string{"Phantom Learning"},
+			wantItems:       2,
+			wantCases:       1,
+		},
+		{
+			policy:          EvidencePolicyDrop,
+			wantClaims:      UnsupportedClaims{Policy: EvidencePolicyDrop, Competitors: 1, Barriers: 1, Risks: 1, GraveyardCases: 1},
+			wantCompetitors: []string{"Tutorly"},
+			wantItems:       1,
+			wantCases:       0,
+		},
+	}
+
+	evidence := []types.Evidence{{ID: "ev_1"}, {ID: "ev_2"}}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			// The analyzers strip IDs the LLM made up before the policy runs, which
+			// leaves one item of each kind citing nothing
+			market := (&MarketAnalyzer{}).validateEvidenceIDs(types.MarketAnalysis{Competitors: []types.Competitor{
+				{Name: "Tutorly", EvidenceIDs: []string{"ev_1", "ev_404"}},
+				{Name: "Phantom Learning", EvidenceIDs: []string{"ev_404", "ev_made_up"}},
+			}}, evidence)
+			barriers := (&BarriersAnalyzer{}).validateEvidenceIDs(types.BarrierAnalysis{Barriers: []types.Barrier{
+				{Type: "regulation", Weight: 0.8, EvidenceIDs: []string{"ev_2"}},
+				{Type: "trust", Weight: 0.4, EvidenceIDs: []string{"ev_999"}},
+			}}, evidence)
+			risks := (&RisksAnalyzer{}).validateEvidenceIDs(types.RiskAnalysis{Risks: []types.Risk{
+				{Category: "market", Severity: 3, Likelihood: 3, EvidenceIDs: []string{"ev_1"}},
+				{Category: "legal", Severity: 5, Likelihood: 2},
+			}}, evidence)
+			graveyard := (&GraveyardAnalyzer{}).validateEvidenceIDs(types.GraveyardAnalysis{Cases: []types.GraveyardCase{
+				{CompanyName: "Tutor.ly", EvidenceIDs: []string{"ev_made_up"}},
+			}}, evidence)
+
+			claims := enforceEvidence(tt.policy, &market, &barriers, &risks, &graveyard)
+			if claims != tt.wantClaims {
+				t.Errorf("got claims %+v, want %+v", claims, tt.wantClaims)
+			}
+
+			var names, flagged []string
+			for _, competitor := range market.Competitors {
+				names = append(names, competitor.Name)
+				if competitor.Unsupported {
+					flagged = append(flagged, competitor.Name)
+				}
+			}
+			if !slices.Equal(names, tt.wantCompetitors) {
+				t.Errorf("got competitors %v, want %v", names, tt.wantCompetitors)
+			}
+			if !slices.Equal(flagged, tt.wantFlagged) {
+				t.Errorf("got flagged competitors %v, want %v", flagged, tt.wantFlagged)
+			}
+
+			if len(barriers.Barriers) != tt.wantItems || len(risks.Risks) != tt.wantItems {
+				t.Errorf("got %d barriers and %d risks, want %d of each", len(barriers.Barriers), len(risks.Risks), tt.wantItems)
+			}
+			if len(graveyard.Cases) != tt.wantCases {
+				t.Errorf("got %d graveyard cases, want %d", len(graveyard.Cases), tt.wantCases)
+			}
+			if tt.policy == EvidencePolicyFlag && (!barriers.Barriers[1].Unsupported || !risks.Risks[1].Unsupported || !graveyard.Cases[0].Unsupported) {
+				t.Error("unsupported barrier, risk or graveyard case was not flagged")
+			}
+		})
+	}
+}
+
+func TestParseEvidencePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    EvidencePolicy
+		wantErr bool
+	}{
+		{"", EvidencePolicyKeep, false},
+		{" Flag ", EvidencePolicyFlag, false},
+		{"drop", EvidencePolicyDrop, false},
+		{"reject", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEvidencePolicy(tt.name)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseEvidencePolicy(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"rectaify/internal/llm"
+	"rectaify/pkg/types"
+)
+
+// IdeaExpander reads a vague one-liner as a fuller description of the idea,
+// giving the query planner more to search for
+type IdeaExpander struct {
+	llmClient *llm.Client
+}
+
+// NewIdeaExpander creates a new idea expander
+func NewIdeaExpander(llmClient *llm.Client) *IdeaExpander {
+	return &IdeaExpander{
+		llmClient: llmClient,
+	}
+}
+
+// Expand asks the LLM who the idea is for, what it does for them and which
+// category it belongs to. When the LLM finds the idea specific already, the
+// expansion says so and should not be used.
+func (ie *IdeaExpander) Expand(ctx context.Context, idea types.IdeaInput) (types.IdeaExpansion, error) {
+	systemPrompt := `You are a startup analyst reading a short idea description before researching it. Expand the idea into a structured description a researcher could search from.
+
+CRITICAL REQUIREMENTS:
+1. Output ONLY valid JSON matching the required schema
+2. target_user: the most likely customer, in a few words (e.g. "independent restaurant owners")
+3. core_value: what the product does for them, in one short sentence
+4. category_guess: the market category, in one or two words (e.g. "fintech", "edtech")
+5. specific: true if the idea already names its user and what it does for them clearly enough to research as written
+6. Stay with the most plausible reading of the idea; do not add features it does not suggest`
+
+	userPrompt := map[string]interface{}{
+		"idea": idea,
+	}
+
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"target_user": {"type": "string"},
+			"core_value": {"type": "string"},
+			"category_guess": {"type": "string"},
+			"specific": {"type": "boolean"}
+		},
+		"required": ["target_user", "core_value", "category_guess", "specific"],
+		"additionalProperties": false
+	}`)
+
+	response, err := ie.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	if err != nil {
+		return types.IdeaExpansion{}, fmt.Errorf("idea expansion failed: %w", err)
+	}
+
+	var expansion types.IdeaExpansion
+	if err := json.Unmarshal(response, &expansion); err != nil {
+		return types.IdeaExpansion{}, fmt.Errorf("failed to parse idea expansion response: %w", err)
+	}
+
+	expansion.TargetUser = strings.TrimSpace(expansion.TargetUser)
+	expansion.CoreValue = strings.TrimSpace(expansion.CoreValue)
+	expansion.CategoryGuess = strings.TrimSpace(expansion.CategoryGuess)
+	return expansion, nil
+}
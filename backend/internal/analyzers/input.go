@@ -0,0 +1,200 @@
+package analyzers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"rectaify/internal/evidence"
+	"rectaify/pkg/types"
+)
+
+// Snippet limits for SnippetLimits values
+const (
+	SnippetFull      = -1 // send the whole snippet
+	SnippetTitleOnly = 0  // send the ID, title and URL but no snippet
+)
+
+// SnippetLimits sets, per analyzer dimension (or "verdict"), how many
+// characters of each evidence snippet go into the prompt. Dimensions that are
+// not listed receive full snippets. Evidence IDs are always sent so
+// citations still validate.
+type SnippetLimits map[string]int
+
+// DimensionVerdict names the verdict enhancer in SnippetLimits
+const DimensionVerdict = "verdict"
+
+// Limit returns the snippet limit for a dimension
+func (l SnippetLimits) Limit(dimension string) int {
+	if limit, ok := l[dimension]; ok {
+		return limit
+	}
+	return SnippetFull
+}
+
+// dimensionIntents are the search intents, as the query planner names them,
+// whose evidence each dimension analyzer is handed first
+var dimensionIntents = map[string][]string{
+	DimensionMarket:    {"competitors", "market"},
+	DimensionProblem:   {"problem", "market"},
+	DimensionBarriers:  {"regulation", "competitors"},
+	DimensionExecution: {"funding"},
+	DimensionRisks:     {"regulation", "postmortems"},
+	DimensionGraveyard: {"postmortems"},
+}
+
+// AnalysisInput is the idea and evidence for one analysis. Each dimension
+// sees the evidence retrieved for its intents first, cut to its snippet
+// limit; each distinct ordering and limit is serialized once and shared by
+// every dimension analyzer that uses it instead of each one marshaling the
+// same evidence again.
+type AnalysisInput struct {
+	Idea     types.IdeaInput
+	Evidence []types.Evidence
+
+	limits      SnippetLimits
+	userPrompts map[string]string // keyed by promptKey
+	fullBytes   int               // size of the prompt had every evidence field been sent
+}
+
+// promptEvidence is the evidence as shown to the LLM. Retrieval timestamps carry
+// no signal for the analyzers and publication times only need day precision.
+type promptEvidence struct {
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Snippet     string `json:"snippet,omitempty"`
+	PublishedAt string `json:"published_at,omitempty"`
+	SourceType  string `json:"source_type,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// PromptStats reports how large the analyzer prompts are
+type PromptStats struct {
+	FullBytes            int            `json:"full_bytes"`             // with every evidence field
+	DimensionBytes       map[string]int `json:"dimension_bytes"`        // sent to each analyzer
+	SnippetLimits        map[string]int `json:"snippet_limits"`         // -1 is a full snippet, 0 title only
+	TotalBytesSent       int            `json:"total_bytes_sent"`       // sum of DimensionBytes
+	TotalBytesSaved      int            `json:"total_bytes_saved"`      // versus FullBytes for every analyzer
+	EstimatedTokensSaved int            `json:"estimated_tokens_saved"` // TotalBytesSaved at ~4 bytes per token
+}
+
+// NewAnalysisInput serializes the idea and evidence into the user prompt of
+// every snippet limit in use. limits may be nil.
+func NewAnalysisInput(idea types.IdeaInput, evidence []types.Evidence, limits SnippetLimits) (*AnalysisInput, error) {
+	input := &AnalysisInput{
+		Idea:        idea,
+		Evidence:    evidence,
+		limits:      limits,
+		userPrompts: make(map[string]string),
+	}
+
+	for _, dimension := range Dimensions {
+		key := input.promptKey(dimension)
+		if _, ok := input.userPrompts[key]; ok {
+			continue
+		}
+
+		prompt, err := json.Marshal(map[string]interface{}{
+			"idea":     idea,
+			"evidence": compactEvidence(rankForDimension(evidence, dimension), limits.Limit(dimension)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal analyzer input: %w", err)
+		}
+		input.userPrompts[key] = string(prompt)
+	}
+
+	full, err := json.Marshal(map[string]interface{}{
+		"idea":     idea,
+		"evidence": evidence,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal analyzer input: %w", err)
+	}
+	input.fullBytes = len(full)
+
+	return input, nil
+}
+
+// compactEvidence converts evidence to its prompt form, cutting each snippet
+// to the given limit
+func compactEvidence(evidence []types.Evidence, limit int) []promptEvidence {
+	compact := make([]promptEvidence, len(evidence))
+	for i, ev := range evidence {
+		compact[i] = promptEvidence{
+			ID:          ev.ID,
+			URL:         ev.URL,
+			Title:       ev.Title,
+			Snippet:     truncateSnippet(ev.Snippet, limit),
+			SourceType:  ev.SourceType,
+			ContentType: ev.ContentType,
+		}
+		if ev.PublishedAt != nil {
+			compact[i].PublishedAt = ev.PublishedAt.Format("2006-01-02")
+		}
+	}
+	return compact
+}
+
+// truncateSnippet cuts a snippet to limit characters, marking the cut with an ellipsis
+func truncateSnippet(snippet string, limit int) string {
+	if limit < 0 || utf8.RuneCountInString(snippet) <= limit {
+		return snippet
+	}
+	if limit == SnippetTitleOnly {
+		return ""
+	}
+	runes := []rune(snippet)
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
+
+// CacheKey identifies the dimension analyzer inputs: the idea, the evidence as
+// each analyzer sees it and the category prompt hints. Analyses with the same
+// key send the same prompts, so their dimension results can be reused.
+func (in *AnalysisInput) CacheKey(hints CategoryPromptHints) string {
+	hash := sha256.New()
+	for _, dimension := range Dimensions {
+		fmt.Fprintf(hash, "%s\x00%s\x00%s\x00", dimension, in.UserPrompt(dimension), hints.Hint(in.Idea.Category, dimension))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// UserPrompt returns the pre-serialized idea and evidence for a dimension
+func (in *AnalysisInput) UserPrompt(dimension string) string {
+	return in.userPrompts[in.promptKey(dimension)]
+}
+
+// rankForDimension orders evidence with the items retrieved for the
+// dimension's intents first
+func rankForDimension(items []types.Evidence, dimension string) []types.Evidence {
+	return evidence.RankForIntents(items, dimensionIntents[dimension])
+}
+
+// promptKey identifies the user prompt of a dimension by what shapes it: the
+// intents its evidence is ranked by and its snippet limit
+func (in *AnalysisInput) promptKey(dimension string) string {
+	return fmt.Sprintf("%s/%d", strings.Join(dimensionIntents[dimension], ","), in.limits.Limit(dimension))
+}
+
+// Stats reports the prompt size of each dimension analyzer and the savings
+// over sending every evidence field in full
+func (in *AnalysisInput) Stats() PromptStats {
+	stats := PromptStats{
+		FullBytes:      in.fullBytes,
+		DimensionBytes: make(map[string]int, len(Dimensions)),
+		SnippetLimits:  make(map[string]int, len(Dimensions)),
+	}
+	for _, dimension := range Dimensions {
+		size := len(in.UserPrompt(dimension))
+		stats.DimensionBytes[dimension] = size
+		stats.SnippetLimits[dimension] = in.limits.Limit(dimension)
+		stats.TotalBytesSent += size
+		stats.TotalBytesSaved += in.fullBytes - size
+	}
+	stats.EstimatedTokensSaved = stats.TotalBytesSaved / 4
+	return stats
+}
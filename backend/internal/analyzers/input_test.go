@@ -0,0 +1,57 @@
+package analyzers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+// promptEvidenceIDs returns the IDs of the evidence in a dimension's prompt, in order
+func promptEvidenceIDs(t *testing.T, input *AnalysisInput, dimension string) []string {
+	t.Helper()
+	var prompt struct {
+		Evidence []promptEvidence `json:"evidence"`
+	}
+	if err := json.Unmarshal([]byte(input.UserPrompt(dimension)), &prompt); err != nil {
+		t.Fatalf("%s prompt: %v", dimension, err)
+	}
+	ids := make([]string, len(prompt.Evidence))
+	for i, ev := range prompt.Evidence {
+		ids[i] = ev.ID
+	}
+	return ids
+}
+
+func TestUserPromptRanksIntentMatchedEvidenceFirst(t *testing.T) {
+	evidence := []types.Evidence{
+		{ID: "funding", URL: "https://example.com/1", Title: "Seed round", Intents: []string{"funding"}},
+		{ID: "generic", URL: "https://example.com/2", Title: "Tutoring overview"},
+		{ID: "postmortem", URL: "https://example.com/3", Title: "Why we shut down", Intents: []string{"postmortems"}},
+		{ID: "competitor", URL: "https://example.com/4", Title: "Top tutoring apps", Intents: []string{"competitors"}},
+	}
+	input, err := NewAnalysisInput(types.IdeaInput{Title: "Tutor match"}, evidence, nil)
+	if err != nil {
+		t.Fatalf("NewAnalysisInput: %v", err)
+	}
+
+	tests := map[string]string{
+		DimensionMarket:    "competitor",
+		DimensionExecution: "funding",
+		DimensionGraveyard: "postmortem",
+	}
+	for dimension, wantFirst := range tests {
+		ids := promptEvidenceIDs(t, input, dimension)
+		if len(ids) != len(evidence) {
+			t.Fatalf("%s prompt has %d items, want all %d", dimension, len(ids), len(evidence))
+		}
+		if ids[0] != wantFirst {
+			t.Errorf("%s prompt starts with %q, want %q: %v", dimension, ids[0], wantFirst, ids)
+		}
+	}
+
+	// With nothing retrieved for its intents, a dimension keeps quality order
+	if ids := promptEvidenceIDs(t, input, DimensionProblem); ids[0] != "funding" || ids[1] != "generic" {
+		t.Errorf("problem prompt reordered unmatched evidence: %v", ids)
+	}
+}
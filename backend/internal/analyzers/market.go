@@ -4,25 +4,35 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"rectaify/internal/llm"
+	"rectaify/internal/money"
 	"rectaify/pkg/types"
 )
 
 // MarketAnalyzer analyzes market conditions and competition
 type MarketAnalyzer struct {
 	llmClient *llm.Client
+	fxRates   money.Rates
 }
 
-// NewMarketAnalyzer creates a new market analyzer
-func NewMarketAnalyzer(llmClient *llm.Client) *MarketAnalyzer {
+// NewMarketAnalyzer creates a new market analyzer. fxRates converts competitor
+// funding to US dollars; nil uses money.DefaultRates.
+func NewMarketAnalyzer(llmClient *llm.Client, fxRates money.Rates) *MarketAnalyzer {
+	if fxRates == nil {
+		fxRates = money.DefaultRates()
+	}
 	return &MarketAnalyzer{
 		llmClient: llmClient,
+		fxRates:   fxRates,
 	}
 }
 
 // Analyze performs market analysis based on idea and evidence
-func (ma *MarketAnalyzer) Analyze(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.MarketAnalysis, error) {
+func (ma *MarketAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, promptSuffix string) (types.MarketAnalysis, error) {
 	// Create the analysis prompt
 	systemPrompt := `You are a market research analyst. Analyze the provided startup idea and evidence to assess market conditions.
 
@@ -32,6 +42,7 @@ CRITICAL REQUIREMENTS:
 3. Output ONLY valid JSON matching the required schema
 4. Reference Evidence by ID numbers when making claims
 5. Categorize market stage as exactly one of: "early", "growing", "mature", "declining"
+6. Rate each competitor's overlap with the idea from 1 (adjacent market) to 5 (same product for the same customers)
 
 Your analysis should focus on:
 - Identifying direct and indirect competitors from Evidence
@@ -41,12 +52,6 @@ Your analysis should focus on:
 
 Be conservative - if Evidence doesn't clearly support a conclusion, acknowledge uncertainty.`
 
-	// Prepare user prompt with idea and evidence
-	userPrompt := map[string]interface{}{
-		"idea":     idea,
-		"evidence": evidence,
-	}
-
 	// Define JSON schema for market analysis
 	schema := []byte(`{
 		"type": "object",
@@ -60,12 +65,13 @@ Be conservative - if Evidence doesn't clearly support a conclusion, acknowledge
 						"description": {"type": "string"},
 						"funding": {"type": "string"},
 						"stage": {"type": "string"},
+						"overlap": {"type": "integer", "minimum": 1, "maximum": 5},
 						"evidence_ids": {
 							"type": "array",
 							"items": {"type": "string"}
 						}
 					},
-					"required": ["name", "description", "evidence_ids"],
+					"required": ["name", "description", "overlap", "evidence_ids"],
 					"additionalProperties": false
 				}
 			},
@@ -84,7 +90,9 @@ Be conservative - if Evidence doesn't clearly support a conclusion, acknowledge
 	}`)
 
 	// Call LLM for analysis
-	response, err := ma.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	systemPrompt = appendPromptSuffix(systemPrompt, promptSuffix)
+
+	response, err := ma.llmClient.ConstrainedJSON(ctx, systemPrompt, input.UserPrompt(DimensionMarket), schema)
 	if err != nil {
 		return types.MarketAnalysis{}, fmt.Errorf("market analysis failed: %w", err)
 	}
@@ -96,11 +104,106 @@ Be conservative - if Evidence doesn't clearly support a conclusion, acknowledge
 	}
 
 	// Validate that evidence IDs exist
-	result = ma.validateEvidenceIDs(result, evidence)
+	result = ma.validateEvidenceIDs(result, input.Evidence)
+	result.MarketStage = marketStageEnum.normalize(result.MarketStage)
+
+	// Convert funding to US dollars so competitors compare across currencies
+	normalizeFunding(result.Competitors, ma.fxRates)
+
+	// Put the biggest threats first
+	rankCompetitors(result.Competitors)
 
 	return result, nil
 }
 
+// rankCompetitors scores each competitor's threat and sorts by it, highest first.
+// Overlap with the idea dominates, then how well funded the competitor is,
+// then how much evidence mentions it.
+func rankCompetitors(competitors []types.Competitor) {
+	for i := range competitors {
+		c := &competitors[i]
+
+		overlap := c.Overlap
+		if overlap < 1 || overlap > 5 {
+			overlap = 3 // unrated
+		}
+		overlapScore := float64(overlap-1) / 4 * 100
+
+		evidenceCount := len(c.EvidenceIDs)
+		if evidenceCount > 5 {
+			evidenceCount = 5
+		}
+		evidenceScore := float64(evidenceCount) / 5 * 100
+
+		threat := 0.5*overlapScore + 0.3*fundingStageScore(c.Stage+" "+c.Funding, c.FundingNormalized) + 0.2*evidenceScore
+		c.ThreatScore = math.Round(threat*10) / 10
+	}
+
+	sort.SliceStable(competitors, func(i, j int) bool {
+		return competitors[i].ThreatScore > competitors[j].ThreatScore
+	})
+}
+
+// normalizeFunding parses each competitor's funding text into US dollars,
+// keeping the original text. Unparseable funding is flagged, not dropped.
+func normalizeFunding(competitors []types.Competitor, rates money.Rates) {
+	for i := range competitors {
+		funding := strings.TrimSpace(competitors[i].Funding)
+		if funding == "" {
+			continue
+		}
+		amount := money.Parse(funding, rates)
+		competitors[i].FundingNormalized = &amount
+	}
+}
+
+// fundingStageScore rates how well resourced a competitor is from its stage and
+// funding text, falling back to the amount raised when no stage is named
+func fundingStageScore(text string, funding *types.MoneyAmount) float64 {
+	text = strings.ToLower(text)
+
+	stages := []struct {
+		keywords []string
+		score    float64
+	}{
+		{[]string{"public", "ipo", "acquired", "enterprise", "incumbent"}, 100},
+		{[]string{"series d", "series e", "series f", "late stage", "late-stage"}, 90},
+		{[]string{"series c"}, 80},
+		{[]string{"series b", "growth"}, 65},
+		{[]string{"series a"}, 50},
+		{[]string{"pre-seed", "seed", "angel"}, 30},
+		{[]string{"bootstrapped", "self-funded", "startup"}, 20},
+	}
+	for _, stage := range stages {
+		for _, keyword := range stage.keywords {
+			if strings.Contains(text, keyword) {
+				return stage.score
+			}
+		}
+	}
+
+	if funding != nil && funding.Parsed {
+		amounts := []struct {
+			usd   float64
+			score float64
+		}{
+			{1e9, 100},
+			{1e8, 90},
+			{3e7, 80},
+			{1e7, 65},
+			{2e6, 50},
+			{1, 30},
+		}
+		for _, amount := range amounts {
+			if funding.USD >= amount.usd {
+				return amount.score
+			}
+		}
+	}
+
+	return 40 // unknown
+}
+
 // validateEvidenceIDs ensures all referenced evidence IDs actually exist
 func (ma *MarketAnalyzer) validateEvidenceIDs(analysis types.MarketAnalysis, evidence []types.Evidence) types.MarketAnalysis {
 	evidenceSet := make(map[string]bool)
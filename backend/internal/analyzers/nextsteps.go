@@ -0,0 +1,135 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"rectaify/internal/llm"
+	"rectaify/pkg/types"
+)
+
+// Bounds on the validation plan for one idea
+const (
+	maxNextSteps        = 5
+	nextStepsDimensions = 3 // lowest-scoring dimensions the plan de-risks
+)
+
+// NextStepsAnalyzer turns the weakest dimensions of an analysis into a
+// validation plan: experiments or research that would de-risk each one
+type NextStepsAnalyzer struct {
+	llmClient    *llm.Client
+	snippetLimit int
+}
+
+// NewNextStepsAnalyzer creates a new next steps analyzer. snippetLimit caps
+// the evidence snippets sent with the analysis.
+func NewNextStepsAnalyzer(llmClient *llm.Client, snippetLimit int) *NextStepsAnalyzer {
+	return &NextStepsAnalyzer{
+		llmClient:    llmClient,
+		snippetLimit: snippetLimit,
+	}
+}
+
+// Plan proposes validation steps for the lowest-scoring dimensions, most
+// impactful first. Steps that cite no provided evidence are dropped so the
+// plan stays specific to this idea.
+func (na *NextStepsAnalyzer) Plan(ctx context.Context, analysis types.Analysis) ([]types.ValidationStep, error) {
+	focus := weakestDimensions(analysis.Verdict, nextStepsDimensions)
+
+	systemPrompt := `You are a startup advisor turning an analysis into a validation plan. Given the weakest dimensions of an idea and the evidence behind them, propose 3-5 concrete experiments or research tasks the founder should run next to de-risk them.
+
+CRITICAL REQUIREMENTS:
+1. ONLY use information from the provided analysis and evidence
+2. Output ONLY valid JSON matching the required schema
+3. Each step must target one of the focus dimensions
+4. Every step must list the Evidence IDs that motivated it in evidence_ids
+5. Order steps by impact: the step that would most change the verdict comes first
+6. Propose specific actions (who to talk to, what to build or measure, which numbers to find), not generic advice
+
+Each step should:
+- Describe the action in one or two sentences
+- Name the result that would show the weakness is less severe than it looks`
+
+	userPrompt := map[string]interface{}{
+		"idea":             analysis.Idea,
+		"focus_dimensions": focus,
+		"weaknesses":       dimensionResults(analysis, focus),
+		"key_insights":     analysis.Verdict.KeyInsights,
+		"evidence":         compactEvidence(analysis.Evidence, na.snippetLimit),
+	}
+
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"steps": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"action": {"type": "string"},
+						"dimension": {"type": "string", "enum": ["market", "problem", "barriers", "execution", "risks", "graveyard"]},
+						"success_signal": {"type": "string"},
+						"evidence_ids": {
+							"type": "array",
+							"items": {"type": "string"}
+						}
+					},
+					"required": ["action", "dimension", "success_signal", "evidence_ids"],
+					"additionalProperties": false
+				}
+			}
+		},
+		"required": ["steps"],
+		"additionalProperties": false
+	}`)
+
+	response, err := na.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("next steps generation failed: %w", err)
+	}
+
+	var result struct {
+		Steps []types.ValidationStep `json:"steps"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse next steps response: %w", err)
+	}
+
+	return na.validateSteps(result.Steps, analysis.Evidence), nil
+}
+
+// validateSteps drops empty steps and those citing no provided evidence,
+// keeping at most maxNextSteps in the order given
+func (na *NextStepsAnalyzer) validateSteps(steps []types.ValidationStep, evidence []types.Evidence) []types.ValidationStep {
+	evidenceSet := make(map[string]bool)
+	for _, ev := range evidence {
+		evidenceSet[ev.ID] = true
+	}
+
+	var valid []types.ValidationStep
+	for _, step := range steps {
+		if strings.TrimSpace(step.Action) == "" {
+			continue
+		}
+
+		var validIDs []string
+		for _, id := range step.EvidenceIDs {
+			if evidenceSet[id] {
+				validIDs = append(validIDs, id)
+			}
+		}
+		if len(validIDs) == 0 {
+			continue
+		}
+		step.EvidenceIDs = validIDs
+
+		valid = append(valid, step)
+		if len(valid) == maxNextSteps {
+			break
+		}
+	}
+
+	return valid
+}
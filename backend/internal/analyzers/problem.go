@@ -22,7 +22,7 @@ func NewProblemAnalyzer(llmClient *llm.Client) *ProblemAnalyzer {
 }
 
 // Analyze performs problem validation analysis
-func (pa *ProblemAnalyzer) Analyze(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.ProblemAnalysis, error) {
+func (pa *ProblemAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, promptSuffix string) (types.ProblemAnalysis, error) {
 	systemPrompt := `You are a problem validation expert. Analyze the provided startup idea and evidence to assess problem validity.
 
 CRITICAL REQUIREMENTS:
@@ -41,11 +41,6 @@ Your analysis should focus on:
 
 Be skeptical - distinguish between assumed problems and evidence-backed pain points.`
 
-	userPrompt := map[string]interface{}{
-		"idea":     idea,
-		"evidence": evidence,
-	}
-
 	schema := []byte(`{
 		"type": "object",
 		"properties": {
@@ -67,7 +62,9 @@ Be skeptical - distinguish between assumed problems and evidence-backed pain poi
 		"additionalProperties": false
 	}`)
 
-	response, err := pa.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	systemPrompt = appendPromptSuffix(systemPrompt, promptSuffix)
+
+	response, err := pa.llmClient.ConstrainedJSON(ctx, systemPrompt, input.UserPrompt(DimensionProblem), schema)
 	if err != nil {
 		return types.ProblemAnalysis{}, fmt.Errorf("problem analysis failed: %w", err)
 	}
@@ -77,7 +74,7 @@ Be skeptical - distinguish between assumed problems and evidence-backed pain poi
 		return types.ProblemAnalysis{}, fmt.Errorf("failed to parse problem analysis response: %w", err)
 	}
 
-	result = pa.validateEvidenceIDs(result, evidence)
+	result = pa.validateEvidenceIDs(result, input.Evidence)
 	return result, nil
 }
 
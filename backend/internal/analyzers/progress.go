@@ -0,0 +1,44 @@
+package analyzers
+
+import (
+	"context"
+
+	"rectaify/pkg/types"
+)
+
+// progressKey carries the channel dimension results are reported on
+type progressKey struct{}
+
+// WithProgress returns a context under which AnalyzeAll reports each
+// dimension on progress as soon as its analyzer finishes. The channel should
+// have room for every dimension; reports are dropped once ctx ends.
+func WithProgress(ctx context.Context, progress chan<- types.DimensionEvent) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+// reportDimension sends a finished dimension to the progress channel, if any
+func reportDimension(ctx context.Context, dimension string, result interface{}, err error) {
+	progress, ok := ctx.Value(progressKey{}).(chan<- types.DimensionEvent)
+	if !ok {
+		return
+	}
+
+	event := types.DimensionEvent{Dimension: dimension, Result: result}
+	if err != nil {
+		event = types.DimensionEvent{Dimension: dimension, Error: err.Error()}
+	}
+	select {
+	case progress <- event:
+	case <-ctx.Done():
+	}
+}
+
+// reportDimensions sends every dimension of results, as when they come from the cache
+func reportDimensions(ctx context.Context, results types.DimensionResults) {
+	reportDimension(ctx, DimensionMarket, results.Market, nil)
+	reportDimension(ctx, DimensionProblem, results.Problem, nil)
+	reportDimension(ctx, DimensionBarriers, results.Barriers, nil)
+	reportDimension(ctx, DimensionExecution, results.Execution, nil)
+	reportDimension(ctx, DimensionRisks, results.Risks, nil)
+	reportDimension(ctx, DimensionGraveyard, results.Graveyard, nil)
+}
@@ -0,0 +1,51 @@
+package analyzers
+
+import "strings"
+
+// Analyzer dimensions that can receive category-specific prompt hints
+const (
+	DimensionMarket    = "market"
+	DimensionProblem   = "problem"
+	DimensionBarriers  = "barriers"
+	DimensionExecution = "execution"
+	DimensionRisks     = "risks"
+	DimensionGraveyard = "graveyard"
+)
+
+// Dimensions lists every analyzer dimension that accepts prompt hints
+var Dimensions = []string{
+	DimensionMarket,
+	DimensionProblem,
+	DimensionBarriers,
+	DimensionExecution,
+	DimensionRisks,
+	DimensionGraveyard,
+}
+
+// CategoryPromptHints maps an idea category to per-dimension guidance that is
+// appended to the analyzer system prompts, e.g.
+// {"fintech": {"risks": "Pay particular attention to compliance risks."}}
+type CategoryPromptHints map[string]map[string]string
+
+// Hint returns the guidance for a category and dimension, matching the
+// category case-insensitively. It returns "" when none is configured.
+func (h CategoryPromptHints) Hint(category, dimension string) string {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		return ""
+	}
+	for name, hints := range h {
+		if strings.ToLower(name) == category {
+			return strings.TrimSpace(hints[dimension])
+		}
+	}
+	return ""
+}
+
+// appendPromptSuffix adds category guidance after the core system prompt
+func appendPromptSuffix(systemPrompt, suffix string) string {
+	if suffix == "" {
+		return systemPrompt
+	}
+	return systemPrompt + "\n\nCATEGORY-SPECIFIC GUIDANCE:\n" + suffix
+}
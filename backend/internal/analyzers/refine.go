@@ -0,0 +1,179 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"rectaify/internal/llm"
+	"rectaify/pkg/types"
+)
+
+// Bounds on the refinements suggested for one idea
+const (
+	maxRefinements        = 3
+	refineFocusDimensions = 3 // lowest-scoring dimensions the suggestions target
+)
+
+// RefineAnalyzer suggests changes to an analyzed idea that would address its
+// lowest-scoring dimensions
+type RefineAnalyzer struct {
+	llmClient    *llm.Client
+	snippetLimit int
+}
+
+// NewRefineAnalyzer creates a new refine analyzer. snippetLimit caps the
+// evidence snippets sent with the analysis.
+func NewRefineAnalyzer(llmClient *llm.Client, snippetLimit int) *RefineAnalyzer {
+	return &RefineAnalyzer{
+		llmClient:    llmClient,
+		snippetLimit: snippetLimit,
+	}
+}
+
+// Suggest proposes refinements grounded in the analysis and its evidence. It
+// returns the dimensions the suggestions focus on along with the suggestions.
+func (ra *RefineAnalyzer) Suggest(ctx context.Context, analysis types.Analysis) ([]types.DimensionScore, []types.Refinement, error) {
+	focus := weakestDimensions(analysis.Verdict, refineFocusDimensions)
+
+	systemPrompt := `You are a startup advisor helping a founder rework a weak idea. Given an analysis of the idea and the evidence behind it, propose 2-3 specific refinements that would most improve the lowest-scoring dimensions.
+
+CRITICAL REQUIREMENTS:
+1. ONLY use information from the provided analysis and evidence
+2. Output ONLY valid JSON matching the required schema
+3. Each refinement must target at least one of the focus dimensions
+4. Every refinement must list the Evidence IDs that support its rationale in evidence_ids
+5. Propose concrete changes (a narrower customer, a different business model, a new channel), not generic advice
+
+Each refinement should:
+- Name the change in a short title
+- Describe exactly what to do differently
+- Explain, citing evidence, why the change addresses the identified weaknesses`
+
+	userPrompt := map[string]interface{}{
+		"idea":             analysis.Idea,
+		"focus_dimensions": focus,
+		"weaknesses":       dimensionResults(analysis, focus),
+		"key_insights":     analysis.Verdict.KeyInsights,
+		"evidence":         compactEvidence(analysis.Evidence, ra.snippetLimit),
+	}
+
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"suggestions": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"title": {"type": "string"},
+						"change": {"type": "string"},
+						"addresses": {
+							"type": "array",
+							"items": {"type": "string", "enum": ["market", "problem", "barriers", "execution", "risks", "graveyard"]}
+						},
+						"rationale": {"type": "string"},
+						"evidence_ids": {
+							"type": "array",
+							"items": {"type": "string"}
+						}
+					},
+					"required": ["title", "change", "addresses", "rationale", "evidence_ids"],
+					"additionalProperties": false
+				}
+			}
+		},
+		"required": ["suggestions"],
+		"additionalProperties": false
+	}`)
+
+	response, err := ra.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refinement generation failed: %w", err)
+	}
+
+	var result struct {
+		Suggestions []types.Refinement `json:"suggestions"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse refinement response: %w", err)
+	}
+
+	suggestions := ra.validateSuggestions(result.Suggestions, analysis.Evidence)
+	if len(suggestions) == 0 {
+		return nil, nil, fmt.Errorf("refinement generation returned no suggestions")
+	}
+
+	return focus, suggestions, nil
+}
+
+// validateSuggestions drops empty suggestions and citations of evidence that
+// was never provided, keeping at most maxRefinements
+func (ra *RefineAnalyzer) validateSuggestions(suggestions []types.Refinement, evidence []types.Evidence) []types.Refinement {
+	evidenceSet := make(map[string]bool)
+	for _, ev := range evidence {
+		evidenceSet[ev.ID] = true
+	}
+
+	var valid []types.Refinement
+	for _, suggestion := range suggestions {
+		if strings.TrimSpace(suggestion.Title) == "" || strings.TrimSpace(suggestion.Change) == "" {
+			continue
+		}
+
+		var validIDs []string
+		for _, id := range suggestion.EvidenceIDs {
+			if evidenceSet[id] {
+				validIDs = append(validIDs, id)
+			}
+		}
+		suggestion.EvidenceIDs = validIDs
+
+		valid = append(valid, suggestion)
+		if len(valid) == maxRefinements {
+			break
+		}
+	}
+
+	return valid
+}
+
+// weakestDimensions returns the n lowest-scoring dimensions of a verdict, lowest first
+func weakestDimensions(verdict types.Viability, n int) []types.DimensionScore {
+	scores := []types.DimensionScore{
+		{Dimension: DimensionMarket, Score: verdict.MarketScore},
+		{Dimension: DimensionProblem, Score: verdict.ProblemScore},
+		{Dimension: DimensionBarriers, Score: verdict.BarrierScore},
+		{Dimension: DimensionExecution, Score: verdict.ExecutionScore},
+		{Dimension: DimensionRisks, Score: verdict.RiskScore},
+		{Dimension: DimensionGraveyard, Score: verdict.GraveyardScore},
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score < scores[j].Score
+	})
+	return scores[:min(n, len(scores))]
+}
+
+// dimensionResults returns the analyzer output of each focus dimension
+func dimensionResults(analysis types.Analysis, focus []types.DimensionScore) map[string]interface{} {
+	results := make(map[string]interface{}, len(focus))
+	for _, f := range focus {
+		switch f.Dimension {
+		case DimensionMarket:
+			results[f.Dimension] = analysis.Market
+		case DimensionProblem:
+			results[f.Dimension] = analysis.Problem
+		case DimensionBarriers:
+			results[f.Dimension] = analysis.Barriers
+		case DimensionExecution:
+			results[f.Dimension] = analysis.Execution
+		case DimensionRisks:
+			results[f.Dimension] = analysis.Risks
+		case DimensionGraveyard:
+			results[f.Dimension] = analysis.Graveyard
+		}
+	}
+	return results
+}
@@ -22,7 +22,7 @@ func NewRisksAnalyzer(llmClient *llm.Client) *RisksAnalyzer {
 }
 
 // Analyze performs risk analysis
-func (ra *RisksAnalyzer) Analyze(ctx context.Context, idea types.IdeaInput, evidence []types.Evidence) (types.RiskAnalysis, error) {
+func (ra *RisksAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, promptSuffix string) (types.RiskAnalysis, error) {
 	systemPrompt := `You are a business risk analyst. Analyze the provided startup idea and evidence to identify and assess business risks.
 
 CRITICAL REQUIREMENTS:
@@ -57,11 +57,6 @@ Risk likelihood scale (1-5):
 
 Only identify risks with Evidence backing. Include mitigation strategies when Evidence suggests them.`
 
-	userPrompt := map[string]interface{}{
-		"idea":     idea,
-		"evidence": evidence,
-	}
-
 	schema := []byte(`{
 		"type": "object",
 		"properties": {
@@ -101,7 +96,9 @@ Only identify risks with Evidence backing. Include mitigation strategies when Ev
 		"additionalProperties": false
 	}`)
 
-	response, err := ra.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	systemPrompt = appendPromptSuffix(systemPrompt, promptSuffix)
+
+	response, err := ra.llmClient.ConstrainedJSON(ctx, systemPrompt, input.UserPrompt(DimensionRisks), schema)
 	if err != nil {
 		return types.RiskAnalysis{}, fmt.Errorf("risks analysis failed: %w", err)
 	}
@@ -111,7 +108,7 @@ Only identify risks with Evidence backing. Include mitigation strategies when Ev
 		return types.RiskAnalysis{}, fmt.Errorf("failed to parse risks analysis response: %w", err)
 	}
 
-	result = ra.validateEvidenceIDs(result, evidence)
+	result = ra.validateEvidenceIDs(result, input.Evidence)
 	return result, nil
 }
 
@@ -0,0 +1,160 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"rectaify/internal/llm"
+	"rectaify/pkg/types"
+)
+
+// citationRe matches an inline citation of one or more evidence IDs, such as
+// [a1b2c3d4] or [a1b2c3d4, e5f6a7b8], with the space before it
+var citationRe = regexp.MustCompile(`\s*\[[^\[\]]+\]`)
+
+// SummaryAnalyzer condenses a completed analysis into a short TL;DR
+type SummaryAnalyzer struct {
+	llmClient *llm.Client
+}
+
+// NewSummaryAnalyzer creates a new summary analyzer
+func NewSummaryAnalyzer(llmClient *llm.Client) *SummaryAnalyzer {
+	return &SummaryAnalyzer{
+		llmClient: llmClient,
+	}
+}
+
+// Analyze produces a 2-3 sentence summary of the analysis, falling back to a
+// templated summary built from the scores if the LLM call fails
+func (sa *SummaryAnalyzer) Analyze(ctx context.Context, analysis types.Analysis) string {
+	summary, err := sa.summarizeWithLLM(ctx, analysis)
+	if err != nil || strings.TrimSpace(summary) == "" {
+		return sa.templateSummary(analysis)
+	}
+	return summary
+}
+
+// summarizeWithLLM asks the LLM for a plain-English summary
+func (sa *SummaryAnalyzer) summarizeWithLLM(ctx context.Context, analysis types.Analysis) (string, error) {
+	systemPrompt := `You are a startup advisor writing a TL;DR for a busy reader. Condense the provided analysis into 2-3 plain-English sentences.
+
+CRITICAL REQUIREMENTS:
+1. ONLY use information from the provided analysis
+2. Output ONLY valid JSON matching the required schema
+3. Sentence 1: the overall verdict and score
+4. Sentence 2: the single biggest opportunity
+5. Sentence 3: the single biggest risk
+6. Cite supporting Evidence IDs inline in square brackets, e.g. [a1b2c3d4]
+
+No jargon, no bullet points, no more than three sentences.`
+
+	userPrompt := map[string]interface{}{
+		"idea":      analysis.Idea,
+		"verdict":   analysis.Verdict,
+		"market":    analysis.Market,
+		"problem":   analysis.Problem,
+		"risks":     analysis.Risks,
+		"graveyard": analysis.Graveyard,
+	}
+
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"summary": {"type": "string"}
+		},
+		"required": ["summary"],
+		"additionalProperties": false
+	}`)
+
+	response, err := sa.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	if err != nil {
+		return "", fmt.Errorf("summary generation failed: %w", err)
+	}
+
+	var result struct {
+		Summary string `json:"summary"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return "", fmt.Errorf("failed to parse summary response: %w", err)
+	}
+
+	return sa.validateCitations(strings.TrimSpace(result.Summary), analysis.Evidence), nil
+}
+
+// validateCitations removes cited IDs that don't match provided evidence, and
+// citations left with none
+func (sa *SummaryAnalyzer) validateCitations(summary string, evidence []types.Evidence) string {
+	evidenceSet := make(map[string]bool)
+	for _, ev := range evidence {
+		evidenceSet[ev.ID] = true
+	}
+
+	return citationRe.ReplaceAllStringFunc(summary, func(citation string) string {
+		open := strings.Index(citation, "[")
+		var validIDs []string
+		for _, id := range strings.Split(citation[open+1:len(citation)-1], ",") {
+			if id = strings.TrimSpace(id); evidenceSet[id] {
+				validIDs = append(validIDs, id)
+			}
+		}
+		if len(validIDs) == 0 {
+			return ""
+		}
+		return citation[:open] + "[" + strings.Join(validIDs, ", ") + "]"
+	})
+}
+
+// templateSummary builds a deterministic summary from the scores
+func (sa *SummaryAnalyzer) templateSummary(analysis types.Analysis) string {
+	verdict := analysis.Verdict
+
+	dimensions := []struct {
+		name  string
+		score float64
+	}{
+		{"market", verdict.MarketScore},
+		{"problem validation", verdict.ProblemScore},
+		{"execution barriers", verdict.BarrierScore},
+		{"execution complexity", verdict.ExecutionScore},
+		{"business risk", verdict.RiskScore},
+		{"lessons from past failures", verdict.GraveyardScore},
+	}
+
+	strongest, weakest := dimensions[0], dimensions[0]
+	for _, d := range dimensions[1:] {
+		if d.score > strongest.score {
+			strongest = d
+		}
+		if d.score < weakest.score {
+			weakest = d
+		}
+	}
+
+	label := verdict.Recommendation
+	if idx := strings.Index(label, ":"); idx > 0 {
+		label = label[:idx]
+	}
+
+	sentences := []string{
+		fmt.Sprintf("%s scores %.0f/100 overall, a %s verdict.", analysis.Idea.Title, verdict.OverallScore, strings.TrimSpace(label)),
+		fmt.Sprintf("Its strongest dimension is %s (%.0f/100).", strongest.name, strongest.score),
+	}
+
+	// Prefer the highest-impact identified risk over the weakest score
+	var topRisk *types.Risk
+	for i, risk := range analysis.Risks.Risks {
+		if topRisk == nil || risk.Severity*risk.Likelihood > topRisk.Severity*topRisk.Likelihood {
+			topRisk = &analysis.Risks.Risks[i]
+		}
+	}
+	if topRisk != nil {
+		sentences = append(sentences, fmt.Sprintf("The biggest risk is %s: %s", strings.ToLower(topRisk.Category), strings.TrimSuffix(topRisk.Description, ".")+"."))
+	} else {
+		sentences = append(sentences, fmt.Sprintf("The weakest area is %s (%.0f/100).", weakest.name, weakest.score))
+	}
+
+	return strings.Join(sentences, " ")
+}
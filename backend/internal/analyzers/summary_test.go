@@ -0,0 +1,52 @@
+package analyzers
+
+import (
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func TestValidateCitations(t *testing.T) {
+	evidence := []types.Evidence{{ID: "a1b2c3d4"}, {ID: "e5f6a7b8"}}
+
+	tests := []struct {
+		name    string
+		summary string
+		want    string
+	}{
+		{
+			name:    "known citations kept",
+			summary: "Tutoring is a growing market [a1b2c3d4]. Churn is high [e5f6a7b8].",
+			want:    "Tutoring is a growing market [a1b2c3d4]. Churn is high [e5f6a7b8].",
+		},
+		{
+			name:    "unknown citation removed",
+			summary: "Tutoring is a growing market [deadbeef]. Churn is high [e5f6a7b8].",
+			want:    "Tutoring is a growing market. Churn is high [e5f6a7b8].",
+		},
+		{
+			name:    "unknown IDs dropped from a list",
+			summary: "Churn is high [a1b2c3d4, deadbeef,e5f6a7b8].",
+			want:    "Churn is high [a1b2c3d4, e5f6a7b8].",
+		},
+		{
+			name:    "list of unknown IDs removed",
+			summary: "Churn is high [deadbeef, cafebabe].",
+			want:    "Churn is high.",
+		},
+		{
+			name:    "no citations",
+			summary: "Tutoring is a growing market.",
+			want:    "Tutoring is a growing market.",
+		},
+	}
+
+	sa := NewSummaryAnalyzer(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sa.validateCitations(tt.summary, evidence); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"sort"
+	"time"
 
 	"rectaify/internal/llm"
 	"rectaify/internal/score"
@@ -12,27 +15,57 @@ import (
 
 // VerdictAnalyzer synthesizes all analyses into a final verdict
 type VerdictAnalyzer struct {
-	llmClient  *llm.Client
-	calculator *score.Calculator
+	llmClient    *llm.Client
+	calculator   *score.Calculator
+	snippetLimit int // characters of each evidence snippet sent; see SnippetLimits
+	payload      VerdictPayload
 }
 
+// VerdictPayload bounds what the verdict prompt carries and how its call may
+// fail. The verdict call sees every dimension and all evidence, making it the
+// largest prompt of an analysis.
+type VerdictPayload struct {
+	MaxEvidence int  // evidence items sent, cited ones first; 0 sends all
+	Condensed   bool // send dimension summaries instead of full dimension results
+	// Timeout bounds each attempt of the verdict request, which may need far
+	// longer than other calls; 0 uses the client's request timeout
+	Timeout time.Duration
+	// Strict returns enhancement failures as errors instead of falling back to
+	// the calculated verdict
+	Strict bool
+}
+
+// condensedItems bounds the items of each dimension in a condensed verdict prompt
+const condensedItems = 5
+
 // NewVerdictAnalyzer creates a new verdict analyzer
-func NewVerdictAnalyzer(llmClient *llm.Client, calculator *score.Calculator) *VerdictAnalyzer {
+func NewVerdictAnalyzer(llmClient *llm.Client, calculator *score.Calculator, snippetLimit int, payload VerdictPayload) *VerdictAnalyzer {
 	return &VerdictAnalyzer{
-		llmClient:  llmClient,
-		calculator: calculator,
+		llmClient:    llmClient,
+		calculator:   calculator,
+		snippetLimit: snippetLimit,
+		payload:      payload,
 	}
 }
 
-// Analyze synthesizes all analysis results into a final verdict
+// Analyze synthesizes all analysis results into a final verdict. When the LLM
+// enhancement fails, the calculated verdict is returned marked as a fallback,
+// along with the error in strict mode.
 func (va *VerdictAnalyzer) Analyze(ctx context.Context, analysis types.Analysis) (types.Viability, error) {
 	// First, compute scores using the calculator
 	viability := va.calculator.ComputeViability(analysis)
+	if stream := verdictStreamFrom(ctx); stream != nil {
+		stream.setScores(viability)
+	}
 
 	// Then, enhance with LLM-generated insights
 	enhancedViability, err := va.enhanceWithLLMInsights(ctx, analysis, viability)
 	if err != nil {
-		// If LLM enhancement fails, return the calculated viability
+		viability.Fallback = true
+		if va.payload.Strict {
+			return viability, err
+		}
+		log.Printf("Verdict enhancement failed, using the calculated verdict: %v", err)
 		return viability, nil
 	}
 
@@ -49,6 +82,7 @@ CRITICAL REQUIREMENTS:
 3. Reference Evidence IDs when making claims
 4. DO NOT change the numerical scores - only enhance insights and recommendation
 5. Focus on strategic synthesis and actionable insights
+6. Every key insight must list the Evidence IDs that support it in evidence_ids
 
 Your enhancement should:
 - Synthesize insights across all analysis dimensions
@@ -59,9 +93,19 @@ Your enhancement should:
 
 Keep insights specific and actionable rather than generic startup advice.`
 
+	// Evidence is sent in its compact prompt form rather than inside the analysis
+	var promptAnalysis interface{}
+	if va.payload.Condensed {
+		promptAnalysis = condenseAnalysis(analysis)
+	} else {
+		full := analysis
+		full.Evidence = nil
+		promptAnalysis = full
+	}
 	userPrompt := map[string]interface{}{
-		"analysis":   analysis,
-		"viability":  viability,
+		"analysis":  promptAnalysis,
+		"evidence":  compactEvidence(verdictEvidence(analysis, va.payload.MaxEvidence), va.snippetLimit),
+		"viability": viability,
 	}
 
 	schema := []byte(`{
@@ -77,7 +121,18 @@ Keep insights specific and actionable rather than generic startup advice.`
 			"recommendation": {"type": "string"},
 			"key_insights": {
 				"type": "array",
-				"items": {"type": "string"}
+				"items": {
+					"type": "object",
+					"properties": {
+						"text": {"type": "string"},
+						"evidence_ids": {
+							"type": "array",
+							"items": {"type": "string"}
+						}
+					},
+					"required": ["text", "evidence_ids"],
+					"additionalProperties": false
+				}
 			},
 			"evidence_ids": {
 				"type": "array",
@@ -88,7 +143,21 @@ Keep insights specific and actionable rather than generic startup advice.`
 		"additionalProperties": false
 	}`)
 
-	response, err := va.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	if va.payload.Timeout > 0 {
+		ctx = llm.WithRequestTimeout(ctx, va.payload.Timeout)
+	}
+	var response json.RawMessage
+	var err error
+	if stream := verdictStreamFrom(ctx); stream != nil {
+		var recommendation recommendationDecoder
+		response, err = va.llmClient.ConstrainedJSONStream(ctx, systemPrompt, userPrompt, schema, func(piece string) {
+			if text := recommendation.feed(piece); text != "" {
+				stream.writeRecommendation(text)
+			}
+		})
+	} else {
+		response, err = va.llmClient.ConstrainedJSON(ctx, systemPrompt, userPrompt, schema)
+	}
 	if err != nil {
 		return viability, fmt.Errorf("verdict enhancement failed: %w", err)
 	}
@@ -101,9 +170,129 @@ Keep insights specific and actionable rather than generic startup advice.`
 	// Validate evidence IDs
 	enhancedViability = va.validateEvidenceIDs(enhancedViability, analysis.Evidence)
 
+	// Confidence, the verdict band and score rationale are computed locally, not by the LLM
+	enhancedViability.Confidence = viability.Confidence
+	enhancedViability.Verdict = score.VerdictForScore(enhancedViability.OverallScore)
+	enhancedViability.ScoreRationale = viability.ScoreRationale
+
 	return enhancedViability, nil
 }
 
+// verdictEvidence picks the evidence sent with the verdict prompt: evidence
+// cited by a dimension first, then the rest, each in quality order as
+// normalization sorted it, keeping at most max items. Citations are still
+// validated against all of the analysis's evidence.
+func verdictEvidence(analysis types.Analysis, max int) []types.Evidence {
+	if max <= 0 || len(analysis.Evidence) <= max {
+		return analysis.Evidence
+	}
+
+	cited := make(map[string]bool)
+	for _, ids := range [][]string{
+		analysis.Market.EvidenceIDs, analysis.Problem.EvidenceIDs, analysis.Barriers.EvidenceIDs,
+		analysis.Execution.EvidenceIDs, analysis.Risks.EvidenceIDs, analysis.Graveyard.EvidenceIDs,
+	} {
+		for _, id := range ids {
+			cited[id] = true
+		}
+	}
+	for _, c := range analysis.Market.Competitors {
+		for _, id := range c.EvidenceIDs {
+			cited[id] = true
+		}
+	}
+	for _, b := range analysis.Barriers.Barriers {
+		for _, id := range b.EvidenceIDs {
+			cited[id] = true
+		}
+	}
+	for _, r := range analysis.Risks.Risks {
+		for _, id := range r.EvidenceIDs {
+			cited[id] = true
+		}
+	}
+	for _, g := range analysis.Graveyard.Cases {
+		for _, id := range g.EvidenceIDs {
+			cited[id] = true
+		}
+	}
+
+	selected := make([]types.Evidence, 0, max)
+	for _, wantCited := range []bool{true, false} {
+		for _, ev := range analysis.Evidence {
+			if len(selected) == max {
+				return selected
+			}
+			if cited[ev.ID] == wantCited {
+				selected = append(selected, ev)
+			}
+		}
+	}
+	return selected
+}
+
+// condenseAnalysis summarizes each dimension for the verdict prompt: the
+// headline fields, at most condensedItems items each as a single line, and
+// the evidence IDs the dimension cites
+func condenseAnalysis(analysis types.Analysis) map[string]interface{} {
+	competitors := make([]string, 0, condensedItems)
+	for _, c := range analysis.Market.Competitors[:min(condensedItems, len(analysis.Market.Competitors))] {
+		competitors = append(competitors, fmt.Sprintf("%s (threat %.0f): %s", c.Name, c.ThreatScore, c.Description))
+	}
+
+	barriers := make([]string, 0, condensedItems)
+	for _, b := range analysis.Barriers.Barriers[:min(condensedItems, len(analysis.Barriers.Barriers))] {
+		barriers = append(barriers, fmt.Sprintf("%s (weight %.1f): %s", b.Type, b.Weight, b.Description))
+	}
+
+	// Highest-impact risks first
+	risks := append([]types.Risk(nil), analysis.Risks.Risks...)
+	sort.SliceStable(risks, func(i, j int) bool {
+		return risks[i].Severity*risks[i].Likelihood > risks[j].Severity*risks[j].Likelihood
+	})
+	riskLines := make([]string, 0, condensedItems)
+	for _, r := range risks[:min(condensedItems, len(risks))] {
+		riskLines = append(riskLines, fmt.Sprintf("%s (severity %d, likelihood %d): %s", r.Category, r.Severity, r.Likelihood, r.Description))
+	}
+
+	cases := make([]string, 0, condensedItems)
+	for _, g := range analysis.Graveyard.Cases[:min(condensedItems, len(analysis.Graveyard.Cases))] {
+		cases = append(cases, fmt.Sprintf("%s: failed from %s", g.CompanyName, g.FailureCause))
+	}
+
+	return map[string]interface{}{
+		"idea": analysis.Idea,
+		"market": map[string]interface{}{
+			"market_stage":     analysis.Market.MarketStage,
+			"positioning":      analysis.Market.Positioning,
+			"competitor_count": len(analysis.Market.Competitors),
+			"top_competitors":  competitors,
+			"evidence_ids":     analysis.Market.EvidenceIDs,
+		},
+		"problem": map[string]interface{}{
+			"pain_points":  analysis.Problem.PainPoints[:min(condensedItems, len(analysis.Problem.PainPoints))],
+			"validation":   analysis.Problem.Validation,
+			"evidence_ids": analysis.Problem.EvidenceIDs,
+		},
+		"barriers": map[string]interface{}{
+			"barrier_count": len(analysis.Barriers.Barriers),
+			"top_barriers":  barriers,
+			"evidence_ids":  analysis.Barriers.EvidenceIDs,
+		},
+		"execution": analysis.Execution,
+		"risks": map[string]interface{}{
+			"risk_count":   len(analysis.Risks.Risks),
+			"top_risks":    riskLines,
+			"evidence_ids": analysis.Risks.EvidenceIDs,
+		},
+		"graveyard": map[string]interface{}{
+			"case_count":   len(analysis.Graveyard.Cases),
+			"cases":        cases,
+			"evidence_ids": analysis.Graveyard.EvidenceIDs,
+		},
+	}
+}
+
 func (va *VerdictAnalyzer) validateEvidenceIDs(viability types.Viability, evidence []types.Evidence) types.Viability {
 	evidenceSet := make(map[string]bool)
 	for _, ev := range evidence {
@@ -117,5 +306,17 @@ func (va *VerdictAnalyzer) validateEvidenceIDs(viability types.Viability, eviden
 		}
 	}
 	viability.EvidenceIDs = validEvidenceIDs
+
+	// Drop citations of evidence that was never provided
+	for i, insight := range viability.KeyInsights {
+		var validInsightIDs []string
+		for _, id := range insight.EvidenceIDs {
+			if evidenceSet[id] {
+				validInsightIDs = append(validInsightIDs, id)
+			}
+		}
+		viability.KeyInsights[i].EvidenceIDs = validInsightIDs
+	}
+
 	return viability
 }
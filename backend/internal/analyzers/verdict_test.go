@@ -0,0 +1,56 @@
+package analyzers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"rectaify/internal/llm"
+	"rectaify/internal/score"
+	"rectaify/pkg/types"
+)
+
+// failingLLM returns a client whose every call fails as the provider is down
+func failingLLM(t *testing.T) *llm.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"message": "upstream unavailable"}}`, http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	return llm.NewClient(llm.ClientConfig{APIKey: "test", BaseURL: server.URL, RPS: 100})
+}
+
+func TestVerdictFallback(t *testing.T) {
+	calculator := score.NewCalculator(nil, nil)
+	analysis := types.Analysis{
+		Market: types.MarketAnalysis{MarketStage: "growing"},
+		Risks:  types.RiskAnalysis{Risks: []types.Risk{{Category: "market", Severity: 4, Likelihood: 3}}},
+	}
+	calculated := calculator.ComputeViability(analysis)
+
+	t.Run("lenient", func(t *testing.T) {
+		verdict := NewVerdictAnalyzer(failingLLM(t), calculator, SnippetFull, VerdictPayload{})
+		viability, err := verdict.Analyze(context.Background(), analysis)
+		if err != nil {
+			t.Fatalf("Analyze: %v", err)
+		}
+		if !viability.Fallback {
+			t.Error("fallback verdict not marked as one")
+		}
+		if viability.OverallScore != calculated.OverallScore || viability.Verdict != calculated.Verdict {
+			t.Errorf("got %g (%s), want the calculated %g (%s)", viability.OverallScore, viability.Verdict, calculated.OverallScore, calculated.Verdict)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		verdict := NewVerdictAnalyzer(failingLLM(t), calculator, SnippetFull, VerdictPayload{Strict: true})
+		viability, err := verdict.Analyze(context.Background(), analysis)
+		if err == nil {
+			t.Fatal("strict mode swallowed the enhancement error")
+		}
+		if !viability.Fallback || viability.OverallScore != calculated.OverallScore {
+			t.Errorf("got %+v, want the calculated verdict marked as a fallback", viability)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package analyzers
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"rectaify/pkg/types"
+)
+
+// VerdictStream collects the verdict of a streaming analysis as it forms: the
+// computed scores first, then the recommendation as the LLM writes it. The
+// analyzer never waits on the reader; pieces written between two reads are
+// taken together.
+type VerdictStream struct {
+	mu             sync.Mutex
+	scores         *types.Viability
+	recommendation strings.Builder
+	ready          chan struct{}
+}
+
+// NewVerdictStream creates an empty verdict stream
+func NewVerdictStream() *VerdictStream {
+	return &VerdictStream{ready: make(chan struct{}, 1)}
+}
+
+// Ready signals that the stream has something new to Take
+func (s *VerdictStream) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Take returns what was written since the last call: the computed scores, if
+// they were just set, and the next part of the recommendation
+func (s *VerdictStream) Take() (*types.Viability, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scores, text := s.scores, s.recommendation.String()
+	s.scores = nil
+	s.recommendation.Reset()
+	return scores, text
+}
+
+func (s *VerdictStream) setScores(viability types.Viability) {
+	// The recommendation follows as it is written
+	viability.Recommendation = ""
+	s.mu.Lock()
+	s.scores = &viability
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *VerdictStream) writeRecommendation(text string) {
+	s.mu.Lock()
+	s.recommendation.WriteString(text)
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *VerdictStream) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// verdictStreamKey carries the stream a verdict is written to as it forms
+type verdictStreamKey struct{}
+
+// WithVerdictStream returns a context under which the verdict analyzer writes
+// its computed scores and the tokens of its recommendation to stream
+func WithVerdictStream(ctx context.Context, stream *VerdictStream) context.Context {
+	return context.WithValue(ctx, verdictStreamKey{}, stream)
+}
+
+// verdictStreamFrom returns the verdict stream of ctx, or nil
+func verdictStreamFrom(ctx context.Context) *VerdictStream {
+	stream, _ := ctx.Value(verdictStreamKey{}).(*VerdictStream)
+	return stream
+}
+
+// recommendationKey opens the recommendation string of the verdict response.
+// Inside another string the quotes would be escaped, so it only matches the key.
+var recommendationKey = regexp.MustCompile(`"recommendation"\s*:\s*"`)
+
+// recommendationDecoder pulls the recommendation out of the verdict's JSON
+// response as it streams in, decoding the string value piece by piece
+type recommendationDecoder struct {
+	pending string // response not yet decoded
+	inValue bool
+	done    bool
+}
+
+// feed takes the next piece of the response and returns the recommendation
+// text it completes, if any
+func (d *recommendationDecoder) feed(piece string) string {
+	if d.done {
+		return ""
+	}
+	d.pending += piece
+	if !d.inValue {
+		loc := recommendationKey.FindStringIndex(d.pending)
+		if loc == nil {
+			return ""
+		}
+		d.pending, d.inValue = d.pending[loc[1]:], true
+	}
+
+	var text strings.Builder
+	i := 0
+	for i < len(d.pending) {
+		switch c := d.pending[i]; c {
+		case '"':
+			d.pending, d.done = "", true
+			return text.String()
+		case '\\':
+			decoded, n := decodeEscape(d.pending[i:])
+			if n == 0 {
+				// The rest of the escape hasn't arrived yet
+				d.pending = d.pending[i:]
+				return text.String()
+			}
+			text.WriteString(decoded)
+			i += n
+		default:
+			text.WriteByte(c)
+			i++
+		}
+	}
+	d.pending = ""
+	return text.String()
+}
+
+// decodeEscape decodes the JSON escape sequence s starts with, returning its
+// text and length, or a length of 0 when s ends before the sequence does
+func decodeEscape(s string) (string, int) {
+	n := 2
+	if len(s) >= 2 && s[1] == 'u' {
+		n = 6
+		// A surrogate pair is only decodable together
+		if len(s) >= n && isHighSurrogate(s[2:6]) {
+			n = 12
+		}
+	}
+	if len(s) < n {
+		return "", 0
+	}
+
+	var decoded string
+	if err := json.Unmarshal([]byte(`"`+s[:n]+`"`), &decoded); err != nil {
+		// Not an escape json knows; pass it through as the model wrote it
+		return s[:n], n
+	}
+	return decoded, n
+}
+
+// isHighSurrogate reports whether hex is the first half of a UTF-16 surrogate pair
+func isHighSurrogate(hex string) bool {
+	var r rune
+	for _, c := range hex {
+		switch {
+		case c >= '0' && c <= '9':
+			r = r<<4 | (c - '0')
+		case c >= 'a' && c <= 'f':
+			r = r<<4 | (c - 'a' + 10)
+		case c >= 'A' && c <= 'F':
+			r = r<<4 | (c - 'A' + 10)
+		default:
+			return false
+		}
+	}
+	return utf16.IsSurrogate(r) && r < 0xdc00
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"rectaify/pkg/types"
+)
+
+// anonymousCaller is audited for requests made without an API key
+const anonymousCaller = "anonymous"
+
+type callerKey struct{}
+
+// WithCaller returns a context whose analyses are audited as requested by
+// caller, an identifier of the API key used that is safe to store
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// callerOf returns the caller an analysis is audited as
+func callerOf(ctx context.Context) string {
+	if caller, ok := ctx.Value(callerKey{}).(string); ok && caller != "" {
+		return caller
+	}
+	return anonymousCaller
+}
+
+// audit appends an analysis request and its outcome to the audit log. The
+// idea title and error are scrubbed like stored ideas, and the verdict is
+// read back from the stored analysis, cached or new. Failing to audit is
+// logged rather than failing a request that already ran.
+func (o *Orchestrator) audit(ctx context.Context, action string, idea types.IdeaInput, analysisID string, cached bool, err error) {
+	// The request may be gone by now, but its entry is still owed
+	ctx = context.WithoutCancel(ctx)
+
+	entry := types.AuditEntry{
+		Caller:     callerOf(ctx),
+		Action:     action,
+		AnalysisID: analysisID,
+		IdeaTitle:  o.scrubber.Text(idea.Title),
+		Category:   idea.Category,
+		Outcome:    "completed",
+	}
+	switch {
+	case errors.Is(err, ErrCancelled):
+		entry.Outcome = "cancelled"
+		entry.Error = err.Error()
+	case err != nil:
+		entry.Outcome = "failed"
+		entry.Error = o.scrubber.Text(err.Error())
+	case cached:
+		entry.Outcome = "cached"
+	}
+
+	if err == nil {
+		if analysis, lookupErr := o.repository.GetAnalysis(ctx, analysisID); lookupErr == nil {
+			score := analysis.Verdict.OverallScore
+			entry.Verdict = string(analysis.Verdict.Verdict)
+			entry.OverallScore = &score
+		} else {
+			log.Printf("Audit: failed to read verdict of analysis %s: %v", analysisID, lookupErr)
+		}
+	}
+
+	if _, err := o.repository.AppendAudit(ctx, entry); err != nil {
+		log.Printf("Audit: failed to record %s of analysis %s: %v", action, analysisID, err)
+	}
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// runRegistry tracks the analyses running on this instance by ID, so they
+// can be cancelled
+type runRegistry struct {
+	mu   sync.Mutex
+	runs map[string]*analysisRun
+}
+
+// analysisRun is one running analysis
+type analysisRun struct {
+	ctx         context.Context
+	cancel      context.CancelCauseFunc
+	keepPartial atomic.Bool // save what was gathered before the cancellation
+}
+
+type startedKey struct{}
+
+// WithStarted returns a context whose analysis sends its ID on started once
+// it is running, so the caller can cancel it. Cached answers never start. The
+// send doesn't wait, so started should be buffered.
+func WithStarted(ctx context.Context, started chan<- string) context.Context {
+	return context.WithValue(ctx, startedKey{}, started)
+}
+
+func newRunRegistry() *runRegistry {
+	return &runRegistry{runs: make(map[string]*analysisRun)}
+}
+
+// start registers an analysis, returning the context it runs under from now
+// on, which a cancellation ends
+func (r *runRegistry) start(ctx context.Context, analysisID string) (context.Context, *analysisRun) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	run := &analysisRun{ctx: ctx, cancel: cancel}
+
+	r.mu.Lock()
+	r.runs[analysisID] = run
+	r.mu.Unlock()
+
+	if started, ok := ctx.Value(startedKey{}).(chan<- string); ok {
+		select {
+		case started <- analysisID:
+		default:
+		}
+	}
+	return ctx, run
+}
+
+// finish forgets a finished analysis
+func (r *runRegistry) finish(analysisID string) {
+	r.mu.Lock()
+	run := r.runs[analysisID]
+	delete(r.runs, analysisID)
+	r.mu.Unlock()
+
+	if run != nil {
+		run.cancel(nil)
+	}
+}
+
+// cancel cancels a running analysis, reporting whether one was running
+func (r *runRegistry) cancel(analysisID string, keepPartial bool) bool {
+	r.mu.Lock()
+	run, ok := r.runs[analysisID]
+	r.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	run.keepPartial.Store(keepPartial)
+	run.cancel(ErrCancelled)
+	return true
+}
+
+// cancelled reports whether the analysis was cancelled by request, rather
+// than timing out or losing its requester
+func (run *analysisRun) cancelled() bool {
+	return errors.Is(context.Cause(run.ctx), ErrCancelled)
+}
+
+// CancelAnalysis cancels a running analysis, stopping its searches and LLM
+// calls. With keepPartial, the dimensions analyzed by then are saved as a
+// partial analysis; otherwise nothing is. Only analyses running on this
+// instance can be cancelled; a stored one fails with ErrAnalysisFinished.
+func (o *Orchestrator) CancelAnalysis(ctx context.Context, analysisID string, keepPartial bool) error {
+	if o.running.cancel(analysisID, keepPartial) {
+		return nil
+	}
+	if _, err := o.repository.GetAnalysis(ctx, analysisID); err != nil {
+		return err
+	}
+	return ErrAnalysisFinished
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"rectaify/internal/analyzers"
+	"rectaify/pkg/types"
+)
+
+// evidenceCitations indexes the analysis the other way round from its
+// dimensions: each evidence ID maps to the dimensions citing it, in
+// analyzers.Dimensions order. The dimensions' evidence IDs were already
+// checked against the provided evidence; IDs not among the analysis's
+// evidence are skipped all the same, and uncited evidence has no entry.
+func evidenceCitations(analysis types.Analysis) map[string][]string {
+	known := make(map[string]bool, len(analysis.Evidence))
+	for _, ev := range analysis.Evidence {
+		known[ev.ID] = true
+	}
+
+	citations := make(map[string][]string)
+	// Dimensions are cited one after another, so a repeat within one
+	// dimension is always the last entry
+	cite := func(dimension string, evidenceIDs []string) {
+		for _, id := range evidenceIDs {
+			dimensions := citations[id]
+			if !known[id] || (len(dimensions) > 0 && dimensions[len(dimensions)-1] == dimension) {
+				continue
+			}
+			citations[id] = append(dimensions, dimension)
+		}
+	}
+
+	cite(analyzers.DimensionMarket, analysis.Market.EvidenceIDs)
+	for _, competitor := range analysis.Market.Competitors {
+		cite(analyzers.DimensionMarket, competitor.EvidenceIDs)
+	}
+	cite(analyzers.DimensionProblem, analysis.Problem.EvidenceIDs)
+	cite(analyzers.DimensionBarriers, analysis.Barriers.EvidenceIDs)
+	for _, barrier := range analysis.Barriers.Barriers {
+		cite(analyzers.DimensionBarriers, barrier.EvidenceIDs)
+	}
+	cite(analyzers.DimensionExecution, analysis.Execution.EvidenceIDs)
+	cite(analyzers.DimensionRisks, analysis.Risks.EvidenceIDs)
+	for _, risk := range analysis.Risks.Risks {
+		cite(analyzers.DimensionRisks, risk.EvidenceIDs)
+	}
+	cite(analyzers.DimensionGraveyard, analysis.Graveyard.EvidenceIDs)
+	for _, graveyardCase := range analysis.Graveyard.Cases {
+		cite(analyzers.DimensionGraveyard, graveyardCase.EvidenceIDs)
+	}
+
+	return citations
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"rectaify/pkg/types"
+)
+
+// checkCoherence has the LLM score how closely the analysis's findings match
+// its idea and records the score in meta. Below minCoherence the analysis is
+// suspect: it gets a warning and its confidence is lowered in proportion. A
+// minimum of 0 skips the check.
+func (o *Orchestrator) checkCoherence(ctx context.Context, analysis *types.Analysis) error {
+	if o.minCoherence <= 0 {
+		return nil
+	}
+
+	coherence, err := o.coordinator.CheckCoherence(ctx, *analysis)
+	if err != nil {
+		return err
+	}
+
+	coherence.Minimum = o.minCoherence
+	if coherence.Score < coherence.Minimum {
+		coherence.Suspect = true
+		analysis.Warnings = append(analysis.Warnings, coherenceWarning(coherence))
+		analysis.Verdict.Confidence *= coherenceConfidence(coherence)
+	}
+	analysis.SetMeta("coherence", coherence)
+	return nil
+}
+
+// coherenceConfidence is the factor an analysis's confidence is lowered by:
+// for a suspect one, the share of the required coherence it reached
+func coherenceConfidence(coherence types.Coherence) float64 {
+	if !coherence.Suspect || coherence.Minimum <= 0 {
+		return 1
+	}
+	return coherence.Score / coherence.Minimum
+}
+
+// coherenceWarning explains that the findings may not be about the idea
+func coherenceWarning(coherence types.Coherence) string {
+	warning := fmt.Sprintf("Suspect analysis: its findings scored %.2f for relevance to the idea", coherence.Score)
+	if len(coherence.OffTopic) > 0 {
+		warning += fmt.Sprintf(", with off-topic %s findings", strings.Join(coherence.OffTopic, ", "))
+	}
+	warning += "."
+	if coherence.Reason != "" {
+		warning += " " + coherence.Reason
+	}
+	return warning
+}
+
+// coherenceFromMeta returns the coherence check recorded in an analysis's
+// meta, if it was checked
+func coherenceFromMeta(meta json.RawMessage) (types.Coherence, bool) {
+	if len(meta) == 0 {
+		return types.Coherence{}, false
+	}
+
+	var parsed struct {
+		Coherence *types.Coherence `json:"coherence"`
+	}
+	if err := json.Unmarshal(meta, &parsed); err != nil || parsed.Coherence == nil {
+		return types.Coherence{}, false
+	}
+	return *parsed.Coherence, true
+}
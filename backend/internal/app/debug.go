@@ -0,0 +1,66 @@
+package app
+
+import (
+	"encoding/json"
+	"slices"
+
+	"rectaify/pkg/types"
+)
+
+// DebugMetaKey is the meta key debug mode records raw analyzer output under.
+// It can be large and echoes the prompts' evidence, so it is only served to
+// admins who ask for it.
+const DebugMetaKey = "debug"
+
+// analyzerDebug is what debug mode records about one analyzer's LLM call
+type analyzerDebug struct {
+	RawOutput           json.RawMessage `json:"raw_output"`
+	StrippedEvidenceIDs []string        `json:"stripped_evidence_ids,omitempty"` // cited but not among the analysis's evidence, so removed in validation
+}
+
+// analyzerDebugMeta pairs each labeled call's raw output with the evidence IDs
+// validation stripped from it
+func analyzerDebugMeta(responses map[string]json.RawMessage, evidence []types.Evidence) map[string]analyzerDebug {
+	known := make(map[string]bool, len(evidence))
+	for _, ev := range evidence {
+		known[ev.ID] = true
+	}
+
+	debug := make(map[string]analyzerDebug, len(responses))
+	for label, raw := range responses {
+		var output interface{}
+		json.Unmarshal(raw, &output)
+		stripped := unknownEvidenceIDs(output, known, nil)
+		slices.Sort(stripped)
+		debug[label] = analyzerDebug{
+			RawOutput:           raw,
+			StrippedEvidenceIDs: slices.Compact(stripped),
+		}
+	}
+	return debug
+}
+
+// unknownEvidenceIDs appends the IDs cited anywhere in a decoded analyzer
+// output under an "evidence_ids" key that are not known evidence
+func unknownEvidenceIDs(output interface{}, known map[string]bool, stripped []string) []string {
+	switch output := output.(type) {
+	case map[string]interface{}:
+		for key, value := range output {
+			ids, isList := value.([]interface{})
+			if key != "evidence_ids" || !isList {
+				stripped = unknownEvidenceIDs(value, known, stripped)
+				continue
+			}
+			for _, id := range ids {
+				if id, ok := id.(string); ok && !known[id] {
+					stripped = append(stripped, id)
+				}
+			}
+		}
+	case []interface{}:
+		for _, item := range output {
+			stripped = unknownEvidenceIDs(item, known, stripped)
+		}
+	}
+	return stripped
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"rectaify/internal/analyzers"
+	"rectaify/internal/evidence"
+	"rectaify/pkg/types"
+)
+
+// checkSourceDiversity counts the domains behind each dimension's cited
+// evidence, from the analysis's citation index. A dimension backed by fewer
+// than minSourceDomains domains has its confidence lowered in proportion and
+// gets a warning; a minimum of 0 or 1 accepts any single source.
+func (o *Orchestrator) checkSourceDiversity(analysis *types.Analysis) {
+	cited := make(map[string][]string)
+	for id, dimensions := range analysis.EvidenceCitations {
+		for _, dimension := range dimensions {
+			cited[dimension] = append(cited[dimension], id)
+		}
+	}
+
+	diversity := make(map[string]types.SourceDiversity)
+	for _, dimension := range analyzers.Dimensions {
+		if len(cited[dimension]) == 0 {
+			continue
+		}
+
+		domains := evidence.CitedDomains(analysis.Evidence, cited[dimension])
+		entry := types.SourceDiversity{Domains: domains, Cited: len(cited[dimension]), Confidence: 1}
+		if len(domains) < o.minSourceDomains {
+			entry.Confidence = float64(len(domains)) / float64(o.minSourceDomains)
+			entry.Narrow = true
+			analysis.Warnings = append(analysis.Warnings, diversityWarning(dimension, entry, o.minSourceDomains))
+		}
+		diversity[dimension] = entry
+	}
+
+	if len(diversity) > 0 {
+		analysis.SourceDiversity = diversity
+	}
+}
+
+// diversityWarning explains that too few domains back a dimension
+func diversityWarning(dimension string, diversity types.SourceDiversity, minDomains int) string {
+	if len(diversity.Domains) == 1 {
+		return fmt.Sprintf("Single-source %s analysis: all %d cited sources come from %s; at least %d domains are expected.",
+			dimension, diversity.Cited, diversity.Domains[0], minDomains)
+	}
+	return fmt.Sprintf("%s%s analysis cites sources from only %d domains; at least %d are expected.",
+		strings.ToUpper(dimension[:1]), dimension[1:], len(diversity.Domains), minDomains)
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"rectaify/internal/analyzers"
+	"rectaify/pkg/types"
+)
+
+func TestCheckSourceDiversity(t *testing.T) {
+	analysis := types.Analysis{
+		Evidence: []types.Evidence{
+			{ID: "m1", URL: "https://www.techcrunch.com/2024/a"},
+			{ID: "m2", URL: "https://techcrunch.com/2024/b"},
+			{ID: "m3", URL: "https://TechCrunch.com/2025/c"},
+			{ID: "p1", URL: "https://www.reddit.com/r/tutoring/1"},
+			{ID: "p2", URL: "https://www.edweek.org/tutoring"},
+		},
+		EvidenceCitations: map[string][]string{
+			"m1": {analyzers.DimensionMarket},
+			"m2": {analyzers.DimensionMarket},
+			"m3": {analyzers.DimensionMarket},
+			"p1": {analyzers.DimensionProblem},
+			"p2": {analyzers.DimensionProblem},
+		},
+	}
+
+	o := &Orchestrator{minSourceDomains: 2}
+	o.checkSourceDiversity(&analysis)
+
+	market := analysis.SourceDiversity[analyzers.DimensionMarket]
+	if !market.Narrow || market.Confidence != 0.5 || market.Cited != 3 {
+		t.Errorf("got market %+v, want narrow at confidence 0.5 over 3 citations", market)
+	}
+	if len(market.Domains) != 1 || market.Domains[0] != "techcrunch.com" {
+		t.Errorf("got market domains %v, want only techcrunch.com", market.Domains)
+	}
+
+	problem := analysis.SourceDiversity[analyzers.DimensionProblem]
+	if problem.Narrow || problem.Confidence != 1 || len(problem.Domains) != 2 {
+		t.Errorf("got problem %+v, want two domains at full confidence", problem)
+	}
+
+	if _, ok := analysis.SourceDiversity[analyzers.DimensionRisks]; ok {
+		t.Error("dimension citing nothing was given a diversity entry")
+	}
+
+	if len(analysis.Warnings) != 1 || !strings.HasPrefix(analysis.Warnings[0], "Single-source market analysis") ||
+		!strings.Contains(analysis.Warnings[0], "techcrunch.com") {
+		t.Errorf("got warnings %q, want one single-source warning for the market", analysis.Warnings)
+	}
+}
+
+func TestCheckSourceDiversityDisabled(t *testing.T) {
+	analysis := types.Analysis{
+		Evidence:          []types.Evidence{{ID: "m1", URL: "https://techcrunch.com/a"}},
+		EvidenceCitations: map[string][]string{"m1": {analyzers.DimensionMarket}},
+	}
+
+	o := &Orchestrator{minSourceDomains: 1}
+	o.checkSourceDiversity(&analysis)
+
+	if market := analysis.SourceDiversity[analyzers.DimensionMarket]; market.Narrow || len(analysis.Warnings) != 0 {
+		t.Errorf("a minimum of 1 flagged a single source: %+v, %q", market, analysis.Warnings)
+	}
+}
@@ -0,0 +1,19 @@
+package app
+
+import "errors"
+
+var (
+	ErrInvalidTimeout         = errors.New("timeout must be positive and no greater than the configured maximum")
+	ErrInvalidSearchTimeout   = errors.New("search_timeout must be positive and shorter than the analysis timeout")
+	ErrInvalidEvidenceAge     = errors.New("evidence_max_age_days must not be negative")
+	ErrInvalidReviewThreshold = errors.New("review_threshold must be between 0 and 100")
+	ErrInvalidTags            = errors.New("tags must be up to 32 lowercase letters, digits, '.', '_' or '-', starting with a letter or digit, and at most 10 per analysis")
+	ErrSourceURLDisabled      = errors.New("source URL analysis is not enabled")
+	ErrInsufficientEvidence   = errors.New("could not gather evidence")
+	ErrQueueFull              = errors.New("too many analyses in progress; retry later")
+	ErrQueueClosed            = errors.New("server is shutting down")
+	ErrMaintenance            = errors.New("new analyses are paused for maintenance; stored analyses remain available")
+	ErrTooFewAnalyses         = errors.New("too few analyses in the category for insights")
+	ErrCancelled              = errors.New("analysis was cancelled")
+	ErrAnalysisFinished       = errors.New("analysis already finished")
+)
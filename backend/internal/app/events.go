@@ -0,0 +1,98 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// EventType names an analysis lifecycle event
+type EventType string
+
+// Analysis lifecycle events published by the orchestrator
+const (
+	EventAnalysisCreated   EventType = "analysis.created"
+	EventAnalysisCompleted EventType = "analysis.completed"
+	EventAnalysisFailed    EventType = "analysis.failed"
+	EventAnalysisCancelled EventType = "analysis.cancelled"
+)
+
+// EventTypes lists every event the orchestrator publishes
+var EventTypes = []EventType{
+	EventAnalysisCreated,
+	EventAnalysisCompleted,
+	EventAnalysisFailed,
+	EventAnalysisCancelled,
+}
+
+// Event is a single lifecycle notification. Data holds the event payload:
+// the idea for created, the full analysis for completed and a FailedEvent
+// for failed and cancelled.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       EventType   `json:"type"`
+	AnalysisID string      `json:"analysis_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// FailedEvent is the payload of an analysis.failed or analysis.cancelled event
+type FailedEvent struct {
+	Idea  interface{} `json:"idea"`
+	Error string      `json:"error"`
+}
+
+// EventHandler receives published events. Handlers run on the publisher's
+// goroutine, so anything slow must be handed off.
+type EventHandler func(Event)
+
+// EventBus fans lifecycle events out to in-process subscribers
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+// NewEventBus creates an event bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registers a handler for every published event
+func (b *EventBus) Subscribe(handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish stamps the event with an ID and time and passes it to every
+// subscriber. Publishing on a nil bus does nothing.
+func (b *EventBus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// newEventID returns a random identifier clients can use to deduplicate deliveries
+func newEventID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return "evt_" + hex.EncodeToString(bytes)
+}
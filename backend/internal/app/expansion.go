@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"rectaify/internal/taxonomy"
+	"rectaify/pkg/types"
+)
+
+// expandIdea reads a vague idea as a fuller description to plan searches
+// from. An idea with enough key terms is planned as written without asking
+// the LLM, and so is one the LLM finds specific; then the expansion is nil.
+func (o *Orchestrator) expandIdea(ctx context.Context, idea types.IdeaInput) (*types.IdeaExpansion, error) {
+	if !o.planner.IsVague(idea) {
+		return nil, nil
+	}
+	expansion, err := o.coordinator.ExpandIdea(ctx, idea)
+	if err != nil {
+		return nil, err
+	}
+	if expansion.Specific {
+		return nil, nil
+	}
+	return &expansion, nil
+}
+
+// planningIdea returns a copy of idea enriched with its expansion, for the
+// query planner only. The expansion's terms follow the idea's own so they
+// fill in for missing ones rather than displace them, and its category
+// guess is used only when the idea has none and the guess is a known one.
+func (o *Orchestrator) planningIdea(idea types.IdeaInput, expansion *types.IdeaExpansion) types.IdeaInput {
+	if expansion == nil {
+		return idea
+	}
+
+	idea.OneLiner = strings.Join(strings.Fields(strings.Join([]string{idea.OneLiner, expansion.TargetUser, expansion.CoreValue}, " ")), " ")
+	if (idea.Category == "" || idea.Category == taxonomy.Other) && o.categories != nil {
+		if category, known := o.categories.Canonical(expansion.CategoryGuess); known {
+			idea.Category = category
+		}
+	}
+	return idea
+}
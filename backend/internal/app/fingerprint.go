@@ -0,0 +1,48 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"rectaify/pkg/types"
+)
+
+// IdeaFingerprint returns a stable hash of an idea that ignores case and
+// surrounding whitespace, so trivially different submissions match
+func IdeaFingerprint(idea types.IdeaInput) string {
+	fields := []string{idea.Title, idea.OneLiner, idea.Category, idea.Location}
+	for i, field := range fields {
+		fields[i] = strings.ToLower(strings.Join(strings.Fields(field), " "))
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
+
+// analysisCacheKey combines the idea fingerprint with the options that change
+// the outcome of an analysis, including whether a vague idea is expanded
+// before planning and the review gate it is flagged against. Tags are stored
+// on the analysis, so differently tagged requests don't share one. The timeout
+// is left out because partial analyses are never cached.
+func analysisCacheKey(fingerprint string, maxEvidence int, location *types.ApproxLocation, evidenceMaxAge time.Duration, nextSteps, expandIdea bool, reviewThreshold float64, tags []string) string {
+	// Tag order doesn't matter to the analysis
+	tags = append([]string(nil), tags...)
+	sort.Strings(tags)
+
+	options, _ := json.Marshal(struct {
+		MaxEvidence     int                   `json:"max_evidence"`
+		Location        *types.ApproxLocation `json:"location,omitempty"`
+		EvidenceMaxAge  time.Duration         `json:"evidence_max_age,omitempty"`
+		NextSteps       bool                  `json:"next_steps,omitempty"`
+		ExpandIdea      bool                  `json:"expand_idea,omitempty"`
+		ReviewThreshold float64               `json:"review_threshold,omitempty"`
+		Tags            []string              `json:"tags,omitempty"`
+	}{maxEvidence, location, evidenceMaxAge, nextSteps, expandIdea, reviewThreshold, tags})
+
+	hash := sha256.Sum256(options)
+	return fingerprint + ":" + hex.EncodeToString(hash[:8])
+}
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func TestAnalysisCacheKeyTags(t *testing.T) {
+	fingerprint := IdeaFingerprint(types.IdeaInput{Title: "Tutor match", OneLiner: "Matches students with tutors"})
+	key := func(tags ...string) string {
+		return analysisCacheKey(fingerprint, 50, nil, 0, false, false, 0, tags)
+	}
+
+	if key("edtech", "b2c") != key("b2c", "edtech") {
+		t.Error("tag order changed the cache key")
+	}
+	if key() != key([]string{}...) {
+		t.Error("no tags and empty tags gave different cache keys")
+	}
+	if key("edtech") == key("fintech") || key("edtech") == key() {
+		t.Error("differently tagged requests share a cache key")
+	}
+}
@@ -4,13 +4,22 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"strings"
 	"time"
 
 	"rectaify/internal/analyzers"
+	"rectaify/internal/cache"
 	"rectaify/internal/evidence"
+	"rectaify/internal/landing"
+	"rectaify/internal/llm"
+	"rectaify/internal/scrub"
 	"rectaify/internal/search"
 	"rectaify/internal/store"
+	"rectaify/internal/taxonomy"
 	"rectaify/pkg/types"
 )
 
@@ -21,82 +30,407 @@ type Orchestrator struct {
 	normalizer       *evidence.Normalizer
 	coordinator      *analyzers.Coordinator
 	repository       *store.Repository
+	fetcher          *landing.Fetcher
+	scrubber         *scrub.Scrubber      // nil when scrubbing is disabled
+	analysisCache    *cache.AnalysisCache // nil when analysis caching is disabled
+	events           *EventBus            // nil when nothing subscribes to lifecycle events
+	killSwitch       *llm.KillSwitch      // nil when LLM calls can't be switched off
+	categories       *taxonomy.Taxonomy   // nil keeps idea categories as submitted
+	defaults         IdeaDefaults
 	maxEvidence      int
+	maxEvidenceBytes int // serialized evidence kept per analysis; 0 is unlimited
+	minEvidence      int // analyses gathering less evidence fail; 0 runs on any evidence
+	retryBudget      int // LLM retries shared by an analysis's calls; 0 leaves only the per-request limit
 	analysisTimeout  time.Duration
+	searchTimeout    time.Duration // evidence search phase, within analysisTimeout
+	minTimeout       time.Duration
+	maxTimeout       time.Duration
+	staleEvidenceAge time.Duration
+	evidenceMaxAge   time.Duration // evidence published longer ago is dropped; 0 keeps all
+	keepUndated      bool          // keep undated evidence under an evidenceMaxAge cutoff
+	nextSteps        bool          // generate validation plans unless a request says otherwise
+	reviewThreshold  float64       // overall score below which analyses are flagged; 0 flags none
+	minSourceDomains int           // domains expected behind each dimension's cited evidence
+	ideaExpansion    bool          // expand vague ideas before planning unless a request says otherwise
+	minCoherence     float64       // relevance of findings to the idea below which analyses are suspect; 0 skips the check
+	debugMeta        bool          // keep each analyzer's raw LLM output in meta for debugging
+	minInsights      int           // analyses a category needs before its insights are served
+	auditLog         bool          // record each analysis request and its verdict in the audit log
+	running          *runRegistry  // analyses in flight on this instance, by ID
+}
+
+// OrchestratorOptions holds the dependencies and settings used to construct
+// an Orchestrator
+type OrchestratorOptions struct {
+	Planner     *search.Planner
+	Executor    *search.Executor
+	Normalizer  *evidence.Normalizer
+	Coordinator *analyzers.Coordinator
+	Repository  *store.Repository
+	// Fetcher reads landing pages; nil when ideas are given directly
+	Fetcher *landing.Fetcher
+	// Scrubber is nil when scrubbing is disabled
+	Scrubber *scrub.Scrubber
+	// AnalysisCache is nil when analysis caching is disabled
+	AnalysisCache *cache.AnalysisCache
+	// Events is nil when nothing subscribes to lifecycle events
+	Events *EventBus
+	// KillSwitch is nil when LLM calls can't be switched off
+	KillSwitch *llm.KillSwitch
+	// Categories is nil to keep idea categories as submitted
+	Categories *taxonomy.Taxonomy
+	Defaults   IdeaDefaults
+
+	MaxEvidence int
+	// MaxEvidenceBytes is the serialized evidence kept per analysis; 0 is unlimited
+	MaxEvidenceBytes int
+	// MinEvidence fails analyses gathering less evidence; 0 runs on any evidence
+	MinEvidence int
+	// RetryBudget is the LLM retries shared by an analysis's calls; 0 leaves
+	// only the per-request limit
+	RetryBudget int
+
+	AnalysisTimeout time.Duration
+	// SearchTimeout bounds the evidence search phase, within AnalysisTimeout
+	SearchTimeout    time.Duration
+	MinTimeout       time.Duration
+	MaxTimeout       time.Duration
+	StaleEvidenceAge time.Duration
+	// EvidenceMaxAge drops evidence published longer ago; 0 keeps all
+	EvidenceMaxAge time.Duration
+	// KeepUndated keeps undated evidence under an EvidenceMaxAge cutoff
+	KeepUndated bool
+
+	// NextSteps generates validation plans unless a request says otherwise
+	NextSteps bool
+	// ReviewThreshold is the overall score below which analyses are flagged;
+	// 0 flags none
+	ReviewThreshold float64
+	// MinSourceDomains is the domains expected behind each dimension's cited evidence
+	MinSourceDomains int
+	// IdeaExpansion expands vague ideas before planning unless a request
+	// says otherwise
+	IdeaExpansion bool
+	// MinCoherence is the relevance of findings to the idea below which
+	// analyses are suspect; 0 skips the check
+	MinCoherence float64
+	// DebugMeta keeps each analyzer's raw LLM output in meta for debugging
+	DebugMeta bool
+	// MinInsights is the analyses a category needs before its insights are served
+	MinInsights int
+	// AuditLog records each analysis request and its verdict in the audit log
+	AuditLog bool
 }
 
 // NewOrchestrator creates a new orchestrator
-func NewOrchestrator(
-	planner *search.Planner,
-	executor *search.Executor,
-	normalizer *evidence.Normalizer,
-	coordinator *analyzers.Coordinator,
-	repository *store.Repository,
-	maxEvidence int,
-	analysisTimeout time.Duration,
-) *Orchestrator {
+func NewOrchestrator(opts OrchestratorOptions) *Orchestrator {
 	return &Orchestrator{
-		planner:         planner,
-		executor:        executor,
-		normalizer:      normalizer,
-		coordinator:     coordinator,
-		repository:      repository,
-		maxEvidence:     maxEvidence,
-		analysisTimeout: analysisTimeout,
+		planner:          opts.Planner,
+		executor:         opts.Executor,
+		normalizer:       opts.Normalizer,
+		coordinator:      opts.Coordinator,
+		repository:       opts.Repository,
+		fetcher:          opts.Fetcher,
+		scrubber:         opts.Scrubber,
+		analysisCache:    opts.AnalysisCache,
+		events:           opts.Events,
+		killSwitch:       opts.KillSwitch,
+		categories:       opts.Categories,
+		defaults:         opts.Defaults,
+		maxEvidence:      opts.MaxEvidence,
+		maxEvidenceBytes: opts.MaxEvidenceBytes,
+		minEvidence:      opts.MinEvidence,
+		retryBudget:      opts.RetryBudget,
+		analysisTimeout:  opts.AnalysisTimeout,
+		searchTimeout:    opts.SearchTimeout,
+		minTimeout:       opts.MinTimeout,
+		maxTimeout:       opts.MaxTimeout,
+		staleEvidenceAge: opts.StaleEvidenceAge,
+		evidenceMaxAge:   opts.EvidenceMaxAge,
+		keepUndated:      opts.KeepUndated,
+		nextSteps:        opts.NextSteps,
+		reviewThreshold:  opts.ReviewThreshold,
+		minSourceDomains: opts.MinSourceDomains,
+		ideaExpansion:    opts.IdeaExpansion,
+		minCoherence:     opts.MinCoherence,
+		debugMeta:        opts.DebugMeta,
+		minInsights:      opts.MinInsights,
+		auditLog:         opts.AuditLog,
+		running:          newRunRegistry(),
 	}
 }
 
-// AnalyzeIdea performs a complete analysis of a startup idea
-func (o *Orchestrator) AnalyzeIdea(ctx context.Context, request types.AnalysisRequest) (string, error) {
+// AnalyzeIdea performs a complete analysis of a startup idea. When an
+// identical request completed within the analysis cache TTL, the earlier
+// analysis ID is returned instead and cached is true.
+func (o *Orchestrator) AnalyzeIdea(ctx context.Context, request types.AnalysisRequest) (analysisID string, cached bool, err error) {
+	return o.analyze(ctx, request, "")
+}
+
+// RefreshEvidence re-plans and re-runs the evidence search for the idea of a
+// stored analysis and runs the analyzers on what it finds, saving the result
+// as a new analysis that records the one it refreshed. The stored idea and
+// tags are reused as is. freshSearch bypasses the evidence cache, so no
+// search results cached before the market moved are reused.
+func (o *Orchestrator) RefreshEvidence(ctx context.Context, analysisID string, freshSearch bool) (string, error) {
+	original, err := o.repository.GetAnalysis(ctx, analysisID)
+	if err != nil {
+		return "", err
+	}
+
+	request := types.AnalysisRequest{
+		Idea:    original.Idea,
+		Tags:    original.Tags,
+		Options: &types.AnalysisOptions{ForceRefresh: true, FreshSearch: freshSearch},
+	}
+	if original.Idea.Location != "" {
+		request.Options.Location = &types.ApproxLocation{Country: original.Idea.Location}
+	}
+
+	refreshedID, _, err := o.analyze(ctx, request, original.ID)
+	return refreshedID, err
+}
+
+// analyze runs an analysis; refreshOf names the analysis being refreshed, if any
+func (o *Orchestrator) analyze(ctx context.Context, request types.AnalysisRequest, refreshOf string) (analysisID string, cached bool, err error) {
+	if o.auditLog {
+		action := "analyze"
+		if refreshOf != "" {
+			action = "refresh"
+		}
+		// The idea is normalized in place below, so the entry has it as analyzed
+		defer func(ctx context.Context) {
+			o.audit(ctx, action, request.Idea, analysisID, cached, err)
+		}(ctx)
+	}
+
 	// Create context with timeout
-	timeout := o.analysisTimeout
-	if request.Options != nil && request.Options.Timeout != nil {
-		timeout = *request.Options.Timeout
+	timeout, err := o.ResolveTimeout(request.Options)
+	if err != nil {
+		return "", false, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	searchTimeout, err := o.ResolveSearchTimeout(request.Options, timeout)
+	if err != nil {
+		return "", false, err
+	}
 
-	// Generate analysis ID
-	analysisID, err := o.generateAnalysisID()
+	evidenceMaxAge, err := o.ResolveEvidenceMaxAge(request.Options)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate analysis ID: %w", err)
+		return "", false, err
 	}
 
-	// Step 1: Plan search queries
-	queries, err := o.planner.Plan(ctx, request.Idea)
+	reviewThreshold, err := o.ResolveReviewThreshold(request.Options)
 	if err != nil {
-		return "", fmt.Errorf("query planning failed: %w", err)
+		return "", false, err
 	}
 
-	// Step 2: Execute searches and gather evidence
-	location := request.Options.GetLocation()
-	rawEvidence, err := o.executor.Run(ctx, queries, location)
+	tags, err := normalizeTags(request.Tags)
 	if err != nil {
-		return "", fmt.Errorf("search execution failed: %w", err)
+		return "", false, err
 	}
 
-	// Step 3: Normalize and deduplicate evidence
-	normalizedEvidence := o.normalizer.Normalize(rawEvidence)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Share one retry budget across every LLM call of the analysis, so a
+	// provider outage fails it fast instead of retrying each call in turn
+	var retryBudget *llm.RetryBudget
+	if o.retryBudget > 0 {
+		ctx, retryBudget = llm.WithRetryBudget(ctx, o.retryBudget)
+	}
+	// Note which model produced each result, as fallbacks may stand in
+	ctx, models := llm.WithModelLog(ctx)
+	// In debug mode, also keep what each of them answered
+	var responses *llm.ResponseLog
+	if o.debugMeta {
+		ctx, responses = llm.WithResponseLog(ctx)
+	}
+
+	normalizeIdea(&request.Idea)
+	o.defaults.apply(&request)
+	submittedCategory := request.Idea.Category
+	categoryWarning := o.canonicalizeCategory(&request.Idea)
+
+	// Fill in missing idea fields from the landing page if one was given
+	if request.SourceURL != "" && (request.Idea.Title == "" || request.Idea.OneLiner == "") {
+		if err := o.populateFromSourceURL(ctx, &request); err != nil {
+			return "", false, err
+		}
+	}
 
-	// Step 4: Limit evidence if needed
 	maxEvidence := o.maxEvidence
 	if request.Options != nil && request.Options.MaxEvidence > 0 {
 		maxEvidence = request.Options.MaxEvidence
 	}
-	if len(normalizedEvidence) > maxEvidence {
-		normalizedEvidence = normalizedEvidence[:maxEvidence]
+	location := request.Options.GetLocation()
+
+	fingerprint := IdeaFingerprint(request.Idea)
+	wantsNextSteps := request.Options.WantsNextSteps(o.nextSteps)
+	wantsExpansion := request.Options.WantsIdeaExpansion(o.ideaExpansion)
+	cacheKey := analysisCacheKey(fingerprint, maxEvidence, location, evidenceMaxAge, wantsNextSteps, wantsExpansion, reviewThreshold, tags)
+	if request.Options.ShouldForceRefresh() {
+		o.invalidateCachedAnalysis(ctx, cacheKey)
+	} else if cachedID, ok := o.cachedAnalysisID(ctx, cacheKey); ok {
+		return cachedID, true, nil
+	}
+
+	// Cached analyses cost nothing, so only new ones stop in maintenance mode
+	if o.Maintenance() {
+		return "", false, ErrMaintenance
+	}
+
+	// Generate analysis ID
+	analysisID, err = o.generateAnalysisID()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to generate analysis ID: %w", err)
+	}
+
+	createdID, publicIdea := analysisID, o.scrubber.Idea(request.Idea)
+	o.events.Publish(Event{Type: EventAnalysisCreated, AnalysisID: createdID, Data: publicIdea})
+	defer func() {
+		if err != nil {
+			eventType := EventAnalysisFailed
+			if errors.Is(err, ErrCancelled) {
+				eventType = EventAnalysisCancelled
+			}
+			o.events.Publish(Event{
+				Type:       eventType,
+				AnalysisID: createdID,
+				Data:       FailedEvent{Idea: publicIdea, Error: err.Error()},
+			})
+		}
+	}()
+
+	// From here on the analysis can be cancelled by ID, which fails whatever
+	// step it was in
+	ctx, run := o.running.start(ctx, analysisID)
+	defer o.running.finish(analysisID)
+	defer func() {
+		if err != nil && run.cancelled() {
+			err = ErrCancelled
+		}
+	}()
+
+	// Step 1: Plan search queries, reading a vague idea more fully first. The
+	// expansion only widens the search, so failing to make one doesn't fail
+	// the analysis.
+	var expansion *types.IdeaExpansion
+	var expansionErr error
+	if wantsExpansion {
+		expansion, expansionErr = o.expandIdea(ctx, request.Idea)
+		if expansionErr != nil {
+			log.Printf("Idea expansion for analysis %s failed: %v", analysisID, expansionErr)
+		}
+	}
+	queries, err := o.planner.Plan(ctx, o.planningIdea(request.Idea, expansion))
+	if err != nil {
+		return "", false, fmt.Errorf("query planning failed: %w", err)
+	}
+
+	// Step 2: Execute searches and gather evidence, leaving the rest of the
+	// analysis timeout for the analyzers
+	searchCtx, cancelSearch := context.WithTimeout(ctx, searchTimeout)
+	rawEvidence, queryStats, err := o.executor.Run(searchCtx, queries, location, request.Options.ShouldSearchFresh())
+	searchTimedOut := errors.Is(searchCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+	cancelSearch()
+	if err != nil {
+		return "", false, fmt.Errorf("search execution failed: %w", err)
+	}
+	if run.cancelled() {
+		return "", false, ErrCancelled
+	}
+
+	// Step 3: Normalize and deduplicate evidence
+	normalizedEvidence := o.normalizer.Normalize(ctx, rawEvidence)
+	normalizedEvidence, droppedOld := evidence.DropOlderThan(normalizedEvidence, time.Now(), evidenceMaxAge, o.keepUndated)
+
+	// Step 4: Limit evidence if needed, keeping source types within their caps
+	normalizedEvidence = o.normalizer.Select(normalizedEvidence, maxEvidence)
+	normalizedEvidence, truncation := evidence.LimitBytes(normalizedEvidence, o.maxEvidenceBytes)
+
+	// In strict mode, fail rather than analyze and save an analysis without grounding
+	if len(normalizedEvidence) < o.minEvidence {
+		return "", false, fmt.Errorf("%w: found %d usable items, %d required", ErrInsufficientEvidence, len(normalizedEvidence), o.minEvidence)
 	}
 
 	// Step 5: Run all analyzers
-	analysis, err := o.coordinator.AnalyzeAll(ctx, request.Idea, normalizedEvidence)
+	analysis, err := o.coordinator.AnalyzeAll(ctx, request.Idea, normalizedEvidence, search.PostmortemCoverage(queryStats), request.Options.ShouldForceRefresh())
 	if err != nil {
-		return "", fmt.Errorf("analysis failed: %w", err)
+		return "", false, fmt.Errorf("analysis failed: %w", err)
+	}
+	analysis.EvidenceCitations = evidenceCitations(analysis)
+
+	// A failed coherence check leaves the analysis unchecked rather than failing it
+	if err := o.checkCoherence(ctx, &analysis); err != nil {
+		log.Printf("Coherence check for analysis %s failed: %v", analysisID, err)
+		analysis.SetMeta("coherence_error", err.Error())
+	}
+
+	// A validation plan is a nice-to-have, so failing to make one doesn't fail the analysis
+	if wantsNextSteps {
+		steps, err := o.coordinator.PlanNextSteps(ctx, analysis)
+		if err != nil {
+			log.Printf("Next steps for analysis %s failed: %v", analysisID, err)
+			analysis.SetMeta("next_steps_error", err.Error())
+		}
+		analysis.NextSteps = steps
 	}
 
 	// Step 6: Finalize analysis metadata
 	analysis.ID = analysisID
 	analysis.CreatedAt = time.Now()
+	analysis.VerdictVersion = 1
+	analysis.Tags = tags
+	flagForReview(&analysis, reviewThreshold)
+
+	analysis.SetMeta("effective_timeout", timeout.String())
+	analysis.SetMeta("effective_search_timeout", searchTimeout.String())
+	if searchTimedOut {
+		analysis.SetMeta("search_timed_out", true)
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf(
+			"Evidence search stopped after %s with %d of %d queries answered; the analysis uses the evidence found by then.",
+			searchTimeout, answeredQueries(queryStats), len(queryStats)))
+	}
+	analysis.SetMeta("idea_fingerprint", fingerprint)
+	if submittedCategory != request.Idea.Category {
+		analysis.SetMeta("submitted_category", submittedCategory)
+	}
+	if categoryWarning != "" {
+		analysis.Warnings = append(analysis.Warnings, categoryWarning)
+	}
+	analysis.SetMeta("query_stats", queryStats)
+	if expansion != nil {
+		analysis.SetMeta("idea_expansion", expansion)
+	}
+	if expansionErr != nil {
+		analysis.SetMeta("idea_expansion_error", expansionErr.Error())
+	}
+	if refreshOf != "" {
+		analysis.SetMeta("refreshed_from", refreshOf)
+	}
+	if retryBudget != nil {
+		analysis.SetMeta("retry_budget", retryBudget.Stats())
+	}
+	if used := models.Models(); len(used) > 0 {
+		analysis.SetMeta("models", used)
+	}
+	if responses != nil {
+		analysis.SetMeta(DebugMetaKey, analyzerDebugMeta(responses.Responses(), analysis.Evidence))
+	}
+	if truncation.Truncated {
+		analysis.SetMeta("evidence_truncation", truncation)
+	}
+	o.checkFreshness(&analysis)
+	o.checkSourceDiversity(&analysis)
+	if droppedOld > 0 {
+		analysis.SetMeta("evidence_age_cutoff", map[string]interface{}{
+			"max_age_days": evidenceMaxAge.Hours() / 24,
+			"dropped":      droppedOld,
+		})
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf(
+			"%d sources published more than %.0f days ago were left out.", droppedOld, evidenceMaxAge.Hours()/24))
+	}
 
 	// Check if context was cancelled (partial analysis)
 	select {
@@ -105,12 +439,401 @@ func (o *Orchestrator) AnalyzeIdea(ctx context.Context, request types.AnalysisRe
 	default:
 	}
 
+	// A cancelled analysis is saved only if its canceller asked for what it
+	// gathered; the LLM steps left when it was cancelled failed at once
+	saveCtx := ctx
+	if run.cancelled() {
+		if !run.keepPartial.Load() {
+			return "", false, ErrCancelled
+		}
+		analysis.SetMeta("cancelled", true)
+		saveCtx = context.WithoutCancel(ctx)
+	}
+
+	// Redact personal data from the submitted idea before it is stored
+	analysis.Idea = o.scrubber.Idea(analysis.Idea)
+
 	// Step 7: Save to database
-	if err := o.repository.SaveAnalysis(ctx, analysis); err != nil {
-		return "", fmt.Errorf("failed to save analysis: %w", err)
+	if err := o.repository.SaveAnalysis(saveCtx, analysis); err != nil {
+		return "", false, fmt.Errorf("failed to save analysis: %w", err)
+	}
+
+	// Partial analyses are not worth serving again
+	if !analysis.Partial {
+		o.cacheAnalysis(ctx, cacheKey, analysis)
+	}
+
+	// Subscribers are not admins, so the debug output stays in the database
+	analysis.DeleteMeta(DebugMetaKey)
+	o.events.Publish(Event{Type: EventAnalysisCompleted, AnalysisID: analysisID, Data: analysis})
+
+	return analysisID, false, nil
+}
+
+// cachedAnalysisID returns the ID of a cached analysis for the key, as long as
+// that analysis has not since been deleted
+func (o *Orchestrator) cachedAnalysisID(ctx context.Context, key string) (string, bool) {
+	if o.analysisCache == nil {
+		return "", false
+	}
+
+	analysis, found, err := o.analysisCache.GetAnalysis(ctx, key)
+	if err != nil {
+		log.Printf("Analysis cache lookup failed: %v", err)
+		return "", false
+	}
+	if !found {
+		return "", false
+	}
+
+	if _, err := o.repository.GetAnalysis(ctx, analysis.ID); err != nil {
+		if !errors.Is(err, store.ErrAnalysisNotFound) {
+			log.Printf("Failed to check cached analysis %s: %v", analysis.ID, err)
+			return "", false
+		}
+		o.invalidateCachedAnalysis(ctx, key)
+		return "", false
+	}
+
+	return analysis.ID, true
+}
+
+// cacheAnalysis stores a completed analysis under its cache key
+func (o *Orchestrator) cacheAnalysis(ctx context.Context, key string, analysis types.Analysis) {
+	if o.analysisCache == nil {
+		return
+	}
+
+	if err := o.analysisCache.SetAnalysis(ctx, key, analysis); err != nil {
+		log.Printf("Failed to cache analysis %s: %v", analysis.ID, err)
+	}
+}
+
+// invalidateCachedAnalysis drops the cached analysis for a key so the next
+// identical request runs the full pipeline
+func (o *Orchestrator) invalidateCachedAnalysis(ctx context.Context, key string) {
+	if o.analysisCache == nil {
+		return
+	}
+
+	if err := o.analysisCache.Invalidate(ctx, key); err != nil {
+		log.Printf("Failed to invalidate cached analysis: %v", err)
+	}
+}
+
+// Reverdict regenerates the verdict of a stored analysis from its stored
+// dimension results and evidence, then saves it with a bumped verdict version.
+// It returns the analysis before and after.
+func (o *Orchestrator) Reverdict(ctx context.Context, analysisID string) (types.Analysis, types.Analysis, error) {
+	if o.Maintenance() {
+		return types.Analysis{}, types.Analysis{}, ErrMaintenance
+	}
+
+	before, err := o.repository.GetAnalysisWithEvidence(ctx, analysisID)
+	if err != nil {
+		return types.Analysis{}, types.Analysis{}, err
+	}
+
+	verdict, err := o.coordinator.Reverdict(ctx, before)
+	if err != nil {
+		return types.Analysis{}, types.Analysis{}, fmt.Errorf("verdict synthesis failed: %w", err)
+	}
+
+	after := before
+	after.Verdict = verdict
+	after.VerdictVersion = max(before.VerdictVersion, 1) + 1
+
+	// Apply the same stale-evidence dampening as a fresh analysis; the
+	// warning is already on the stored analysis
+	freshness := evidence.MeasureFreshness(after.Evidence, after.Verdict.EvidenceIDs, time.Now(), o.staleEvidenceAge)
+	if freshness.Stale {
+		after.Verdict.Confidence *= staleConfidenceFactor
+	}
+	// The findings are unchanged, so their coherence check still holds
+	if coherence, ok := coherenceFromMeta(before.Meta); ok {
+		after.Verdict.Confidence *= coherenceConfidence(coherence)
+	}
+	after.SetMeta("evidence_freshness", freshness)
+	after.SetMeta("reverdict_at", time.Now().UTC())
+	flagForReview(&after, before.ReviewThreshold)
+
+	if err := o.repository.UpdateVerdict(ctx, after); err != nil {
+		return types.Analysis{}, types.Analysis{}, err
+	}
+
+	return before, after, nil
+}
+
+// RefineIdea suggests refinements to the idea of a stored analysis, grounded
+// in its evidence and aimed at its lowest-scoring dimensions
+func (o *Orchestrator) RefineIdea(ctx context.Context, analysisID string) (types.RefineResponse, error) {
+	if o.Maintenance() {
+		return types.RefineResponse{}, ErrMaintenance
+	}
+
+	analysis, err := o.repository.GetAnalysisWithEvidence(ctx, analysisID)
+	if err != nil {
+		return types.RefineResponse{}, err
+	}
+
+	focus, suggestions, err := o.coordinator.Refine(ctx, analysis)
+	if err != nil {
+		return types.RefineResponse{}, err
+	}
+
+	return types.RefineResponse{
+		AnalysisID:      analysisID,
+		FocusDimensions: focus,
+		Suggestions:     suggestions,
+	}, nil
+}
+
+// Sensitivity recomputes an analysis's overall score under moved dimension
+// scores and alternative weightings, without calling the LLM
+func (o *Orchestrator) Sensitivity(ctx context.Context, analysisID string, delta float64) (types.Sensitivity, error) {
+	analysis, err := o.repository.GetAnalysis(ctx, analysisID)
+	if err != nil {
+		return types.Sensitivity{}, err
+	}
+	return o.coordinator.Sensitivity(analysis, delta), nil
+}
+
+// staleConfidenceFactor dampens verdict confidence when evidence is stale
+const staleConfidenceFactor = 0.85
+
+// checkFreshness records the median age of the cited evidence and warns when it is stale
+func (o *Orchestrator) checkFreshness(analysis *types.Analysis) {
+	freshness := evidence.MeasureFreshness(analysis.Evidence, analysis.Verdict.EvidenceIDs, time.Now(), o.staleEvidenceAge)
+	analysis.SetMeta("evidence_freshness", freshness)
+
+	if !freshness.Stale {
+		return
+	}
+
+	analysis.Warnings = append(analysis.Warnings, fmt.Sprintf(
+		"Stale evidence: the median cited source is %.0f days old, so conclusions may not reflect the current market.",
+		freshness.MedianAgeDays))
+	analysis.Verdict.Confidence *= staleConfidenceFactor
+}
+
+// ResolveTimeout returns the timeout that will be applied to an analysis.
+// Client-supplied values below the configured minimum are raised to it;
+// non-positive values or values above the maximum are rejected.
+func (o *Orchestrator) ResolveTimeout(options *types.AnalysisOptions) (time.Duration, error) {
+	if options == nil || options.Timeout == nil {
+		return o.analysisTimeout, nil
+	}
+
+	timeout := *options.Timeout
+	if timeout <= 0 || (o.maxTimeout > 0 && timeout > o.maxTimeout) {
+		return 0, fmt.Errorf("%w (max %s)", ErrInvalidTimeout, o.maxTimeout)
+	}
+	if timeout < o.minTimeout {
+		timeout = o.minTimeout
+	}
+
+	return timeout, nil
+}
+
+// ResolveSearchTimeout returns the time the evidence search may take within an
+// analysis timeout. A client-supplied value must be positive and shorter than
+// the analysis timeout. The configured value is halved down to fit when the
+// client shortened the analysis timeout below it, so analyzers keep a budget.
+func (o *Orchestrator) ResolveSearchTimeout(options *types.AnalysisOptions, analysisTimeout time.Duration) (time.Duration, error) {
+	if options != nil && options.SearchTimeout != nil {
+		timeout := *options.SearchTimeout
+		if timeout <= 0 || timeout >= analysisTimeout {
+			return 0, fmt.Errorf("%w (%s)", ErrInvalidSearchTimeout, analysisTimeout)
+		}
+		return timeout, nil
+	}
+
+	if o.searchTimeout <= 0 || o.searchTimeout >= analysisTimeout {
+		return analysisTimeout / 2, nil
+	}
+	return o.searchTimeout, nil
+}
+
+// ResolveEvidenceMaxAge returns the published-date cutoff for an analysis:
+// the requested number of days, or the configured default
+func (o *Orchestrator) ResolveEvidenceMaxAge(options *types.AnalysisOptions) (time.Duration, error) {
+	if options == nil || options.EvidenceMaxAgeDays == 0 {
+		return o.evidenceMaxAge, nil
+	}
+	if options.EvidenceMaxAgeDays < 0 {
+		return 0, ErrInvalidEvidenceAge
 	}
+	return time.Duration(options.EvidenceMaxAgeDays) * 24 * time.Hour, nil
+}
 
-	return analysisID, nil
+// ResolveReviewThreshold returns the overall score below which an analysis is
+// flagged for review: the requested bar, or the configured default
+func (o *Orchestrator) ResolveReviewThreshold(options *types.AnalysisOptions) (float64, error) {
+	if options == nil || options.ReviewThreshold == nil {
+		return o.reviewThreshold, nil
+	}
+	threshold := *options.ReviewThreshold
+	if threshold < 0 || threshold > 100 {
+		return 0, ErrInvalidReviewThreshold
+	}
+	return threshold, nil
+}
+
+// flagForReview records the review gate an analysis was screened against and
+// flags it when its overall score falls below. A threshold of 0 flags nothing.
+func flagForReview(analysis *types.Analysis, threshold float64) {
+	analysis.ReviewThreshold = threshold
+	analysis.Flagged = threshold > 0 && analysis.Verdict.OverallScore < threshold
+}
+
+// answeredQueries counts the searches that completed without error
+func answeredQueries(stats []types.QueryStat) int {
+	answered := 0
+	for _, stat := range stats {
+		if stat.Error == "" {
+			answered++
+		}
+	}
+	return answered
+}
+
+// ValidateRequest runs the same checks as AnalyzeIdea and plans the search
+// queries, but performs no fetching, searching or analysis
+func (o *Orchestrator) ValidateRequest(ctx context.Context, request types.AnalysisRequest) (types.ValidationResponse, error) {
+	timeout, err := o.ResolveTimeout(request.Options)
+	if err != nil {
+		return types.ValidationResponse{}, err
+	}
+	searchTimeout, err := o.ResolveSearchTimeout(request.Options, timeout)
+	if err != nil {
+		return types.ValidationResponse{}, err
+	}
+	if _, err := o.ResolveEvidenceMaxAge(request.Options); err != nil {
+		return types.ValidationResponse{}, err
+	}
+	if _, err := o.ResolveReviewThreshold(request.Options); err != nil {
+		return types.ValidationResponse{}, err
+	}
+	if _, err := normalizeTags(request.Tags); err != nil {
+		return types.ValidationResponse{}, err
+	}
+
+	normalizeIdea(&request.Idea)
+	o.defaults.apply(&request)
+	categoryWarning := o.canonicalizeCategory(&request.Idea)
+
+	response := types.ValidationResponse{
+		Valid:            true,
+		Idea:             request.Idea,
+		SourceURL:        request.SourceURL,
+		EffectiveTimeout: timeout.String(),
+
+		EffectiveSearchTimeout: searchTimeout.String(),
+	}
+	if categoryWarning != "" {
+		response.Warnings = append(response.Warnings, categoryWarning)
+	}
+
+	if request.SourceURL != "" {
+		if o.fetcher == nil {
+			return types.ValidationResponse{}, ErrSourceURLDisabled
+		}
+		if _, err := landing.ParseURL(request.SourceURL); err != nil {
+			return types.ValidationResponse{}, err
+		}
+		// The idea is only known once the page is fetched
+		if request.Idea.Title == "" || request.Idea.OneLiner == "" {
+			return response, nil
+		}
+	}
+
+	queries, err := o.planner.Plan(ctx, request.Idea)
+	if err != nil {
+		return types.ValidationResponse{}, fmt.Errorf("query planning failed: %w", err)
+	}
+	response.PlannedQueries = len(queries)
+
+	return response, nil
+}
+
+// IdeaDefaults fill in the category and location of requests that leave them
+// empty, for deployments that serve a single market or vertical. Values in the
+// request always win; an empty field only means "none" when its default is
+// empty too.
+type IdeaDefaults struct {
+	Category string
+	Location string
+}
+
+// apply fills empty idea fields from the defaults. A defaulted location also
+// becomes the search location unless the request set one in its options.
+func (d IdeaDefaults) apply(request *types.AnalysisRequest) {
+	if request.Idea.Category == "" {
+		request.Idea.Category = d.Category
+	}
+
+	if request.Idea.Location != "" || d.Location == "" {
+		return
+	}
+	request.Idea.Location = d.Location
+
+	if request.Options.GetLocation() != nil {
+		return
+	}
+	if request.Options == nil {
+		request.Options = &types.AnalysisOptions{}
+	}
+	request.Options.Location = &types.ApproxLocation{Country: d.Location}
+}
+
+// canonicalizeCategory replaces the idea category with the canonical category
+// it matches, returning a warning when it matched none and became "other"
+func (o *Orchestrator) canonicalizeCategory(idea *types.IdeaInput) string {
+	if o.categories == nil {
+		return ""
+	}
+	submitted := idea.Category
+	category, known := o.categories.Canonical(submitted)
+	idea.Category = category
+	if known {
+		return ""
+	}
+	return fmt.Sprintf("Category %q matches no known category, so the idea was analyzed as %q.", submitted, category)
+}
+
+// normalizeIdea trims surrounding whitespace from the idea fields
+func normalizeIdea(idea *types.IdeaInput) {
+	idea.Title = strings.TrimSpace(idea.Title)
+	idea.OneLiner = strings.TrimSpace(idea.OneLiner)
+	idea.Category = strings.TrimSpace(idea.Category)
+	idea.Location = strings.TrimSpace(idea.Location)
+}
+
+// populateFromSourceURL fetches the request's landing page and uses it to fill
+// in the idea title and one-liner the client did not provide
+func (o *Orchestrator) populateFromSourceURL(ctx context.Context, request *types.AnalysisRequest) error {
+	if o.fetcher == nil {
+		return ErrSourceURLDisabled
+	}
+
+	extracted, err := o.fetcher.Fetch(ctx, request.SourceURL)
+	if err != nil && !errors.Is(err, landing.ErrInsufficientContent) {
+		return err
+	}
+
+	if request.Idea.Title == "" {
+		request.Idea.Title = extracted.Title
+	}
+	if request.Idea.OneLiner == "" {
+		request.Idea.OneLiner = extracted.OneLiner
+	}
+
+	// Manually supplied fields may cover whatever extraction missed
+	if request.Idea.Title == "" || request.Idea.OneLiner == "" {
+		return landing.ErrInsufficientContent
+	}
+
+	return nil
 }
 
 // GetAnalysis retrieves a stored analysis
@@ -118,14 +841,69 @@ func (o *Orchestrator) GetAnalysis(ctx context.Context, analysisID string) (type
 	return o.repository.GetAnalysisWithEvidence(ctx, analysisID)
 }
 
+// GetEvidenceDetail returns a stored evidence item with its quality score and
+// the analyses citing it
+func (o *Orchestrator) GetEvidenceDetail(ctx context.Context, evidenceID string) (types.EvidenceDetail, error) {
+	ev, err := o.repository.GetEvidence(ctx, evidenceID)
+	if err != nil {
+		return types.EvidenceDetail{}, err
+	}
+
+	analysisIDs, err := o.repository.GetEvidenceAnalysisIDs(ctx, evidenceID)
+	if err != nil {
+		return types.EvidenceDetail{}, err
+	}
+
+	return types.EvidenceDetail{
+		Evidence:     ev,
+		QualityScore: math.Round(o.normalizer.QualityScore(ev)*100) / 100,
+		AnalysisIDs:  analysisIDs,
+	}, nil
+}
+
 // ListAnalyses returns a paginated list of analyses
-func (o *Orchestrator) ListAnalyses(ctx context.Context, limit, offset int) ([]types.Analysis, error) {
-	return o.repository.ListAnalyses(ctx, limit, offset)
+func (o *Orchestrator) ListAnalyses(ctx context.Context, filter store.AnalysisFilter, limit, offset int) ([]types.Analysis, error) {
+	return o.repository.ListAnalyses(ctx, filter, limit, offset)
 }
 
 // SearchAnalyses searches for analyses matching a query
-func (o *Orchestrator) SearchAnalyses(ctx context.Context, query string, limit, offset int) ([]types.Analysis, error) {
-	return o.repository.SearchAnalyses(ctx, query, limit, offset)
+func (o *Orchestrator) SearchAnalyses(ctx context.Context, query string, filter store.AnalysisFilter, limit, offset int) ([]types.Analysis, error) {
+	return o.repository.SearchAnalyses(ctx, query, filter, limit, offset)
+}
+
+// GetAnalysisScores retrieves the scores-only projection of an analysis
+func (o *Orchestrator) GetAnalysisScores(ctx context.Context, analysisID string) (types.AnalysisScores, error) {
+	return o.repository.GetAnalysisScores(ctx, analysisID)
+}
+
+// ListAnalysisScores returns the scores-only projection of a page of analyses
+func (o *Orchestrator) ListAnalysisScores(ctx context.Context, filter store.AnalysisFilter, limit, offset int) ([]types.AnalysisScores, error) {
+	return o.repository.ListAnalysisScores(ctx, filter, limit, offset)
+}
+
+// SearchAnalysisScores searches analyses, returning the scores-only projection
+func (o *Orchestrator) SearchAnalysisScores(ctx context.Context, query string, filter store.AnalysisFilter, limit, offset int) ([]types.AnalysisScores, error) {
+	return o.repository.SearchAnalysisScores(ctx, query, filter, limit, offset)
+}
+
+// UpdateAnalysis replaces the tags of a stored analysis or pins it, as the
+// update asks, and returns the analysis
+func (o *Orchestrator) UpdateAnalysis(ctx context.Context, analysisID string, update types.AnalysisUpdate) (types.Analysis, error) {
+	if update.Tags != nil {
+		tags, err := normalizeTags(*update.Tags)
+		if err != nil {
+			return types.Analysis{}, err
+		}
+		if err := o.repository.UpdateTags(ctx, analysisID, tags); err != nil {
+			return types.Analysis{}, err
+		}
+	}
+	if update.Pinned != nil {
+		if err := o.repository.UpdatePinned(ctx, analysisID, *update.Pinned); err != nil {
+			return types.Analysis{}, err
+		}
+	}
+	return o.repository.GetAnalysisWithEvidence(ctx, analysisID)
 }
 
 // DeleteAnalysis removes an analysis
@@ -133,11 +911,77 @@ func (o *Orchestrator) DeleteAnalysis(ctx context.Context, analysisID string) er
 	return o.repository.DeleteAnalysis(ctx, analysisID)
 }
 
+// ExportAnalyses streams stored analyses within an optional date range to fn
+func (o *Orchestrator) ExportAnalyses(ctx context.Context, from, to *time.Time, fn func(types.Analysis) error) error {
+	return o.repository.StreamAnalyses(ctx, from, to, fn)
+}
+
+// ImportAnalysis stores an analysis, replacing any existing analysis with the same ID
+func (o *Orchestrator) ImportAnalysis(ctx context.Context, analysis types.Analysis) error {
+	if analysis.ID == "" {
+		return fmt.Errorf("analysis ID is required")
+	}
+	if analysis.CreatedAt.IsZero() {
+		analysis.CreatedAt = time.Now()
+	}
+	return o.repository.UpsertAnalysis(ctx, analysis)
+}
+
 // GetAnalysisCount returns the total number of analyses
 func (o *Orchestrator) GetAnalysisCount(ctx context.Context) (int, error) {
 	return o.repository.GetAnalysisCount(ctx)
 }
 
+// CountAnalyses returns the number of analyses matching a filter
+func (o *Orchestrator) CountAnalyses(ctx context.Context, filter store.AnalysisFilter) (int, error) {
+	return o.repository.CountAnalyses(ctx, filter)
+}
+
+// ListCompetitors aggregates competitors across analyses, optionally by category
+func (o *Orchestrator) ListCompetitors(ctx context.Context, category string, limit, offset int) ([]types.CompetitorAggregate, error) {
+	return o.repository.ListCompetitors(ctx, category, limit, offset)
+}
+
+// GetCategoryInsights aggregates the findings of the analyses in a category,
+// up to limit of each kind. A category with too few analyses for the
+// aggregate to mean much is refused with ErrTooFewAnalyses.
+func (o *Orchestrator) GetCategoryInsights(ctx context.Context, category string, limit int) (types.CategoryInsights, error) {
+	if o.categories != nil {
+		if canonical, known := o.categories.Canonical(category); known {
+			category = canonical
+		}
+	}
+
+	insights, err := o.repository.GetCategoryInsights(ctx, category, limit)
+	if err != nil {
+		return insights, err
+	}
+	if insights.Count < o.minInsights {
+		return types.CategoryInsights{}, fmt.Errorf("%w: %d in %q, %d needed", ErrTooFewAnalyses, insights.Count, category, o.minInsights)
+	}
+	return insights, nil
+}
+
+// ListAudit returns audit log entries matching the filter, newest first
+func (o *Orchestrator) ListAudit(ctx context.Context, filter store.AuditFilter, limit, offset int) ([]types.AuditEntry, error) {
+	return o.repository.ListAudit(ctx, filter, limit, offset)
+}
+
+// VerifyAudit checks that the audit log's hash chain is intact
+func (o *Orchestrator) VerifyAudit(ctx context.Context) (types.AuditVerification, error) {
+	return o.repository.VerifyAudit(ctx)
+}
+
+// GetAnalytics returns score aggregates across all analyses
+func (o *Orchestrator) GetAnalytics(ctx context.Context) (types.Analytics, error) {
+	return o.repository.GetAnalytics(ctx)
+}
+
+// GetSearchCount returns the number of analyses matching a search query
+func (o *Orchestrator) GetSearchCount(ctx context.Context, query string, filter store.AnalysisFilter) (int, error) {
+	return o.repository.GetSearchCount(ctx, query, filter)
+}
+
 // generateAnalysisID creates a unique analysis identifier
 func (o *Orchestrator) generateAnalysisID() (string, error) {
 	bytes := make([]byte, 16)
@@ -147,6 +991,28 @@ func (o *Orchestrator) generateAnalysisID() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
+// Maintenance reports whether LLM calls are switched off. Stored analyses
+// stay readable; new analyses, verdict regeneration and refinement fail with
+// ErrMaintenance.
+func (o *Orchestrator) Maintenance() bool {
+	return o.killSwitch.Engaged()
+}
+
+// SetMaintenance switches LLM calls off or back on. It reports false when the
+// orchestrator was built without a kill switch.
+func (o *Orchestrator) SetMaintenance(enabled bool) bool {
+	if o.killSwitch == nil {
+		return false
+	}
+	o.killSwitch.Set(enabled)
+	if enabled {
+		log.Printf("Maintenance mode enabled: LLM calls are switched off")
+	} else {
+		log.Printf("Maintenance mode disabled: LLM calls resumed")
+	}
+	return true
+}
+
 // HealthCheck performs a basic health check of all components
 func (o *Orchestrator) HealthCheck(ctx context.Context) error {
 	// Check database connectivity
@@ -161,6 +1027,18 @@ func (o *Orchestrator) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// Ready reports whether analyses can run: the database is reachable and the
+// LLM client is configured. Maintenance mode doesn't affect readiness.
+func (o *Orchestrator) Ready(ctx context.Context) error {
+	if err := o.HealthCheck(ctx); err != nil {
+		return err
+	}
+	if err := o.executor.ValidateClient(); err != nil {
+		return fmt.Errorf("LLM client check failed: %w", err)
+	}
+	return nil
+}
+
 // GetStats returns basic statistics about the system
 func (o *Orchestrator) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	totalAnalyses, err := o.repository.GetAnalysisCount(ctx)
@@ -169,9 +1047,15 @@ func (o *Orchestrator) GetStats(ctx context.Context) (map[string]interface{}, er
 	}
 
 	stats := map[string]interface{}{
-		"total_analyses": totalAnalyses,
-		"max_evidence":   o.maxEvidence,
-		"timeout":        o.analysisTimeout.String(),
+		"total_analyses":     totalAnalyses,
+		"max_evidence":       o.maxEvidence,
+		"max_evidence_bytes": o.maxEvidenceBytes,
+		"timeout":            o.analysisTimeout.String(),
+		"search_timeout":     o.searchTimeout.String(),
+		"min_timeout":        o.minTimeout.String(),
+		"max_timeout":        o.maxTimeout.String(),
+
+		"cache_key_collisions": o.executor.CacheCollisions(),
 	}
 
 	return stats, nil
@@ -181,3 +1065,80 @@ func (o *Orchestrator) GetStats(ctx context.Context) (map[string]interface{}, er
 func (o *Orchestrator) CleanupOldData(ctx context.Context, olderThan time.Duration) (int, error) {
 	return o.repository.CleanupOldEvidence(ctx, olderThan)
 }
+
+// CleanupCounts reports rows removed by a maintenance cleanup, or eligible for removal
+type CleanupCounts struct {
+	OrphanedEvidence    int `json:"orphaned_evidence"`
+	ExpiredCacheEntries int `json:"expired_cache_entries"`
+}
+
+// CleanupEligible counts orphaned evidence older than evidenceMaxAge and
+// expired cache entries without removing anything
+func (o *Orchestrator) CleanupEligible(ctx context.Context, evidenceMaxAge time.Duration) (CleanupCounts, error) {
+	evidence, err := o.repository.CountOldEvidence(ctx, evidenceMaxAge)
+	if err != nil {
+		return CleanupCounts{}, err
+	}
+	expired, err := o.executor.ExpiredCacheEntries(ctx)
+	if err != nil {
+		return CleanupCounts{}, fmt.Errorf("failed to count expired cache entries: %w", err)
+	}
+	return CleanupCounts{OrphanedEvidence: evidence, ExpiredCacheEntries: expired}, nil
+}
+
+// RunCleanup removes orphaned evidence older than evidenceMaxAge and expired
+// cache entries. Both only delete rows nothing uses, so running it
+// repeatedly or alongside the background workers is safe.
+func (o *Orchestrator) RunCleanup(ctx context.Context, evidenceMaxAge time.Duration) (CleanupCounts, error) {
+	evidence, err := o.CleanupOldData(ctx, evidenceMaxAge)
+	if err != nil {
+		return CleanupCounts{}, err
+	}
+	expired, err := o.executor.CleanupExpiredCache(ctx)
+	if err != nil {
+		return CleanupCounts{OrphanedEvidence: evidence}, fmt.Errorf("failed to clean up expired cache entries: %w", err)
+	}
+	log.Printf("Maintenance: removed %d orphaned evidence rows and %d expired cache entries", evidence, expired)
+	return CleanupCounts{OrphanedEvidence: evidence, ExpiredCacheEntries: expired}, nil
+}
+
+// SoftDeleteOldAnalyses marks analyses older than the retention window as deleted
+func (o *Orchestrator) SoftDeleteOldAnalyses(ctx context.Context, olderThan time.Duration) (int, error) {
+	return o.repository.SoftDeleteAnalysesOlderThan(ctx, olderThan)
+}
+
+// StartRetentionWorker periodically removes orphaned evidence and, when
+// analysisRetention is positive, soft-deletes analyses older than it.
+// It blocks until ctx is cancelled.
+func (o *Orchestrator) StartRetentionWorker(ctx context.Context, interval, evidenceMaxAge, analysisRetention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.runRetention(ctx, evidenceMaxAge, analysisRetention)
+		}
+	}
+}
+
+// runRetention performs a single retention pass
+func (o *Orchestrator) runRetention(ctx context.Context, evidenceMaxAge, analysisRetention time.Duration) {
+	if analysisRetention > 0 {
+		deleted, err := o.SoftDeleteOldAnalyses(ctx, analysisRetention)
+		if err != nil {
+			log.Printf("Retention: failed to soft-delete analyses: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Retention: soft-deleted %d analyses older than %s", deleted, analysisRetention)
+		}
+	}
+
+	removed, err := o.CleanupOldData(ctx, evidenceMaxAge)
+	if err != nil {
+		log.Printf("Retention: failed to clean up evidence: %v", err)
+	} else if removed > 0 {
+		log.Printf("Retention: removed %d orphaned evidence rows older than %s", removed, evidenceMaxAge)
+	}
+}
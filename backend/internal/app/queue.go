@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"rectaify/pkg/types"
+)
+
+// AnalysisQueue runs analyses on a fixed pool of workers instead of the
+// goroutines of the requests that asked for them, so the number of analyses
+// competing for the LLM rate limit and the database pool is bounded. Requests
+// arriving while every worker is busy wait in a queue of fixed depth and are
+// rejected when it is full.
+type AnalysisQueue struct {
+	orchestrator *Orchestrator
+	jobs         chan analysisJob
+	workers      int
+	running      atomic.Int64
+
+	mu     sync.RWMutex // guards closed and sends on jobs
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// analysisJob is one queued analysis and where its outcome goes
+type analysisJob struct {
+	ctx    context.Context
+	run    func(ctx context.Context) (analysisID string, cached bool, err error)
+	result chan analysisResult
+}
+
+type analysisResult struct {
+	analysisID string
+	cached     bool
+	err        error
+}
+
+// NewAnalysisQueue creates a queue holding at most depth waiting analyses
+func NewAnalysisQueue(orchestrator *Orchestrator, workers, depth int) *AnalysisQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	if depth < 0 {
+		depth = 0
+	}
+
+	return &AnalysisQueue{
+		orchestrator: orchestrator,
+		jobs:         make(chan analysisJob, depth),
+		workers:      workers,
+	}
+}
+
+// Start runs the workers until Shutdown
+func (q *AnalysisQueue) Start() {
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+}
+
+func (q *AnalysisQueue) worker() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		// The requester gave up while the job was queued
+		if err := job.ctx.Err(); err != nil {
+			job.result <- analysisResult{err: err}
+			continue
+		}
+
+		q.running.Add(1)
+		analysisID, cached, err := job.run(job.ctx)
+		q.running.Add(-1)
+		job.result <- analysisResult{analysisID: analysisID, cached: cached, err: err}
+	}
+}
+
+// Analyze queues an analysis and waits for a worker to run it. It fails with
+// ErrQueueFull at once when no queue slot is free, and returns early when ctx
+// ends; the analysis itself runs under ctx, so it is cancelled too.
+func (q *AnalysisQueue) Analyze(ctx context.Context, request types.AnalysisRequest) (analysisID string, cached bool, err error) {
+	return q.submit(ctx, false, func(ctx context.Context) (string, bool, error) {
+		return q.orchestrator.AnalyzeIdea(ctx, request)
+	})
+}
+
+// Refresh queues an evidence refresh of a stored analysis like Analyze,
+// returning the ID of the new analysis
+func (q *AnalysisQueue) Refresh(ctx context.Context, analysisID string, freshSearch bool) (string, error) {
+	refreshedID, _, err := q.submit(ctx, false, func(ctx context.Context) (string, bool, error) {
+		refreshedID, err := q.orchestrator.RefreshEvidence(ctx, analysisID, freshSearch)
+		return refreshedID, false, err
+	})
+	return refreshedID, err
+}
+
+// AnalyzeBatch runs several analyses through the queue and returns each one's
+// outcome, in order. At most as many of them wait or run at a time as there
+// are workers, so a batch never fills the queue on its own; one failing
+// doesn't stop the others. Unlike Analyze, items wait for a queue slot rather
+// than failing with ErrQueueFull. Once ctx ends, items not yet started fail
+// with its error. Results are indexed by their place in requests.
+func (q *AnalysisQueue) AnalyzeBatch(ctx context.Context, requests []types.AnalysisRequest) []types.BatchItemResult {
+	results := make([]types.BatchItemResult, len(requests))
+	slots := make(chan struct{}, q.workers)
+
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(requests); j++ {
+				results[j] = types.BatchItemResult{Index: j, Status: "failed", Error: ctx.Err().Error()}
+			}
+			wg.Wait()
+			return results
+		}
+
+		wg.Add(1)
+		go func(i int, request types.AnalysisRequest) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			analysisID, cached, err := q.submit(ctx, true, func(ctx context.Context) (string, bool, error) {
+				return q.orchestrator.AnalyzeIdea(ctx, request)
+			})
+			results[i] = types.BatchItemResult{Index: i, AnalysisID: analysisID, Status: "completed", Cached: cached}
+			if err != nil {
+				results[i] = types.BatchItemResult{Index: i, Status: "failed", Error: err.Error()}
+			}
+		}(i, request)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// submit queues a job and waits for its outcome. Without wait it fails with
+// ErrQueueFull when the queue is full; with it, it waits for a slot until ctx
+// ends. Workers keep draining the queue while Shutdown waits for the lock, so
+// a waiting send holding it always completes.
+func (q *AnalysisQueue) submit(ctx context.Context, wait bool, run func(ctx context.Context) (string, bool, error)) (analysisID string, cached bool, err error) {
+	job := analysisJob{ctx: ctx, run: run, result: make(chan analysisResult, 1)}
+
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return "", false, ErrQueueClosed
+	}
+	if wait {
+		select {
+		case q.jobs <- job:
+			q.mu.RUnlock()
+		case <-ctx.Done():
+			q.mu.RUnlock()
+			return "", false, ctx.Err()
+		}
+	} else {
+		select {
+		case q.jobs <- job:
+			q.mu.RUnlock()
+		default:
+			q.mu.RUnlock()
+			return "", false, ErrQueueFull
+		}
+	}
+
+	select {
+	case result := <-job.result:
+		return result.analysisID, result.cached, result.err
+	case <-ctx.Done():
+		return "", false, ctx.Err()
+	}
+}
+
+// Stats reports the pool size, analyses running and analyses waiting
+func (q *AnalysisQueue) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"workers":     q.workers,
+		"running":     q.running.Load(),
+		"queued":      len(q.jobs),
+		"queue_depth": cap(q.jobs),
+	}
+}
+
+// Shutdown stops accepting analyses and waits for queued and running ones to
+// finish, or for ctx to end
+func (q *AnalysisQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
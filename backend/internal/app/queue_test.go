@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rectaify/pkg/types"
+)
+
+func TestSubmitWaitsForQueueSlot(t *testing.T) {
+	queue := NewAnalysisQueue(nil, 1, 1)
+	run := func(ctx context.Context) (string, bool, error) { return "analysis", false, nil }
+
+	// With no worker running, the first job fills the queue
+	go queue.submit(context.Background(), false, run)
+	deadline := time.Now().Add(time.Second)
+	for len(queue.jobs) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, _, err := queue.submit(context.Background(), false, run); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("got %v from a full queue, want ErrQueueFull", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := queue.submit(ctx, true, run); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v waiting on a full queue, want the context's error", err)
+	}
+
+	// Once workers drain the queue, a waiting job gets its turn
+	queue.Start()
+	defer queue.Shutdown(context.Background())
+	analysisID, _, err := queue.submit(context.Background(), true, run)
+	if err != nil || analysisID != "analysis" {
+		t.Fatalf("got %q, %v once the queue drained", analysisID, err)
+	}
+}
+
+func TestAnalyzeBatchStopsOnCancel(t *testing.T) {
+	// Workers never start, so nothing reaches the orchestrator
+	queue := NewAnalysisQueue(nil, 2, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requests := make([]types.AnalysisRequest, 5)
+	done := make(chan []types.BatchItemResult)
+	go func() { done <- queue.AnalyzeBatch(ctx, requests) }()
+
+	select {
+	case results := <-done:
+		if len(results) != len(requests) {
+			t.Fatalf("got %d results, want %d", len(results), len(requests))
+		}
+		for i, result := range results {
+			if result.Index != i || result.Status != "failed" || result.Error != context.Canceled.Error() {
+				t.Errorf("result %d: got %+v, want failed with %v", i, result, context.Canceled)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("AnalyzeBatch kept going after its context was cancelled")
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Bounds on the tags of one analysis
+const (
+	maxTags      = 10
+	maxTagLength = 32
+)
+
+// tagPattern is the format of a normalized tag, e.g. "q1-screening"
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// normalizeTags trims and lowercases tags and drops repeats, keeping the
+// order given. It rejects tags outside tagPattern or maxTagLength, and more
+// than maxTags. The result is never nil, so it also clears stored tags.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if len(tag) > maxTagLength || !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTags, tag)
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("%w: %d given", ErrInvalidTags, len(normalized))
+	}
+	return normalized, nil
+}
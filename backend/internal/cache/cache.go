@@ -5,6 +5,9 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -20,15 +23,36 @@ type Cache struct {
 	db  *pgxpool.Pool
 	sf  singleflight.Group
 	ttl time.Duration
+
+	collisions atomic.Int64 // lookups whose hash matched an entry stored for another key
 }
 
 // CacheEntry represents a cached item
 type CacheEntry struct {
+	Key       string          `json:"-"` // unhashed key, to detect hash collisions
 	Data      json.RawMessage `json:"data"`
 	CreatedAt time.Time       `json:"created_at"`
 	TTL       time.Duration   `json:"ttl"`
 }
 
+// SearchKey builds the evidence cache key for a search query and location.
+// Queries differing only in case or whitespace, and a nil or empty location,
+// map to the same key.
+func SearchKey(query string, location *types.ApproxLocation) string {
+	key := strings.ToLower(strings.Join(strings.Fields(query), " "))
+
+	if location = location.Normalize(); location != nil {
+		if location.Country != "" {
+			key += "|country:" + location.Country
+		}
+		if location.Region != "" {
+			key += "|region:" + strings.ToLower(location.Region)
+		}
+	}
+
+	return key
+}
+
 // NewCache creates a new cache instance
 func NewCache(db *pgxpool.Pool, lruSize int, ttl time.Duration) (*Cache, error) {
 	lruCache, err := lru.New[string, *CacheEntry](lruSize)
@@ -74,20 +98,39 @@ func (c *Cache) Get(ctx context.Context, key string) (json.RawMessage, bool, err
 
 // Set stores data in both LRU and database
 func (c *Cache) Set(ctx context.Context, key string, data json.RawMessage) error {
+	return c.SetWithTTL(ctx, key, data, c.ttl)
+}
+
+// SetWithTTL stores data like Set, expiring after ttl rather than the cache's TTL
+func (c *Cache) SetWithTTL(ctx context.Context, key string, data json.RawMessage, ttl time.Duration) error {
 	hash := c.hashKey(key)
 
 	entry := &CacheEntry{
 		Data:      data,
 		CreatedAt: time.Now(),
-		TTL:       c.ttl,
+		TTL:       ttl,
 	}
 
+	entry.Key = key
+
 	// Store in LRU
 	c.lru.Add(hash, entry)
 
 	// Store in database (only if database is available)
 	if c.db != nil {
-		return c.setDB(ctx, hash, key, data)
+		return c.setDB(ctx, hash, key, data, ttl)
+	}
+	return nil
+}
+
+// Delete removes an entry from both LRU and database
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	hash := c.hashKey(key)
+
+	c.lru.Remove(hash)
+
+	if c.db != nil {
+		return c.deleteDB(ctx, hash)
 	}
 	return nil
 }
@@ -96,6 +139,9 @@ func (c *Cache) Set(ctx context.Context, key string, data json.RawMessage) error
 func (c *Cache) get(ctx context.Context, key, hash string) (*CacheEntry, error) {
 	// Check LRU first
 	if entry, exists := c.lru.Get(hash); exists {
+		if c.collides(key, hash, entry) {
+			return nil, nil
+		}
 		if !c.isExpired(entry) {
 			return entry, nil
 		}
@@ -115,6 +161,10 @@ func (c *Cache) get(ctx context.Context, key, hash string) (*CacheEntry, error)
 		}
 	}
 
+	if found && c.collides(key, hash, entry) {
+		return nil, nil
+	}
+
 	if found && !c.isExpired(entry) {
 		// Populate LRU with fresh data from DB
 		c.lru.Add(hash, entry)
@@ -131,14 +181,15 @@ func (c *Cache) get(ctx context.Context, key, hash string) (*CacheEntry, error)
 
 // getDB retrieves entry from database
 func (c *Cache) getDB(ctx context.Context, hash string) (*CacheEntry, bool, error) {
+	var key string
 	var result json.RawMessage
 	var createdAt time.Time
 	var ttlSeconds int
 
 	err := c.db.QueryRow(ctx,
-		"SELECT result, created_at, ttl_seconds FROM web_cache WHERE hash = $1",
+		"SELECT query, result, created_at, ttl_seconds FROM web_cache WHERE hash = $1",
 		hash,
-	).Scan(&result, &createdAt, &ttlSeconds)
+	).Scan(&key, &result, &createdAt, &ttlSeconds)
 
 	if err != nil {
 		if err.Error() == "no rows in result set" {
@@ -148,6 +199,7 @@ func (c *Cache) getDB(ctx context.Context, hash string) (*CacheEntry, bool, erro
 	}
 
 	entry := &CacheEntry{
+		Key:       key,
 		Data:      result,
 		CreatedAt: createdAt,
 		TTL:       time.Duration(ttlSeconds) * time.Second,
@@ -157,7 +209,7 @@ func (c *Cache) getDB(ctx context.Context, hash string) (*CacheEntry, bool, erro
 }
 
 // setDB stores entry in database
-func (c *Cache) setDB(ctx context.Context, hash, key string, data json.RawMessage) error {
+func (c *Cache) setDB(ctx context.Context, hash, key string, data json.RawMessage, ttl time.Duration) error {
 	_, err := c.db.Exec(ctx,
 		`INSERT INTO web_cache (hash, query, result, created_at, ttl_seconds) 
 		 VALUES ($1, $2, $3, $4, $5)
@@ -166,7 +218,7 @@ func (c *Cache) setDB(ctx context.Context, hash, key string, data json.RawMessag
 		 result = EXCLUDED.result,
 		 created_at = EXCLUDED.created_at,
 		 ttl_seconds = EXCLUDED.ttl_seconds`,
-		hash, key, data, time.Now(), int(c.ttl.Seconds()),
+		hash, key, data, time.Now(), int(ttl.Seconds()),
 	)
 	return err
 }
@@ -182,6 +234,23 @@ func (c *Cache) isExpired(entry *CacheEntry) bool {
 	return time.Since(entry.CreatedAt) > entry.TTL
 }
 
+// collides reports whether an entry found under hash was stored for a
+// different key. SHA-256 collisions are not expected; this verifies it, and a
+// colliding entry is treated as a miss rather than served for the wrong key.
+func (c *Cache) collides(key, hash string, entry *CacheEntry) bool {
+	if entry.Key == "" || entry.Key == key {
+		return false
+	}
+	c.collisions.Add(1)
+	log.Printf("Cache: key collision on hash %s: %q looked up, %q stored", hash[:12], key, entry.Key)
+	return true
+}
+
+// Collisions is the number of lookups that hit an entry stored for another key
+func (c *Cache) Collisions() int64 {
+	return c.collisions.Load()
+}
+
 // hashKey creates a stable hash for cache keys
 func (c *Cache) hashKey(key string) string {
 	hash := sha256.Sum256([]byte(key))
@@ -195,7 +264,7 @@ func (c *Cache) warmupFromDB(ctx context.Context) {
 	}
 
 	rows, err := c.db.Query(ctx,
-		`SELECT hash, result, created_at, ttl_seconds 
+		`SELECT hash, query, result, created_at, ttl_seconds 
 		 FROM web_cache 
 		 WHERE created_at + (ttl_seconds || ' seconds')::INTERVAL > NOW()
 		 ORDER BY created_at DESC 
@@ -207,16 +276,17 @@ func (c *Cache) warmupFromDB(ctx context.Context) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var hash string
+		var hash, key string
 		var result json.RawMessage
 		var createdAt time.Time
 		var ttlSeconds int
 
-		if err := rows.Scan(&hash, &result, &createdAt, &ttlSeconds); err != nil {
+		if err := rows.Scan(&hash, &key, &result, &createdAt, &ttlSeconds); err != nil {
 			continue
 		}
 
 		entry := &CacheEntry{
+			Key:       key,
 			Data:      result,
 			CreatedAt: createdAt,
 			TTL:       time.Duration(ttlSeconds) * time.Second,
@@ -228,17 +298,31 @@ func (c *Cache) warmupFromDB(ctx context.Context) {
 	}
 }
 
-// CleanupExpired removes expired entries from database
-func (c *Cache) CleanupExpired(ctx context.Context) error {
-	_, err := c.db.Exec(ctx,
+// CleanupExpired removes expired entries from database, returning how many
+// were removed
+func (c *Cache) CleanupExpired(ctx context.Context) (int, error) {
+	result, err := c.db.Exec(ctx,
 		"DELETE FROM web_cache WHERE created_at + (ttl_seconds || ' seconds')::INTERVAL < NOW()",
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// CountExpired counts the expired entries CleanupExpired would remove
+func (c *Cache) CountExpired(ctx context.Context) (int, error) {
+	var count int
+	err := c.db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM web_cache WHERE created_at + (ttl_seconds || ' seconds')::INTERVAL < NOW()",
+	).Scan(&count)
+	return count, err
 }
 
 // EvidenceCache provides specialized caching for search evidence
 type EvidenceCache struct {
 	cache *Cache
+	ttls  map[string]time.Duration // by query intent or source type
 }
 
 // StartCleanupWorker starts a background worker to clean expired entries
@@ -246,14 +330,63 @@ func (ec *EvidenceCache) StartCleanupWorker(ctx context.Context, interval time.D
 	ec.cache.StartCleanupWorker(ctx, interval)
 }
 
-// NewEvidenceCache creates a cache specifically for evidence
-func NewEvidenceCache(db *pgxpool.Pool, lruSize int, ttl time.Duration) (*EvidenceCache, error) {
+// CleanupExpired removes expired entries from the database. The table is
+// shared, so entries of every cache are removed.
+func (ec *EvidenceCache) CleanupExpired(ctx context.Context) (int, error) {
+	return ec.cache.CleanupExpired(ctx)
+}
+
+// CountExpired counts the expired entries of every cache in the database
+func (ec *EvidenceCache) CountExpired(ctx context.Context) (int, error) {
+	return ec.cache.CountExpired(ctx)
+}
+
+// Collisions is the number of evidence lookups that hit an entry stored for another key
+func (ec *EvidenceCache) Collisions() int64 {
+	return ec.cache.Collisions()
+}
+
+// NewEvidenceCache creates a cache specifically for evidence. Results are
+// kept for ttl unless ttls, keyed by query intent or source type, sets
+// another lifetime; see TTL.
+func NewEvidenceCache(db *pgxpool.Pool, lruSize int, ttl time.Duration, ttls map[string]time.Duration) (*EvidenceCache, error) {
 	cache, err := NewCache(db, lruSize, ttl)
 	if err != nil {
 		return nil, err
 	}
 
-	return &EvidenceCache{cache: cache}, nil
+	return &EvidenceCache{cache: cache, ttls: ttls}, nil
+}
+
+// TTL is how long the results of a query with intent are cached: the TTL
+// configured for the intent, otherwise the one for the source type most of
+// the results have, otherwise the cache's TTL. Regulation is stable for
+// months while funding news goes stale in days.
+func (ec *EvidenceCache) TTL(intent string, evidence []types.Evidence) time.Duration {
+	if ttl, ok := ec.ttls[intent]; ok {
+		return ttl
+	}
+	if ttl, ok := ec.ttls[dominantSourceType(evidence)]; ok {
+		return ttl
+	}
+	return ec.cache.ttl
+}
+
+// dominantSourceType returns the source type most of the evidence has, the
+// first seen on a tie, or "" for no typed evidence
+func dominantSourceType(evidence []types.Evidence) string {
+	counts := make(map[string]int)
+	dominant := ""
+	for _, ev := range evidence {
+		if ev.SourceType == "" {
+			continue
+		}
+		counts[ev.SourceType]++
+		if counts[ev.SourceType] > counts[dominant] {
+			dominant = ev.SourceType
+		}
+	}
+	return dominant
 }
 
 // GetEvidence retrieves cached evidence for a query
@@ -271,14 +404,109 @@ func (ec *EvidenceCache) GetEvidence(ctx context.Context, query string) ([]types
 	return evidence, true, nil
 }
 
-// SetEvidence stores evidence in cache
-func (ec *EvidenceCache) SetEvidence(ctx context.Context, query string, evidence []types.Evidence) error {
+// SetEvidence stores the evidence found by a query with intent in cache, for
+// as long as TTL says
+func (ec *EvidenceCache) SetEvidence(ctx context.Context, query, intent string, evidence []types.Evidence) error {
 	data, err := json.Marshal(evidence)
 	if err != nil {
 		return fmt.Errorf("failed to marshal evidence: %w", err)
 	}
 
-	return ec.cache.Set(ctx, query, data)
+	return ec.cache.SetWithTTL(ctx, query, data, ec.TTL(intent, evidence))
+}
+
+// AnalysisCache caches completed analyses by idea fingerprint so an identical
+// request can skip the whole pipeline
+type AnalysisCache struct {
+	cache *Cache
+}
+
+// NewAnalysisCache creates a cache specifically for completed analyses
+func NewAnalysisCache(db *pgxpool.Pool, lruSize int, ttl time.Duration) (*AnalysisCache, error) {
+	cache, err := NewCache(db, lruSize, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnalysisCache{cache: cache}, nil
+}
+
+// analysisKeyPrefix keeps analysis entries apart from evidence queries in web_cache
+const analysisKeyPrefix = "analysis:"
+
+// GetAnalysis retrieves a cached analysis for a fingerprint key
+func (ac *AnalysisCache) GetAnalysis(ctx context.Context, key string) (types.Analysis, bool, error) {
+	data, found, err := ac.cache.Get(ctx, analysisKeyPrefix+key)
+	if err != nil || !found {
+		return types.Analysis{}, found, err
+	}
+
+	var analysis types.Analysis
+	if err := json.Unmarshal(data, &analysis); err != nil {
+		return types.Analysis{}, false, fmt.Errorf("failed to unmarshal analysis: %w", err)
+	}
+
+	return analysis, true, nil
+}
+
+// SetAnalysis stores a completed analysis under a fingerprint key
+func (ac *AnalysisCache) SetAnalysis(ctx context.Context, key string, analysis types.Analysis) error {
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+
+	return ac.cache.Set(ctx, analysisKeyPrefix+key, data)
+}
+
+// Invalidate removes the cached analysis for a fingerprint key
+func (ac *AnalysisCache) Invalidate(ctx context.Context, key string) error {
+	return ac.cache.Delete(ctx, analysisKeyPrefix+key)
+}
+
+// AnalyzerCache caches dimension analyzer results by a hash of their inputs,
+// so re-analyzing an idea over the same evidence only reruns scoring and the
+// verdict
+type AnalyzerCache struct {
+	cache *Cache
+}
+
+// NewAnalyzerCache creates a cache specifically for dimension analyzer results
+func NewAnalyzerCache(db *pgxpool.Pool, lruSize int, ttl time.Duration) (*AnalyzerCache, error) {
+	cache, err := NewCache(db, lruSize, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnalyzerCache{cache: cache}, nil
+}
+
+// analyzerKeyPrefix keeps analyzer entries apart from other entries in web_cache
+const analyzerKeyPrefix = "analyzers:"
+
+// GetDimensions retrieves cached dimension results for an input key
+func (ac *AnalyzerCache) GetDimensions(ctx context.Context, key string) (types.DimensionResults, bool, error) {
+	data, found, err := ac.cache.Get(ctx, analyzerKeyPrefix+key)
+	if err != nil || !found {
+		return types.DimensionResults{}, found, err
+	}
+
+	var results types.DimensionResults
+	if err := json.Unmarshal(data, &results); err != nil {
+		return types.DimensionResults{}, false, fmt.Errorf("failed to unmarshal analyzer results: %w", err)
+	}
+
+	return results, true, nil
+}
+
+// SetDimensions stores dimension results under an input key
+func (ac *AnalyzerCache) SetDimensions(ctx context.Context, key string, results types.DimensionResults) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analyzer results: %w", err)
+	}
+
+	return ac.cache.Set(ctx, analyzerKeyPrefix+key, data)
 }
 
 // StartCleanupWorker starts a background worker to clean expired entries
@@ -291,7 +519,7 @@ func (c *Cache) StartCleanupWorker(ctx context.Context, interval time.Duration)
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if err := c.CleanupExpired(ctx); err != nil {
+			if _, err := c.CleanupExpired(ctx); err != nil {
 				// Log error but continue
 				continue
 			}
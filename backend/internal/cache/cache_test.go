@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"rectaify/pkg/types"
+)
+
+func TestSearchKeyLocationEquivalence(t *testing.T) {
+	const query = "ai tutoring market size"
+	noLocation := SearchKey(query, nil)
+
+	equivalent := map[string]*types.ApproxLocation{
+		"empty location":           {},
+		"blank country and region": {Country: "  ", Region: " \t"},
+	}
+	for name, location := range equivalent {
+		if got := SearchKey(query, location); got != noLocation {
+			t.Errorf("%s: got key %q, want %q as with no location", name, got, noLocation)
+		}
+	}
+
+	if got := SearchKey("  AI   Tutoring market size ", nil); got != noLocation {
+		t.Errorf("case and whitespace changed the key: %q", got)
+	}
+
+	germany := SearchKey(query, &types.ApproxLocation{Country: "DE"})
+	if germany == noLocation {
+		t.Error("a country didn't change the key")
+	}
+	if got := SearchKey(query, &types.ApproxLocation{Country: " de "}); got != germany {
+		t.Errorf("country case and spacing changed the key: %q, want %q", got, germany)
+	}
+	bavaria := SearchKey(query, &types.ApproxLocation{Country: "DE", Region: "Bavaria"})
+	if bavaria == germany {
+		t.Error("a region didn't change the key")
+	}
+	if got := SearchKey(query, &types.ApproxLocation{Country: "de", Region: "  bavaria "}); got != bavaria {
+		t.Errorf("region case and spacing changed the key: %q, want %q", got, bavaria)
+	}
+}
+
+func TestGetDetectsCollision(t *testing.T) {
+	cache, err := NewCache(nil, 16, time.Hour)
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	ctx := context.Background()
+	data := json.RawMessage(`{"found":true}`)
+
+	if err := cache.Set(ctx, "ai tutoring", data); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got, ok, err := cache.Get(ctx, "ai tutoring"); err != nil || !ok || string(got) != string(data) {
+		t.Fatalf("Get = %s, %v, %v; want the stored entry", got, ok, err)
+	}
+
+	// Force an entry stored for one key under another key's hash
+	cache.lru.Add(cache.hashKey("ai tutoring|country:DE"), &CacheEntry{
+		Key:       "ai tutoring",
+		Data:      data,
+		CreatedAt: time.Now(),
+		TTL:       time.Hour,
+	})
+	if _, ok, err := cache.Get(ctx, "ai tutoring|country:DE"); err != nil || ok {
+		t.Fatalf("colliding entry was served (found %v, err %v)", ok, err)
+	}
+	if got := cache.Collisions(); got != 1 {
+		t.Errorf("got %d collisions, want 1", got)
+	}
+}
+
+func TestEvidenceTTLByIntent(t *testing.T) {
+	ttls := map[string]time.Duration{
+		"funding":    48 * time.Hour,
+		"regulation": 90 * 24 * time.Hour,
+		"news":       24 * time.Hour,
+	}
+	ec, err := NewEvidenceCache(nil, 16, 7*24*time.Hour, ttls)
+	if err != nil {
+		t.Fatalf("NewEvidenceCache: %v", err)
+	}
+
+	news := []types.Evidence{{SourceType: "news"}, {SourceType: "news"}, {SourceType: "blog"}}
+	tests := []struct {
+		name     string
+		intent   string
+		evidence []types.Evidence
+		want     time.Duration
+	}{
+		{"funding intent", "funding", nil, 48 * time.Hour},
+		{"regulation intent", "regulation", nil, 90 * 24 * time.Hour},
+		{"intent wins over source type", "regulation", news, 90 * 24 * time.Hour},
+		{"unconfigured intent falls back to source type", "competitors", news, 24 * time.Hour},
+		{"nothing configured", "market", []types.Evidence{{SourceType: "blog"}}, 7 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ec.TTL(tt.intent, tt.evidence); got != tt.want {
+				t.Errorf("got TTL %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetEvidenceExpiresByIntent(t *testing.T) {
+	ec, err := NewEvidenceCache(nil, 16, 7*24*time.Hour, map[string]time.Duration{
+		"funding":    48 * time.Hour,
+		"regulation": 90 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewEvidenceCache: %v", err)
+	}
+	ctx := context.Background()
+	evidence := []types.Evidence{{ID: "ev_1", URL: "https://example.com"}}
+
+	queries := map[string]string{
+		"funding":    "ai tutoring seed round",
+		"regulation": "ai tutoring student privacy law",
+		"market":     "ai tutoring market size",
+	}
+	for intent, query := range queries {
+		if err := ec.SetEvidence(ctx, query, intent, evidence); err != nil {
+			t.Fatalf("SetEvidence(%s): %v", intent, err)
+		}
+		// Backdate the entry three days, past the funding TTL only
+		entry, ok := ec.cache.lru.Get(ec.cache.hashKey(query))
+		if !ok {
+			t.Fatalf("%s: entry was not stored", intent)
+		}
+		entry.CreatedAt = entry.CreatedAt.Add(-72 * time.Hour)
+	}
+
+	want := map[string]bool{"funding": false, "regulation": true, "market": true}
+	for intent, query := range queries {
+		_, found, err := ec.GetEvidence(ctx, query)
+		if err != nil {
+			t.Fatalf("GetEvidence(%s): %v", intent, err)
+		}
+		if found != want[intent] {
+			t.Errorf("%s: got found %v three days later, want %v", intent, found, want[intent])
+		}
+	}
+}
@@ -1,7 +1,12 @@
 package config
 
 import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -11,64 +16,783 @@ import (
 
 // Config holds all application configuration
 type Config struct {
+	// ConfigFile is the optional YAML or JSON file of settings that fills in
+	// the ones the environment leaves unset; fileErr is why it failed to load
+	ConfigFile string
+	fileErr    error
+
 	// HTTP Server
 	HTTPAddr string
+	// Server timeouts; 0 disables each. HTTPWriteTimeout bounds ordinary
+	// routes; routes that run analyses or stream responses use
+	// HTTPLongWriteTimeout instead, which must outlast ANALYSIS_TIMEOUT_MAX.
+	HTTPReadTimeout       time.Duration
+	HTTPReadHeaderTimeout time.Duration // 0 falls back to HTTPReadTimeout
+	HTTPWriteTimeout      time.Duration
+	HTTPLongWriteTimeout  time.Duration
+	HTTPIdleTimeout       time.Duration
 
 	// Database
 	DatabaseDSN string
+	// Startup connection and migration attempts; the delay doubles after
+	// each failure so the service waits for a database that is coming up
+	DBConnectAttempts int
+	DBConnectDelay    time.Duration
 
 	// OpenAI
-	OpenAIAPIKey string
-	OpenAIRPS    int
-	OpenAIBurst  int
+	OpenAIAPIKey      string
+	OpenAIBaseURL     string
+	OpenAIAPIVersion  string // Azure OpenAI only
+	OpenAIModel       string
+	OpenAISearchModel string // defaults to OpenAIModel
+	OpenAIRPS         int
+	OpenAIBurst       int
+	OpenAIStrictRate  bool // no bursts; requests evenly spaced at OpenAIRPS
+	OpenAITPM         int  // tokens-per-minute budget; 0 disables
+	OpenAIMaxRetries  int  // retries of one request after a transient failure
+	OpenAIRetryBudget int  // retries shared by all requests of an analysis; 0 disables
+	// Time one attempt of an LLM request may take to respond, and the longer
+	// limit of the verdict request; 0 leaves attempts to the analysis timeout
+	OpenAIRequestTimeout  time.Duration
+	VerdictRequestTimeout time.Duration
+	// MaintenanceMode starts the API with LLM calls switched off; admins toggle
+	// it at runtime through /v1/maintenance/mode
+	MaintenanceMode bool
+	// OpenAIFallbackModels answer analysis requests, in order, when
+	// OpenAIModel is overloaded or refuses; the search model has none
+	OpenAIFallbackModels []string
 
 	// Cache
-	CacheLRUSize int
-	CacheTTL     time.Duration
-	CacheDir     string
+	CacheLRUSize     int
+	CacheTTL         time.Duration
+	CacheTTLsJSON    string // evidence cache lifetime by query intent or source type, overriding CacheTTL
+	CacheDir         string
+	AnalysisCacheTTL time.Duration // identical requests reuse a completed analysis; 0 disables
+	AnalyzerCacheTTL time.Duration // identical analyzer inputs reuse dimension results; 0 disables
 
 	// Analysis
 	MaxEvidencePerQuery int
+	MaxEvidenceBytes    int // serialized evidence kept per analysis after normalization; 0 is unlimited
+	StrictEvidence      bool
+	StrictEvidenceMin   int // evidence items an analysis needs in strict mode
 	MaxQueries          int
+	QuerySimilarity     float64  // planned queries of one intent more alike than this are merged
+	PlannerStopWords    []string // left out of query key terms, on top of the built-in list
+	PlannerMaxKeyTerms  int      // key terms and phrases queries are planned from
 	AnalysisTimeout     time.Duration
+	MinAnalysisTimeout  time.Duration
+	MaxAnalysisTimeout  time.Duration
+	SearchTimeout       time.Duration // evidence search phase, within AnalysisTimeout
+	SearchConcurrency   int           // searches in flight at once per analysis
+	SearchOverlap       bool          // run priority batches concurrently instead of in turn
+	StaleEvidenceAge    time.Duration // median cited-evidence age that triggers a warning; 0 disables
+	EvidenceCutoffAge   time.Duration // evidence published longer ago is dropped; 0 keeps all
+	KeepUndatedEvidence bool          // keep evidence without a published date under the cutoff
+	AnalyzerConcurrency int           // analyzers run at once per analysis
+	AnalyzerTimeout     time.Duration // each dimension analyzer; 0 shares the analysis deadline
+	AnalysisWorkers     int           // analyses run at once by the API
+	AnalysisQueueDepth  int           // analyses waiting for a worker before requests are rejected
+	BatchMaxSize        int           // ideas accepted by one batch analyze request
+	// SourceTypeWeightsJSON overrides evidence trust weights per source type,
+	// e.g. {"forum":0.1,"social":0}
+	SourceTypeWeightsJSON   string
+	SourceTypeCapsJSON      string // most evidence items kept per source type, e.g. {"news":10}
+	DefaultSourceTypeWeight float64
+	IncludePDFEvidence      bool // keep PDF search results; titled ones without a snippet are flagged
+	// CategoryPromptHintsJSON maps idea categories to per-dimension prompt
+	// hints, e.g. {"fintech":{"risks":"Emphasize compliance risks."}}
+	CategoryPromptHintsJSON string
+	CategoryTaxonomyJSON    string // canonical idea categories to their synonyms, replacing the built-in ones
+	SnippetLimitsJSON       string // evidence snippet characters sent to each analyzer
+	FXRatesJSON             string // US dollars per unit of currency, overriding built-in rates
+	EvidencePolicy          string // "keep", "flag" or "drop" items citing no valid evidence
+	VerdictMaxEvidence      int    // evidence items sent to the verdict analyzer; 0 sends all
+	VerdictCondensed        bool   // send the verdict analyzer dimension summaries only
+	VerdictStrict           bool   // fail the verdict when LLM enhancement fails instead of using the calculated one
+	NextStepsEnabled        bool   // generate a validation plan for every analysis (an extra LLM call)
+	IdeaExpansionEnabled    bool   // expand vague ideas before planning searches (an extra LLM call)
+	ReportEvidenceOrder     string // "quality", "date" or "source" order of report references
+	// Most items each report section lists, most important first; 0 lists
+	// all. Analyses keep every item.
+	ReportMaxCompetitors int
+	ReportMaxRisks       int
+	ReportMaxBarriers    int
+	ReportMaxGraveyard   int
+	// HTML report branding; empty keeps the default theme
+	ReportPrimaryColor string
+	ReportLogoURL      string
+	ReportFooterText   string
+	// Category and location given to requests that leave them empty
+	DefaultCategory string
+	DefaultLocation string
+	// Graveyard scores when no failure cases are found, after postmortem
+	// searches returned results and when none did
+	GraveyardSearched   float64
+	GraveyardUnsearched float64
+	// Analyses scoring below it overall are flagged for review; 0 flags none
+	ReviewScoreThreshold float64
+	// Distinct domains expected behind each dimension's cited evidence
+	MinSourceDomains int
+	// Relevance of an analysis's findings to its idea, from 0 to 1, below
+	// which the analysis is suspect; 0 skips the check (an extra LLM call)
+	MinCoherenceScore float64
+
+	// Keeps each analyzer's raw LLM output and the evidence IDs validation
+	// stripped from it in meta.debug, served only to admins; it bloats stored
+	// analyses
+	AnalyzerDebugMeta bool
+
+	// Analyses a category needs before /v1/insights aggregates it
+	InsightsMinAnalyses int
+
+	// Records each analysis request and its verdict in the append-only,
+	// hash-chained audit log
+	AuditLog bool
+
+	// Scrubbing of personal data and wordlist terms before storage
+	ScrubEnabled  bool
+	ScrubWordlist []string
+
+	// Redirect resolution of evidence URLs before deduplication
+	ResolveRedirects    bool
+	RedirectTimeout     time.Duration // per HEAD request
+	RedirectConcurrency int
+
+	// Retention
+	RetentionEnabled  bool
+	RetentionInterval time.Duration
+	EvidenceMaxAge    time.Duration
+	AnalysisRetention time.Duration // 0 keeps analyses forever
 
 	// Security
-	BearerToken string
+	BearerToken            string
+	AdminToken             string
+	CORSAllowedOrigins     []string
+	SecurityHeadersEnabled bool
+	HSTSMaxAge             time.Duration // 0 disables HSTS
+	APIDeprecationsJSON    string        // deprecated routes, answered with Deprecation and Sunset headers
+
+	// Outbound HTTP. Requests to the OpenAI API and webhooks honor
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+	OutboundCAFile string // PEM CA bundle trusted in addition to the system roots
+
+	// Compression
+	CompressionEnabled  bool
+	CompressionMinBytes int
+
+	// Webhooks for analysis lifecycle events
+	WebhookSubscribersJSON string
+	WebhookSecret          string // HMAC key for delivery signatures; empty sends unsigned
+	WebhookMaxAttempts     int
+	WebhookTimeout         time.Duration
+	WebhookWorkers         int
 
 	// Telemetry
 	LogLevel string
 }
 
-// Load reads configuration from environment variables with defaults
+// defaultCORSOrigins allows the local frontend dev servers with credentials and
+// falls back to a wildcard for everything else
+var defaultCORSOrigins = []string{
+	"http://localhost:5173", // Vite default
+	"http://localhost:5174", // Vite alternative
+	"http://localhost:5175", // Vite alternative
+	"http://localhost:5176", // User's frontend
+	"http://localhost:3000", // React default
+	"http://localhost:3001", // React alternative
+	"http://127.0.0.1:5173",
+	"http://127.0.0.1:5174",
+	"http://127.0.0.1:5175",
+	"http://127.0.0.1:5176",
+	"http://127.0.0.1:3000",
+	"http://127.0.0.1:3001",
+	"*",
+}
+
+// Load reads configuration from environment variables with defaults. The
+// settings file named by CONFIG_FILE, if any, fills in variables that neither
+// the environment nor the .env file set; Validate reports if it can't be read.
 func Load() *Config {
 	// Try to load .env file (ignore errors if it doesn't exist)
 	godotenv.Load()
 
+	configFile := getEnv("CONFIG_FILE", "")
+	var fileErr error
+	if configFile != "" {
+		fileErr = loadFile(configFile)
+	}
+
 	return &Config{
-		HTTPAddr:            getEnv("HTTP_ADDR", ":9444"),
-		DatabaseDSN:         expandEnv(getEnv("DB_DSN", "postgres://localhost/rectaify?sslmode=disable")),
-		OpenAIAPIKey:        getEnv("OPENAI_API_KEY", ""),
-		OpenAIRPS:           getEnvInt("OPENAI_RPS", 2),
-		OpenAIBurst:         getEnvInt("OPENAI_BURST", 4),
-		CacheLRUSize:        getEnvInt("CACHE_LRU_SIZE", 4096),
-		CacheTTL:            getEnvDuration("CACHE_TTL", 24*time.Hour),
-		CacheDir:            getEnv("CACHE_DIR", "/var/lib/rectaify/cache"),
-		MaxEvidencePerQuery: getEnvInt("MAX_EVIDENCE_PER_QUERY", 10),
-		MaxQueries:          getEnvInt("MAX_QUERIES", 20),
-		AnalysisTimeout:     getEnvDuration("ANALYSIS_TIMEOUT", 60*time.Second),
-		BearerToken:         getEnv("BEARER_TOKEN", ""),
-		LogLevel:            getEnv("LOG_LEVEL", "info"),
+		ConfigFile:              configFile,
+		fileErr:                 fileErr,
+		HTTPAddr:                getEnv("HTTP_ADDR", ":9444"),
+		HTTPReadTimeout:         getEnvDuration("HTTP_READ_TIMEOUT", 30*time.Second),
+		HTTPReadHeaderTimeout:   getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 0),
+		HTTPWriteTimeout:        getEnvDuration("HTTP_WRITE_TIMEOUT", 120*time.Second),
+		HTTPLongWriteTimeout:    getEnvDuration("HTTP_LONG_WRITE_TIMEOUT", 0),
+		HTTPIdleTimeout:         getEnvDuration("HTTP_IDLE_TIMEOUT", 60*time.Second),
+		DatabaseDSN:             expandEnv(getEnv("DB_DSN", "postgres://localhost/rectaify?sslmode=disable")),
+		DBConnectAttempts:       getEnvInt("DB_CONNECT_ATTEMPTS", 5),
+		DBConnectDelay:          getEnvDuration("DB_CONNECT_DELAY", 2*time.Second),
+		OpenAIAPIKey:            getEnv("OPENAI_API_KEY", ""),
+		OpenAIBaseURL:           getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		OpenAIAPIVersion:        getEnv("OPENAI_API_VERSION", ""),
+		OpenAIModel:             getEnv("OPENAI_MODEL", "gpt-4o"),
+		OpenAISearchModel:       getEnv("OPENAI_SEARCH_MODEL", ""),
+		OpenAIFallbackModels:    getEnvList("OPENAI_FALLBACK_MODELS", nil),
+		OpenAIRPS:               getEnvInt("OPENAI_RPS", 2),
+		OpenAIBurst:             getEnvInt("OPENAI_BURST", 4),
+		OpenAIStrictRate:        getEnvBool("OPENAI_RATE_LIMIT_STRICT", false),
+		OpenAITPM:               getEnvInt("OPENAI_TPM", 0),
+		OpenAIMaxRetries:        getEnvInt("OPENAI_MAX_RETRIES", 2),
+		OpenAIRetryBudget:       getEnvInt("OPENAI_RETRY_BUDGET", 6),
+		OpenAIRequestTimeout:    getEnvDuration("OPENAI_REQUEST_TIMEOUT", 30*time.Second),
+		VerdictRequestTimeout:   getEnvDuration("VERDICT_REQUEST_TIMEOUT", 2*time.Minute),
+		MaintenanceMode:         getEnvBool("MAINTENANCE_MODE", false),
+		CacheLRUSize:            getEnvInt("CACHE_LRU_SIZE", 4096),
+		CacheTTL:                getEnvDuration("CACHE_TTL", 24*time.Hour),
+		CacheTTLsJSON:           getEnv("EVIDENCE_CACHE_TTLS", defaultCacheTTLs),
+		CacheDir:                getEnv("CACHE_DIR", "/var/lib/rectaify/cache"),
+		AnalysisCacheTTL:        getEnvDuration("ANALYSIS_CACHE_TTL", 0),
+		AnalyzerCacheTTL:        getEnvDuration("ANALYZER_CACHE_TTL", 0),
+		MaxEvidencePerQuery:     getEnvInt("MAX_EVIDENCE_PER_QUERY", 10),
+		MaxEvidenceBytes:        getEnvInt("EVIDENCE_MAX_TOTAL_BYTES", 256<<10),
+		StrictEvidence:          getEnvBool("STRICT_EVIDENCE", false),
+		StrictEvidenceMin:       getEnvInt("STRICT_EVIDENCE_MIN", 1),
+		MaxQueries:              getEnvInt("MAX_QUERIES", 20),
+		QuerySimilarity:         getEnvFloat("QUERY_SIMILARITY_THRESHOLD", 0.8),
+		PlannerStopWords:        getEnvList("PLANNER_STOP_WORDS", nil),
+		PlannerMaxKeyTerms:      getEnvInt("PLANNER_MAX_KEY_TERMS", 6),
+		AnalysisTimeout:         getEnvDuration("ANALYSIS_TIMEOUT", 60*time.Second),
+		MinAnalysisTimeout:      getEnvDuration("ANALYSIS_TIMEOUT_MIN", 10*time.Second),
+		MaxAnalysisTimeout:      getEnvDuration("ANALYSIS_TIMEOUT_MAX", 5*time.Minute),
+		SearchTimeout:           getEnvDuration("SEARCH_TIMEOUT", 30*time.Second),
+		SearchConcurrency:       getEnvInt("SEARCH_CONCURRENCY", 3),
+		SearchOverlap:           getEnvBool("SEARCH_OVERLAP_BATCHES", false),
+		SourceTypeWeightsJSON:   getEnv("SOURCE_TYPE_WEIGHTS", ""),
+		SourceTypeCapsJSON:      getEnv("SOURCE_TYPE_CAPS", ""),
+		IncludePDFEvidence:      getEnvBool("EVIDENCE_INCLUDE_PDFS", true),
+		DefaultSourceTypeWeight: getEnvFloat("SOURCE_TYPE_DEFAULT_WEIGHT", 0.1),
+		CategoryPromptHintsJSON: getEnv("CATEGORY_PROMPT_HINTS", ""),
+		CategoryTaxonomyJSON:    getEnv("CATEGORY_TAXONOMY", ""),
+		SnippetLimitsJSON:       getEnv("ANALYZER_SNIPPET_LIMITS", ""),
+		FXRatesJSON:             getEnv("FX_RATES_USD", ""),
+		EvidencePolicy:          getEnv("UNSUPPORTED_CLAIMS_POLICY", "keep"),
+		VerdictMaxEvidence:      getEnvInt("VERDICT_MAX_EVIDENCE", 0),
+		VerdictCondensed:        getEnvBool("VERDICT_CONDENSED", false),
+		VerdictStrict:           getEnvBool("VERDICT_STRICT", false),
+		NextStepsEnabled:        getEnvBool("NEXT_STEPS_ENABLED", false),
+		IdeaExpansionEnabled:    getEnvBool("IDEA_EXPANSION_ENABLED", false),
+		ReviewScoreThreshold:    getEnvFloat("REVIEW_SCORE_THRESHOLD", 0),
+		MinSourceDomains:        getEnvInt("MIN_SOURCE_DOMAINS", 2),
+		MinCoherenceScore:       getEnvFloat("COHERENCE_MIN_SCORE", 0),
+		AnalyzerDebugMeta:       getEnvBool("ANALYZER_DEBUG_META", false),
+		InsightsMinAnalyses:     getEnvInt("INSIGHTS_MIN_ANALYSES", 5),
+		AuditLog:                getEnvBool("AUDIT_LOG", false),
+		ReportEvidenceOrder:     getEnv("REPORT_EVIDENCE_ORDER", "quality"),
+		ReportMaxCompetitors:    getEnvInt("REPORT_MAX_COMPETITORS", 0),
+		ReportMaxRisks:          getEnvInt("REPORT_MAX_RISKS", 0),
+		ReportMaxBarriers:       getEnvInt("REPORT_MAX_BARRIERS", 0),
+		ReportMaxGraveyard:      getEnvInt("REPORT_MAX_GRAVEYARD", 0),
+		ReportPrimaryColor:      getEnv("REPORT_PRIMARY_COLOR", ""),
+		ReportLogoURL:           getEnv("REPORT_LOGO_URL", ""),
+		ReportFooterText:        getEnv("REPORT_FOOTER_TEXT", ""),
+		DefaultCategory:         getEnv("DEFAULT_CATEGORY", ""),
+		DefaultLocation:         getEnv("DEFAULT_LOCATION", ""),
+		StaleEvidenceAge:        getEnvDuration("STALE_EVIDENCE_AGE", 2*365*24*time.Hour),
+		EvidenceCutoffAge:       getEnvDuration("EVIDENCE_PUBLISHED_CUTOFF", 0),
+		KeepUndatedEvidence:     getEnvBool("EVIDENCE_KEEP_UNDATED", true),
+		GraveyardSearched:       getEnvFloat("GRAVEYARD_NO_FAILURES_SCORE", 60),
+		GraveyardUnsearched:     getEnvFloat("GRAVEYARD_UNSEARCHED_SCORE", 60),
+		AnalyzerConcurrency:     getEnvInt("ANALYZER_CONCURRENCY", 6),
+		AnalyzerTimeout:         getEnvDuration("ANALYZER_TIMEOUT", 0),
+		AnalysisWorkers:         getEnvInt("ANALYSIS_WORKERS", 4),
+		AnalysisQueueDepth:      getEnvInt("ANALYSIS_QUEUE_DEPTH", 32),
+		BatchMaxSize:            getEnvInt("BATCH_MAX_SIZE", 20),
+		ScrubEnabled:            getEnvBool("SCRUB_ENABLED", false),
+		ScrubWordlist:           getEnvList("SCRUB_WORDLIST", nil),
+		ResolveRedirects:        getEnvBool("REDIRECT_RESOLUTION_ENABLED", false),
+		RedirectTimeout:         getEnvDuration("REDIRECT_RESOLUTION_TIMEOUT", 3*time.Second),
+		RedirectConcurrency:     getEnvInt("REDIRECT_RESOLUTION_CONCURRENCY", 4),
+		RetentionEnabled:        getEnvBool("RETENTION_ENABLED", false),
+		RetentionInterval:       getEnvDuration("RETENTION_INTERVAL", time.Hour),
+		EvidenceMaxAge:          getEnvDuration("EVIDENCE_MAX_AGE", 30*24*time.Hour),
+		AnalysisRetention:       getEnvDuration("ANALYSIS_RETENTION", 0),
+		BearerToken:             getEnv("BEARER_TOKEN", ""),
+		AdminToken:              getEnv("ADMIN_TOKEN", ""),
+		CORSAllowedOrigins:      getEnvList("CORS_ALLOWED_ORIGINS", defaultCORSOrigins),
+		SecurityHeadersEnabled:  getEnvBool("SECURITY_HEADERS_ENABLED", true),
+		HSTSMaxAge:              getEnvDuration("HSTS_MAX_AGE", 180*24*time.Hour),
+		OutboundCAFile:          getEnv("OUTBOUND_CA_FILE", ""),
+		CompressionEnabled:      getEnvBool("COMPRESSION_ENABLED", true),
+		CompressionMinBytes:     getEnvInt("COMPRESSION_MIN_BYTES", 1024),
+		APIDeprecationsJSON:     getEnv("API_DEPRECATIONS", ""),
+		WebhookSubscribersJSON:  getEnv("WEBHOOK_SUBSCRIBERS", ""),
+		WebhookSecret:           getEnv("WEBHOOK_SECRET", ""),
+		WebhookMaxAttempts:      getEnvInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		WebhookTimeout:          getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookWorkers:          getEnvInt("WEBHOOK_WORKERS", 2),
+		LogLevel:                getEnv("LOG_LEVEL", "info"),
 	}
 }
 
 // Validate checks if required configuration is present
 func (c *Config) Validate() error {
+	if c.fileErr != nil {
+		return c.fileErr
+	}
 	if c.OpenAIAPIKey == "" {
 		return ErrMissingOpenAIKey
 	}
+	if c.OpenAIMaxRetries < 0 || c.OpenAIRetryBudget < 0 {
+		return ErrInvalidRetries
+	}
+	if c.OpenAIRequestTimeout < 0 || c.VerdictRequestTimeout < 0 {
+		return ErrInvalidLLMTimeout
+	}
+	if c.MinAnalysisTimeout > c.MaxAnalysisTimeout || c.AnalysisTimeout < c.MinAnalysisTimeout || c.AnalysisTimeout > c.MaxAnalysisTimeout {
+		return ErrInvalidTimeoutBounds
+	}
+	if c.SearchTimeout <= 0 || c.SearchTimeout >= c.AnalysisTimeout {
+		return ErrInvalidSearchTimeout
+	}
+	if c.SearchConcurrency < 1 {
+		return ErrInvalidSearchConcurrency
+	}
+	if c.QuerySimilarity < 0 || c.QuerySimilarity >= 1 {
+		return fmt.Errorf("%w: QUERY_SIMILARITY_THRESHOLD=%g", ErrInvalidQuerySimilarity, c.QuerySimilarity)
+	}
+	if c.PlannerMaxKeyTerms < 1 {
+		return ErrInvalidKeyTerms
+	}
+	if c.InsightsMinAnalyses < 1 {
+		return ErrInvalidInsightsMinimum
+	}
+	if c.HTTPLongWriteTimeout > 0 && c.HTTPLongWriteTimeout <= c.MaxAnalysisTimeout {
+		return ErrInvalidLongWriteTimeout
+	}
+	if c.EvidenceCutoffAge < 0 {
+		return ErrInvalidEvidenceCutoff
+	}
+	if c.AnalyzerConcurrency < 1 {
+		return ErrInvalidAnalyzerConcurrency
+	}
+	if c.AnalyzerTimeout < 0 {
+		return ErrInvalidAnalyzerTimeout
+	}
+	if c.AnalysisWorkers < 1 || c.AnalysisQueueDepth < 0 {
+		return ErrInvalidAnalysisQueue
+	}
+	if c.BatchMaxSize < 1 {
+		return ErrInvalidBatchSize
+	}
+	if _, err := c.SourceTypeWeights(); err != nil {
+		return err
+	}
+	if _, err := c.SourceTypeCaps(); err != nil {
+		return err
+	}
+	if _, err := c.EvidenceCacheTTLs(); err != nil {
+		return err
+	}
+	if _, err := c.OutboundRootCAs(); err != nil {
+		return err
+	}
+	if c.DefaultSourceTypeWeight < 0 || c.DefaultSourceTypeWeight > 1 {
+		return fmt.Errorf("%w: SOURCE_TYPE_DEFAULT_WEIGHT=%g", ErrInvalidSourceWeight, c.DefaultSourceTypeWeight)
+	}
+	if c.GraveyardSearched < 0 || c.GraveyardSearched > 100 {
+		return fmt.Errorf("%w: GRAVEYARD_NO_FAILURES_SCORE=%g", ErrInvalidGraveyardScore, c.GraveyardSearched)
+	}
+	if c.GraveyardUnsearched < 0 || c.GraveyardUnsearched > 100 {
+		return fmt.Errorf("%w: GRAVEYARD_UNSEARCHED_SCORE=%g", ErrInvalidGraveyardScore, c.GraveyardUnsearched)
+	}
+	if c.ReviewScoreThreshold < 0 || c.ReviewScoreThreshold > 100 {
+		return fmt.Errorf("%w: REVIEW_SCORE_THRESHOLD=%g", ErrInvalidReviewThreshold, c.ReviewScoreThreshold)
+	}
+	if c.MinSourceDomains < 0 {
+		return ErrInvalidSourceDomains
+	}
+	if c.MinCoherenceScore < 0 || c.MinCoherenceScore > 1 {
+		return fmt.Errorf("%w: COHERENCE_MIN_SCORE=%g", ErrInvalidCoherenceScore, c.MinCoherenceScore)
+	}
+	if _, err := c.CategoryPromptHints(); err != nil {
+		return err
+	}
+	if _, err := c.CategoryTaxonomy(); err != nil {
+		return err
+	}
+	if _, err := c.SnippetLimits(); err != nil {
+		return err
+	}
+	if _, err := c.FXRates(); err != nil {
+		return err
+	}
+	if !evidencePolicies[strings.ToLower(strings.TrimSpace(c.EvidencePolicy))] {
+		return ErrInvalidEvidencePolicy
+	}
+	if !evidenceOrders[strings.ToLower(strings.TrimSpace(c.ReportEvidenceOrder))] {
+		return ErrInvalidEvidenceOrder
+	}
+	if c.ReportMaxCompetitors < 0 || c.ReportMaxRisks < 0 || c.ReportMaxBarriers < 0 || c.ReportMaxGraveyard < 0 {
+		return ErrInvalidReportCaps
+	}
+	if err := c.validateReportTheme(); err != nil {
+		return err
+	}
+	if c.StrictEvidence && c.StrictEvidenceMin < 1 {
+		return ErrInvalidStrictEvidence
+	}
+	if c.VerdictMaxEvidence < 0 {
+		return ErrInvalidVerdictEvidence
+	}
+	if c.DBConnectAttempts < 1 || c.DBConnectDelay < 0 {
+		return ErrInvalidDBConnect
+	}
+	if c.RetentionEnabled && c.RetentionInterval <= 0 {
+		return ErrInvalidRetentionInterval
+	}
+	if _, err := c.WebhookSubscribers(); err != nil {
+		return err
+	}
+	if _, err := c.APIDeprecations(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// MinEvidence is the evidence an analysis must gather to run: StrictEvidenceMin
+// in strict mode and none otherwise
+func (c *Config) MinEvidence() int {
+	if !c.StrictEvidence {
+		return 0
+	}
+	return c.StrictEvidenceMin
+}
+
+// SourceTypeWeights parses the source-type weight overrides, checking each is in [0,1]
+func (c *Config) SourceTypeWeights() (map[string]float64, error) {
+	if c.SourceTypeWeightsJSON == "" {
+		return nil, nil
+	}
+
+	var weights map[string]float64
+	if err := json.Unmarshal([]byte(c.SourceTypeWeightsJSON), &weights); err != nil {
+		return nil, fmt.Errorf("%w: SOURCE_TYPE_WEIGHTS is not a JSON object of numbers: %v", ErrInvalidSourceWeight, err)
+	}
+	for sourceType, weight := range weights {
+		if weight < 0 || weight > 1 {
+			return nil, fmt.Errorf("%w: %s=%g", ErrInvalidSourceWeight, sourceType, weight)
+		}
+	}
+	return weights, nil
+}
+
+// SourceTypeCaps parses the per-source-type evidence caps, checking each is positive
+func (c *Config) SourceTypeCaps() (map[string]int, error) {
+	if c.SourceTypeCapsJSON == "" {
+		return nil, nil
+	}
+
+	var caps map[string]int
+	if err := json.Unmarshal([]byte(c.SourceTypeCapsJSON), &caps); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSourceCaps, err)
+	}
+	for sourceType, limit := range caps {
+		if limit < 1 {
+			return nil, fmt.Errorf("%w: %s=%d", ErrInvalidSourceCaps, sourceType, limit)
+		}
+	}
+	return caps, nil
+}
+
+// defaultCacheTTLs keeps regulation evidence for a month and funding and news
+// for three days
+const defaultCacheTTLs = `{"regulation":"720h","regulatory":"720h","funding":"72h","news":"72h"}`
+
+// EvidenceCacheTTLs parses the evidence cache lifetimes by query intent or
+// source type, checking each is a positive duration
+func (c *Config) EvidenceCacheTTLs() (map[string]time.Duration, error) {
+	if c.CacheTTLsJSON == "" {
+		return nil, nil
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(c.CacheTTLsJSON), &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCacheTTLs, err)
+	}
+
+	ttls := make(map[string]time.Duration, len(raw))
+	for key, value := range raw {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("%w: %s=%q", ErrInvalidCacheTTLs, key, value)
+		}
+		ttls[key] = ttl
+	}
+	return ttls, nil
+}
+
+// OutboundRootCAs loads OutboundCAFile on top of the system roots. It returns
+// nil, leaving the system roots alone, when no file is set.
+func (c *Config) OutboundRootCAs() (*x509.CertPool, error) {
+	if c.OutboundCAFile == "" {
+		return nil, nil
+	}
+
+	pem, err := os.ReadFile(c.OutboundCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCAFile, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: no certificates found in %s", ErrInvalidCAFile, c.OutboundCAFile)
+	}
+	return pool, nil
+}
+
+// Snippet limits returned by SnippetLimits for "full" and "title", matching
+// analyzers.SnippetFull and analyzers.SnippetTitleOnly
+const (
+	snippetFull      = -1
+	snippetTitleOnly = 0
+)
+
+// SnippetLimits parses the per-analyzer snippet limits. Each value is "full",
+// "title" (ID, title and URL only) or a positive number of characters.
+func (c *Config) SnippetLimits() (map[string]int, error) {
+	if c.SnippetLimitsJSON == "" {
+		return nil, nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(c.SnippetLimitsJSON), &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnippetLimits, err)
+	}
+
+	limits := make(map[string]int, len(raw))
+	for dimension, value := range raw {
+		if !promptHintDimensions[dimension] && dimension != "verdict" {
+			return nil, fmt.Errorf("%w: unknown analyzer %q", ErrInvalidSnippetLimits, dimension)
+		}
+		switch v := value.(type) {
+		case string:
+			switch strings.ToLower(v) {
+			case "full":
+				limits[dimension] = snippetFull
+			case "title":
+				limits[dimension] = snippetTitleOnly
+			default:
+				return nil, fmt.Errorf("%w: %s=%q", ErrInvalidSnippetLimits, dimension, v)
+			}
+		case float64:
+			if v < 1 || v != float64(int(v)) {
+				return nil, fmt.Errorf("%w: %s=%g", ErrInvalidSnippetLimits, dimension, v)
+			}
+			limits[dimension] = int(v)
+		default:
+			return nil, fmt.Errorf("%w: %s=%v", ErrInvalidSnippetLimits, dimension, v)
+		}
+	}
+	return limits, nil
+}
+
+// FXRates parses the configured exchange rates, keyed by upper-case ISO 4217
+// code. Currencies not listed keep their built-in rates.
+func (c *Config) FXRates() (map[string]float64, error) {
+	if c.FXRatesJSON == "" {
+		return nil, nil
+	}
+
+	var raw map[string]float64
+	if err := json.Unmarshal([]byte(c.FXRatesJSON), &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFXRates, err)
+	}
+
+	rates := make(map[string]float64, len(raw))
+	for currency, rate := range raw {
+		code := strings.ToUpper(strings.TrimSpace(currency))
+		if len(code) != 3 || rate <= 0 {
+			return nil, fmt.Errorf("%w: %s=%g", ErrInvalidFXRates, currency, rate)
+		}
+		rates[code] = rate
+	}
+	return rates, nil
+}
+
+// hexColor matches the colors a report theme accepts, which are inlined in
+// its stylesheet
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateReportTheme checks the HTML report branding
+func (c *Config) validateReportTheme() error {
+	if c.ReportPrimaryColor != "" && !hexColor.MatchString(c.ReportPrimaryColor) {
+		return fmt.Errorf("%w: color %q", ErrInvalidReportTheme, c.ReportPrimaryColor)
+	}
+	if c.ReportLogoURL != "" {
+		parsed, err := url.Parse(c.ReportLogoURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%w: logo %q", ErrInvalidReportTheme, c.ReportLogoURL)
+		}
+	}
+	return nil
+}
+
+// WebhookSubscriber is a webhook endpoint and the lifecycle events it receives
+type WebhookSubscriber struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"` // empty receives every event
+}
+
+// webhookEvents are the lifecycle events a subscriber can filter on
+var webhookEvents = map[string]bool{
+	"*": true, "analysis.created": true, "analysis.completed": true, "analysis.failed": true,
+	"analysis.cancelled": true,
+}
+
+// WebhookSubscribers parses the webhook subscribers, checking each URL and event filter
+func (c *Config) WebhookSubscribers() ([]WebhookSubscriber, error) {
+	if c.WebhookSubscribersJSON == "" {
+		return nil, nil
+	}
+
+	var subscribers []WebhookSubscriber
+	if err := json.Unmarshal([]byte(c.WebhookSubscribersJSON), &subscribers); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhooks, err)
+	}
+	for _, subscriber := range subscribers {
+		parsed, err := url.Parse(subscriber.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%w: invalid url %q", ErrInvalidWebhooks, subscriber.URL)
+		}
+		for _, event := range subscriber.Events {
+			if !webhookEvents[event] {
+				return nil, fmt.Errorf("%w: unknown event %q for %s", ErrInvalidWebhooks, event, subscriber.URL)
+			}
+		}
+	}
+	return subscribers, nil
+}
+
+// APIDeprecation marks a route as deprecated. Dates are YYYY-MM-DD.
+type APIDeprecation struct {
+	Route         string `json:"route"` // a path, or a prefix ending in "/"
+	Since         string `json:"since,omitempty"`
+	Sunset        string `json:"sunset,omitempty"`
+	Link          string `json:"link,omitempty"`
+	BeforeVersion int    `json:"before_version,omitempty"` // only older API versions are deprecated
+}
+
+// SinceTime is the parsed deprecation date, zero when unset
+func (d APIDeprecation) SinceTime() time.Time {
+	since, _ := time.Parse(time.DateOnly, d.Since)
+	return since
+}
+
+// SunsetTime is the parsed sunset date, zero when unset
+func (d APIDeprecation) SunsetTime() time.Time {
+	sunset, _ := time.Parse(time.DateOnly, d.Sunset)
+	return sunset
+}
+
+// APIDeprecations parses the deprecated routes, checking each route and date
+func (c *Config) APIDeprecations() ([]APIDeprecation, error) {
+	if c.APIDeprecationsJSON == "" {
+		return nil, nil
+	}
+
+	var deprecations []APIDeprecation
+	if err := json.Unmarshal([]byte(c.APIDeprecationsJSON), &deprecations); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeprecations, err)
+	}
+	for _, d := range deprecations {
+		if !strings.HasPrefix(d.Route, "/") {
+			return nil, fmt.Errorf("%w: route %q must start with /", ErrInvalidDeprecations, d.Route)
+		}
+		for _, date := range []string{d.Since, d.Sunset} {
+			if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+				return nil, fmt.Errorf("%w: invalid date %q for %s", ErrInvalidDeprecations, date, d.Route)
+			}
+		}
+		if d.BeforeVersion < 0 {
+			return nil, fmt.Errorf("%w: negative before_version for %s", ErrInvalidDeprecations, d.Route)
+		}
+	}
+	return deprecations, nil
+}
+
+// evidencePolicies are the accepted UNSUPPORTED_CLAIMS_POLICY values
+var evidencePolicies = map[string]bool{
+	"":     true,
+	"keep": true,
+	"flag": true,
+	"drop": true,
+}
+
+// evidenceOrders are the accepted REPORT_EVIDENCE_ORDER values
+var evidenceOrders = map[string]bool{
+	"":        true,
+	"quality": true,
+	"date":    true,
+	"source":  true,
+}
+
+// promptHintDimensions are the analyzer dimensions that accept prompt hints
+var promptHintDimensions = map[string]bool{
+	"market": true, "problem": true, "barriers": true,
+	"execution": true, "risks": true, "graveyard": true,
+}
+
+// CategoryPromptHints parses the category prompt hints, rejecting unknown dimensions
+func (c *Config) CategoryPromptHints() (map[string]map[string]string, error) {
+	if c.CategoryPromptHintsJSON == "" {
+		return nil, nil
+	}
+
+	var hints map[string]map[string]string
+	if err := json.Unmarshal([]byte(c.CategoryPromptHintsJSON), &hints); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPromptHints, err)
+	}
+	for category, dimensions := range hints {
+		for dimension := range dimensions {
+			if !promptHintDimensions[dimension] {
+				return nil, fmt.Errorf("%w: unknown dimension %q for category %q", ErrInvalidPromptHints, dimension, category)
+			}
+		}
+	}
+	return hints, nil
+}
+
+// CategoryTaxonomy parses the configured idea categories, returning nil when
+// the built-in ones apply
+func (c *Config) CategoryTaxonomy() (map[string][]string, error) {
+	if c.CategoryTaxonomyJSON == "" {
+		return nil, nil
+	}
+
+	var categories map[string][]string
+	if err := json.Unmarshal([]byte(c.CategoryTaxonomyJSON), &categories); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCategoryTaxonomy, err)
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("%w: no categories", ErrInvalidCategoryTaxonomy)
+	}
+	for category, synonyms := range categories {
+		if strings.TrimSpace(category) == "" {
+			return nil, fmt.Errorf("%w: empty category name", ErrInvalidCategoryTaxonomy)
+		}
+		for _, synonym := range synonyms {
+			if strings.TrimSpace(synonym) == "" {
+				return nil, fmt.Errorf("%w: empty synonym for category %q", ErrInvalidCategoryTaxonomy, category)
+			}
+		}
+	}
+	return categories, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -85,6 +809,40 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList parses a comma-separated list, ignoring empty entries
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if parsed, err := time.ParseDuration(value); err == nil {
@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestValidateSearchTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		searchTimeout string
+		wantErr       error
+	}{
+		{"shorter than the analysis timeout", "20s", nil},
+		{"as long as the analysis timeout", "24s", ErrInvalidSearchTimeout},
+		{"longer than the analysis timeout", "", ErrInvalidSearchTimeout}, // default 30s
+		{"not positive", "-1s", ErrInvalidSearchTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OPENAI_API_KEY", "test")
+			t.Setenv("ANALYSIS_TIMEOUT", "24s")
+			t.Setenv("SEARCH_TIMEOUT", tt.searchTimeout)
+
+			if err := Load().Validate(); err != tt.wantErr {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,5 +3,42 @@ package config
 import "errors"
 
 var (
-	ErrMissingOpenAIKey = errors.New("OPENAI_API_KEY environment variable is required")
+	ErrMissingOpenAIKey           = errors.New("OPENAI_API_KEY environment variable is required")
+	ErrInvalidConfigFile          = errors.New("CONFIG_FILE must be a readable YAML or JSON object of setting names to values")
+	ErrInvalidRetries             = errors.New("OPENAI_MAX_RETRIES and OPENAI_RETRY_BUDGET must not be negative")
+	ErrInvalidLLMTimeout          = errors.New("OPENAI_REQUEST_TIMEOUT and VERDICT_REQUEST_TIMEOUT must not be negative")
+	ErrInvalidDBConnect           = errors.New("DB_CONNECT_ATTEMPTS must be at least 1 and DB_CONNECT_DELAY must not be negative")
+	ErrInvalidRetentionInterval   = errors.New("RETENTION_INTERVAL must be positive when RETENTION_ENABLED is set")
+	ErrInvalidSourceWeight        = errors.New("source type weights must be between 0 and 1")
+	ErrInvalidSourceCaps          = errors.New("SOURCE_TYPE_CAPS must be a JSON object of source type to a positive item count")
+	ErrInvalidCacheTTLs           = errors.New(`EVIDENCE_CACHE_TTLS must be a JSON object of query intent or source type to a positive duration, e.g. "72h"`)
+	ErrInvalidCAFile              = errors.New("OUTBOUND_CA_FILE must be a readable PEM file of CA certificates")
+	ErrInvalidTimeoutBounds       = errors.New("ANALYSIS_TIMEOUT must lie within ANALYSIS_TIMEOUT_MIN and ANALYSIS_TIMEOUT_MAX")
+	ErrInvalidSearchTimeout       = errors.New("SEARCH_TIMEOUT must be positive and shorter than ANALYSIS_TIMEOUT")
+	ErrInvalidSearchConcurrency   = errors.New("SEARCH_CONCURRENCY must be at least 1")
+	ErrInvalidQuerySimilarity     = errors.New("QUERY_SIMILARITY_THRESHOLD must be at least 0 and below 1")
+	ErrInvalidKeyTerms            = errors.New("PLANNER_MAX_KEY_TERMS must be at least 1")
+	ErrInvalidLongWriteTimeout    = errors.New("HTTP_LONG_WRITE_TIMEOUT must be 0 (no timeout) or longer than ANALYSIS_TIMEOUT_MAX")
+	ErrInvalidEvidenceCutoff      = errors.New("EVIDENCE_PUBLISHED_CUTOFF must not be negative")
+	ErrInvalidAnalyzerConcurrency = errors.New("ANALYZER_CONCURRENCY must be at least 1")
+	ErrInvalidAnalyzerTimeout     = errors.New("ANALYZER_TIMEOUT must not be negative")
+	ErrInvalidAnalysisQueue       = errors.New("ANALYSIS_WORKERS must be at least 1 and ANALYSIS_QUEUE_DEPTH must not be negative")
+	ErrInvalidBatchSize           = errors.New("BATCH_MAX_SIZE must be at least 1")
+	ErrInvalidPromptHints         = errors.New("CATEGORY_PROMPT_HINTS must be a JSON object of category to dimension to hint")
+	ErrInvalidCategoryTaxonomy    = errors.New("CATEGORY_TAXONOMY must be a JSON object of category to an array of synonyms")
+	ErrInvalidSnippetLimits       = errors.New(`ANALYZER_SNIPPET_LIMITS values must be "full", "title" or a positive number of characters`)
+	ErrInvalidEvidencePolicy      = errors.New(`UNSUPPORTED_CLAIMS_POLICY must be "keep", "flag" or "drop"`)
+	ErrInvalidEvidenceOrder       = errors.New(`REPORT_EVIDENCE_ORDER must be "quality", "date" or "source"`)
+	ErrInvalidReportCaps          = errors.New("REPORT_MAX_COMPETITORS, REPORT_MAX_RISKS, REPORT_MAX_BARRIERS and REPORT_MAX_GRAVEYARD must not be negative")
+	ErrInvalidReportTheme         = errors.New(`REPORT_PRIMARY_COLOR must be a hex color such as "#1a73e8" and REPORT_LOGO_URL an http or https URL`)
+	ErrInvalidStrictEvidence      = errors.New("STRICT_EVIDENCE_MIN must be at least 1 when STRICT_EVIDENCE is set")
+	ErrInvalidVerdictEvidence     = errors.New("VERDICT_MAX_EVIDENCE must not be negative")
+	ErrInvalidFXRates             = errors.New("FX_RATES_USD must be a JSON object of currency code to a positive US dollar rate")
+	ErrInvalidGraveyardScore      = errors.New("graveyard baseline scores must be between 0 and 100")
+	ErrInvalidReviewThreshold     = errors.New("review score threshold must be between 0 and 100")
+	ErrInvalidSourceDomains       = errors.New("MIN_SOURCE_DOMAINS must not be negative")
+	ErrInvalidCoherenceScore      = errors.New("COHERENCE_MIN_SCORE must be between 0 and 1")
+	ErrInvalidInsightsMinimum     = errors.New("INSIGHTS_MIN_ANALYSES must be at least 1")
+	ErrInvalidDeprecations        = errors.New(`API_DEPRECATIONS must be a JSON array of {"route", "since", "sunset", "link", "before_version"} objects`)
+	ErrInvalidWebhooks            = errors.New(`WEBHOOK_SUBSCRIBERS must be a JSON array of {"url", "events"} objects`)
 )
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadFile reads the YAML or JSON settings file at path into the environment.
+// Its keys are environment variable names, in either case. A variable that is
+// already set, by the environment or the .env file, keeps its value, so the
+// file only fills in what they leave out.
+//
+// Scalars are used as written. Structured values are what the JSON settings
+// such as SOURCE_TYPE_WEIGHTS expect, so they are written out as JSON, except
+// a list of scalars, which becomes the comma-separated list that settings
+// such as CORS_ALLOWED_ORIGINS take.
+func loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfigFile, err)
+	}
+
+	var settings map[string]interface{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &settings)
+	} else {
+		err = yaml.Unmarshal(data, &settings)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfigFile, path, err)
+	}
+
+	for key, value := range settings {
+		key = strings.ToUpper(strings.TrimSpace(key))
+		// Empty counts as unset, as it does for the getEnv helpers
+		if os.Getenv(key) != "" || value == nil {
+			continue
+		}
+		text, err := settingText(plainDates(value))
+		if err != nil {
+			return fmt.Errorf("%w: %s: %s: %v", ErrInvalidConfigFile, path, key, err)
+		}
+		os.Setenv(key, text)
+	}
+	return nil
+}
+
+// plainDates turns the timestamps YAML reads unquoted dates as back into the
+// dates they were written as, so "since: 2024-01-01" stays a date
+func plainDates(value interface{}) interface{} {
+	switch value := value.(type) {
+	case time.Time:
+		if value.Equal(value.Truncate(24 * time.Hour)) {
+			return value.Format(time.DateOnly)
+		}
+		return value.Format(time.RFC3339)
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = plainDates(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = plainDates(item)
+		}
+	}
+	return value
+}
+
+// settingText renders a file setting as its environment variable value
+func settingText(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case float64:
+		// JSON numbers are floats; write whole ones without an exponent
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case map[string]interface{}:
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				encoded, err := json.Marshal(value)
+				return string(encoded), err
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
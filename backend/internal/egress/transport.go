@@ -0,0 +1,27 @@
+// Package egress configures how outbound HTTP requests leave the network
+package egress
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+)
+
+// NewTransport returns a transport for requests to configured services such
+// as the OpenAI API and webhook subscribers. It honors HTTPS_PROXY,
+// HTTP_PROXY and NO_PROXY and trusts rootCAs when set.
+func NewTransport(rootCAs *x509.CertPool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.TLSClientConfig = TLSConfig(rootCAs)
+	return transport
+}
+
+// TLSConfig trusts rootCAs, which config.OutboundRootCAs builds on top of the
+// system roots; nil keeps the defaults
+func TLSConfig(rootCAs *x509.CertPool) *tls.Config {
+	if rootCAs == nil {
+		return nil
+	}
+	return &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+}
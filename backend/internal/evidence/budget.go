@@ -0,0 +1,52 @@
+package evidence
+
+import (
+	"encoding/json"
+
+	"rectaify/pkg/types"
+)
+
+// Truncation reports evidence dropped to stay within the byte budget
+type Truncation struct {
+	BudgetBytes  int  `json:"budget_bytes"`
+	KeptCount    int  `json:"kept_count"`
+	KeptBytes    int  `json:"kept_bytes"`
+	DroppedCount int  `json:"dropped_count"`
+	DroppedBytes int  `json:"dropped_bytes"`
+	Truncated    bool `json:"truncated"`
+}
+
+// LimitBytes keeps evidence, in the given order, until its serialized size
+// would exceed budget. Evidence is expected to be sorted best first, as
+// Normalize returns it, so the highest-quality items survive. A non-positive
+// budget keeps everything.
+func LimitBytes(evidence []types.Evidence, budget int) ([]types.Evidence, Truncation) {
+	truncation := Truncation{BudgetBytes: budget}
+
+	kept := len(evidence)
+	for i, ev := range evidence {
+		size := evidenceSize(ev)
+		if budget > 0 && kept == len(evidence) && truncation.KeptBytes+size > budget {
+			kept = i
+		}
+		if i < kept {
+			truncation.KeptBytes += size
+		} else {
+			truncation.DroppedBytes += size
+		}
+	}
+
+	truncation.KeptCount = kept
+	truncation.DroppedCount = len(evidence) - kept
+	truncation.Truncated = truncation.DroppedCount > 0
+	return evidence[:kept], truncation
+}
+
+// evidenceSize is the number of bytes an evidence item adds to a stored analysis
+func evidenceSize(ev types.Evidence) int {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,89 @@
+package evidence
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Non-article content search results point at. Their snippets are often empty
+// or junk, so they are tagged, scored lower and flagged when nothing was read.
+const (
+	ContentTypePDF       = "pdf"
+	ContentTypeDocument  = "document" // office files
+	ContentTypeVideo     = "video"
+	ContentTypePaywalled = "paywalled" // snippet is a subscription prompt
+)
+
+// contentPenalties are subtracted from the quality score of each content type
+var contentPenalties = map[string]float64{
+	ContentTypePDF:       0.1,
+	ContentTypeDocument:  0.2,
+	ContentTypeVideo:     0.2,
+	ContentTypePaywalled: 0.3,
+}
+
+var documentExtensions = map[string]string{
+	".pdf":  ContentTypePDF,
+	".doc":  ContentTypeDocument,
+	".docx": ContentTypeDocument,
+	".ppt":  ContentTypeDocument,
+	".pptx": ContentTypeDocument,
+	".xls":  ContentTypeDocument,
+	".xlsx": ContentTypeDocument,
+	".mp4":  ContentTypeVideo,
+	".mov":  ContentTypeVideo,
+	".webm": ContentTypeVideo,
+}
+
+// videoHosts serve video pages whatever the path
+var videoHosts = map[string]bool{
+	"youtube.com":     true,
+	"m.youtube.com":   true,
+	"youtu.be":        true,
+	"vimeo.com":       true,
+	"tiktok.com":      true,
+	"loom.com":        true,
+	"wistia.com":      true,
+	"dailymotion.com": true,
+}
+
+// paywallPhrases start snippets of pages the search provider couldn't read
+var paywallPhrases = []string{
+	"subscribe to read",
+	"subscribe to continue",
+	"subscribers only",
+	"this content is for subscribers",
+	"sign in to read",
+	"log in to continue reading",
+	"you have reached your free article limit",
+	"to continue reading",
+}
+
+// detectContentType tags evidence that isn't an article page from its
+// canonical URL, title and snippet. Articles get "".
+func detectContentType(canonicalURL, title, snippet string) string {
+	u, err := url.Parse(canonicalURL)
+	if err != nil {
+		return ""
+	}
+
+	if contentType, ok := documentExtensions[strings.ToLower(path.Ext(u.Path))]; ok {
+		return contentType
+	}
+	if strings.HasPrefix(strings.ToUpper(title), "[PDF]") {
+		return ContentTypePDF
+	}
+	if videoHosts[strings.ToLower(u.Host)] || strings.HasPrefix(u.Path, "/video/") || strings.HasPrefix(u.Path, "/videos/") {
+		return ContentTypeVideo
+	}
+
+	lowerSnippet := strings.ToLower(snippet)
+	for _, phrase := range paywallPhrases {
+		if strings.Contains(lowerSnippet, phrase) {
+			return ContentTypePaywalled
+		}
+	}
+
+	return ""
+}
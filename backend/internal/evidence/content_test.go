@@ -0,0 +1,86 @@
+package evidence
+
+import (
+	"context"
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		url, title, snippet string
+		want                string
+	}{
+		{"https://www.gartner.com/reports/edtech-2024.pdf", "EdTech Outlook 2024", "", ContentTypePDF},
+		{"https://example.org/files/Market-Study.PDF", "Market study", "", ContentTypePDF},
+		{"https://example.org/download?id=7", "[PDF] Tutoring market survey", "", ContentTypePDF},
+		{"https://example.org/deck.pptx", "Investor deck", "", ContentTypeDocument},
+		{"https://youtube.com/watch?v=abc123", "Founder interview", "", ContentTypeVideo},
+		{"https://youtu.be/abc123", "Founder interview", "", ContentTypeVideo},
+		{"https://vimeo.com/123456", "Product demo", "", ContentTypeVideo},
+		{"https://www.cnbc.com/video/2024/03/01/tutoring.html", "Tutoring boom", "", ContentTypeVideo},
+		{"https://cdn.example.com/clips/demo.mp4", "Demo", "", ContentTypeVideo},
+		{"https://www.ft.com/content/abc", "Tutoring market", "Subscribe to read the full story", ContentTypePaywalled},
+		{"https://techcrunch.com/2024/tutoring-raise", "Tutor startup raises", "The company raised $5M.", ""},
+		{"https://example.org/pdf-guide", "A guide to PDFs", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := detectContentType(tt.url, tt.title, tt.snippet); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeNonArticles(t *testing.T) {
+	pdf := types.Evidence{URL: "https://www.oecd.org/education/tutoring-report.pdf", Title: "Private tutoring across the OECD"}
+	video := types.Evidence{URL: "https://vimeo.com/123456", Title: "Tutoring marketplace demo", Snippet: "A walkthrough of the product."}
+
+	t.Run("PDFs included", func(t *testing.T) {
+		n := NewNormalizer(nil, 0.5, nil, nil, nil, true)
+		ev := n.normalizeEvidence(pdf)
+		if ev == nil {
+			t.Fatal("PDF was dropped")
+		}
+		if ev.ContentType != ContentTypePDF || !ev.MissingContent() {
+			t.Errorf("got content type %q, missing content %v; want a flagged PDF", ev.ContentType, ev.MissingContent())
+		}
+
+		// A titled PDF without a snippet survives the quality filter, scored
+		// below the same page as an article
+		kept := n.Normalize(context.Background(), []types.Evidence{pdf})
+		if len(kept) != 1 {
+			t.Fatalf("quality filter kept %d of 1 PDF", len(kept))
+		}
+		article := kept[0]
+		article.ContentType = ""
+		if n.QualityScore(kept[0]) >= n.QualityScore(article) {
+			t.Error("PDF scored no lower than an article")
+		}
+	})
+
+	t.Run("PDFs excluded", func(t *testing.T) {
+		n := NewNormalizer(nil, 0.5, nil, nil, nil, false)
+		if ev := n.normalizeEvidence(pdf); ev != nil {
+			t.Errorf("PDF was kept: %+v", ev)
+		}
+		if ev := n.normalizeEvidence(video); ev == nil {
+			t.Error("excluding PDFs dropped a video")
+		}
+	})
+
+	t.Run("video", func(t *testing.T) {
+		n := NewNormalizer(nil, 0.5, nil, nil, nil, false)
+		ev := n.normalizeEvidence(video)
+		if ev == nil {
+			t.Fatal("video was dropped")
+		}
+		if ev.ContentType != ContentTypeVideo || ev.SourceType != "video" {
+			t.Errorf("got content type %q and source type %q, want video for both", ev.ContentType, ev.SourceType)
+		}
+		if ev.MissingContent() {
+			t.Error("video with a snippet flagged as missing content")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package evidence
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"rectaify/pkg/types"
+)
+
+// Domain returns the host of an evidence URL, lowercased and without a
+// leading "www.", so pages of one site count as one source
+func Domain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// CitedDomains returns the distinct domains of the evidence whose ID is in
+// cited, sorted. Evidence without a parsable URL is left out.
+func CitedDomains(evidence []types.Evidence, cited []string) []string {
+	citedSet := make(map[string]bool, len(cited))
+	for _, id := range cited {
+		citedSet[id] = true
+	}
+
+	seen := make(map[string]bool)
+	var domains []string
+	for _, ev := range evidence {
+		if !citedSet[ev.ID] {
+			continue
+		}
+		domain := Domain(ev.URL)
+		if domain == "" || seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+
+	sort.Strings(domains)
+	return domains
+}
@@ -0,0 +1,80 @@
+package evidence
+
+import (
+	"sort"
+	"time"
+
+	"rectaify/pkg/types"
+)
+
+// Freshness summarizes how old the evidence behind an analysis is
+type Freshness struct {
+	MedianAgeDays float64 `json:"median_age_days"`
+	DatedCount    int     `json:"dated_count"`   // evidence with a published date
+	UndatedCount  int     `json:"undated_count"` // excluded from the median
+	Stale         bool    `json:"stale"`
+}
+
+// DropOlderThan removes evidence published more than maxAge before now, a hard
+// cutoff for fast-moving markets. Undated evidence is kept when keepUndated is
+// set. maxAge 0 keeps everything. It returns the kept evidence and the number
+// of items dropped.
+func DropOlderThan(evidence []types.Evidence, now time.Time, maxAge time.Duration, keepUndated bool) ([]types.Evidence, int) {
+	if maxAge <= 0 {
+		return evidence, 0
+	}
+
+	cutoff := now.Add(-maxAge)
+	kept := make([]types.Evidence, 0, len(evidence))
+	for _, ev := range evidence {
+		if ev.PublishedAt == nil {
+			if keepUndated {
+				kept = append(kept, ev)
+			}
+			continue
+		}
+		if !ev.PublishedAt.Before(cutoff) {
+			kept = append(kept, ev)
+		}
+	}
+	return kept, len(evidence) - len(kept)
+}
+
+// MeasureFreshness computes the median age of the cited evidence. Only evidence
+// whose ID appears in cited is considered; if nothing is cited, all evidence is
+// used. Evidence without a published date is excluded from the median.
+func MeasureFreshness(evidence []types.Evidence, cited []string, now time.Time, staleAfter time.Duration) Freshness {
+	citedSet := make(map[string]bool, len(cited))
+	for _, id := range cited {
+		citedSet[id] = true
+	}
+
+	var ages []float64
+	var freshness Freshness
+	for _, ev := range evidence {
+		if len(citedSet) > 0 && !citedSet[ev.ID] {
+			continue
+		}
+		if ev.PublishedAt == nil {
+			freshness.UndatedCount++
+			continue
+		}
+		ages = append(ages, now.Sub(*ev.PublishedAt).Hours()/24)
+	}
+
+	freshness.DatedCount = len(ages)
+	if len(ages) == 0 {
+		return freshness
+	}
+
+	sort.Float64s(ages)
+	mid := len(ages) / 2
+	if len(ages)%2 == 0 {
+		freshness.MedianAgeDays = (ages[mid-1] + ages[mid]) / 2
+	} else {
+		freshness.MedianAgeDays = ages[mid]
+	}
+
+	freshness.Stale = staleAfter > 0 && freshness.MedianAgeDays > staleAfter.Hours()/24
+	return freshness
+}
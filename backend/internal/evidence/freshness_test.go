@@ -0,0 +1,54 @@
+package evidence
+
+import (
+	"testing"
+	"time"
+
+	"rectaify/pkg/types"
+)
+
+func TestDropOlderThan(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	year := 365 * 24 * time.Hour
+	at := func(ago time.Duration) *time.Time {
+		published := now.Add(-ago)
+		return &published
+	}
+
+	evidence := []types.Evidence{
+		{ID: "last-week", PublishedAt: at(7 * 24 * time.Hour)},
+		{ID: "two-years", PublishedAt: at(2 * year)},
+		{ID: "undated"},
+		{ID: "at-cutoff", PublishedAt: at(year)},
+		{ID: "just-past-cutoff", PublishedAt: at(year + time.Hour)},
+		{ID: "last-month", PublishedAt: at(30 * 24 * time.Hour)},
+	}
+
+	tests := []struct {
+		name        string
+		maxAge      time.Duration
+		keepUndated bool
+		want        []string
+	}{
+		{"one year keeping undated", year, true, []string{"last-week", "undated", "at-cutoff", "last-month"}},
+		{"one year dropping undated", year, false, []string{"last-week", "at-cutoff", "last-month"}},
+		{"no cutoff", 0, false, []string{"last-week", "two-years", "undated", "at-cutoff", "just-past-cutoff", "last-month"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, dropped := DropOlderThan(evidence, now, tt.maxAge, tt.keepUndated)
+			if dropped != len(evidence)-len(tt.want) {
+				t.Errorf("got %d dropped, want %d", dropped, len(evidence)-len(tt.want))
+			}
+			if len(kept) != len(tt.want) {
+				t.Fatalf("kept %d items, want %v", len(kept), tt.want)
+			}
+			for i, ev := range kept {
+				if ev.ID != tt.want[i] {
+					t.Errorf("kept %q at %d, want %q", ev.ID, i, tt.want[i])
+				}
+			}
+		})
+	}
+}
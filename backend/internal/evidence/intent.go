@@ -0,0 +1,59 @@
+package evidence
+
+import (
+	"slices"
+
+	"rectaify/pkg/types"
+)
+
+// TagIntent returns copies of the evidence tagged with the intent of the
+// query that retrieved it, leaving the originals, which may be cached, as
+// they are
+func TagIntent(evidence []types.Evidence, intent string) []types.Evidence {
+	tagged := make([]types.Evidence, len(evidence))
+	for i, ev := range evidence {
+		ev.Intents = MergeIntents(nil, append(slices.Clip(ev.Intents), intent))
+		tagged[i] = ev
+	}
+	return tagged
+}
+
+// MergeIntents returns the intents of both lists, each once, in the order
+// they first appear
+func MergeIntents(a, b []string) []string {
+	var merged []string
+	for _, intent := range append(slices.Clip(a), b...) {
+		if intent != "" && !slices.Contains(merged, intent) {
+			merged = append(merged, intent)
+		}
+	}
+	return merged
+}
+
+// MatchesIntent reports whether evidence was retrieved for one of intents
+func MatchesIntent(ev types.Evidence, intents []string) bool {
+	for _, intent := range ev.Intents {
+		if slices.Contains(intents, intent) {
+			return true
+		}
+	}
+	return false
+}
+
+// RankForIntents orders evidence for a consumer interested in intents:
+// evidence retrieved for one of them comes first. Within each group the
+// evidence keeps its order, which after normalization is best quality first.
+func RankForIntents(evidence []types.Evidence, intents []string) []types.Evidence {
+	ranked := slices.Clone(evidence)
+	slices.SortStableFunc(ranked, func(a, b types.Evidence) int {
+		aMatches, bMatches := MatchesIntent(a, intents), MatchesIntent(b, intents)
+		switch {
+		case aMatches && !bMatches:
+			return -1
+		case bMatches && !aMatches:
+			return 1
+		}
+		return 0
+	})
+	return ranked
+}
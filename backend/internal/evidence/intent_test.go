@@ -0,0 +1,78 @@
+package evidence
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func evidenceIDs(evidence []types.Evidence) []string {
+	ids := make([]string, len(evidence))
+	for i, ev := range evidence {
+		ids[i] = ev.ID
+	}
+	return ids
+}
+
+func TestRankForIntents(t *testing.T) {
+	// In quality order, as normalization leaves them
+	evidence := []types.Evidence{
+		{ID: "generic-best", Intents: []string{"funding"}},
+		{ID: "untagged"},
+		{ID: "problem", Intents: []string{"problem"}},
+		{ID: "generic", Intents: []string{"regulation"}},
+		{ID: "market", Intents: []string{"funding", "market"}},
+	}
+
+	ranked := RankForIntents(evidence, []string{"problem", "market"})
+	want := []string{"problem", "market", "generic-best", "untagged", "generic"}
+	if got := evidenceIDs(ranked); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if evidence[0].ID != "generic-best" {
+		t.Error("ranking reordered the caller's evidence")
+	}
+
+	if got := evidenceIDs(RankForIntents(evidence, nil)); !slices.Equal(got, evidenceIDs(evidence)) {
+		t.Errorf("ranking for no intents changed the order: %v", got)
+	}
+}
+
+func TestTagIntent(t *testing.T) {
+	cached := []types.Evidence{{ID: "a", Intents: []string{"market"}}, {ID: "b"}}
+
+	tagged := TagIntent(cached, "competitors")
+	if !slices.Equal(tagged[0].Intents, []string{"market", "competitors"}) || !slices.Equal(tagged[1].Intents, []string{"competitors"}) {
+		t.Errorf("got intents %v and %v", tagged[0].Intents, tagged[1].Intents)
+	}
+	if !slices.Equal(cached[0].Intents, []string{"market"}) || cached[1].Intents != nil {
+		t.Errorf("tagging changed the cached evidence: %v and %v", cached[0].Intents, cached[1].Intents)
+	}
+
+	if again := TagIntent(tagged, "competitors"); !slices.Equal(again[0].Intents, []string{"market", "competitors"}) {
+		t.Errorf("tagging twice repeated the intent: %v", again[0].Intents)
+	}
+}
+
+func TestNormalizeMergesIntentsOfDuplicates(t *testing.T) {
+	n := NewNormalizer(nil, 0.5, nil, nil, nil, true)
+	result := types.Evidence{
+		URL:     "https://techcrunch.com/2024/tutoring-raise",
+		Title:   "Tutoring startup raises $20M",
+		Snippet: "The round values the online tutoring marketplace at $200M as demand grows.",
+	}
+
+	funding, market := result, result
+	funding.Intents = []string{"funding"}
+	market.Intents = []string{"market"}
+
+	normalized := n.Normalize(context.Background(), []types.Evidence{funding, market})
+	if len(normalized) != 1 {
+		t.Fatalf("got %d items, want the duplicate merged", len(normalized))
+	}
+	if !slices.Equal(normalized[0].Intents, []string{"funding", "market"}) {
+		t.Errorf("got intents %v, want both queries' intents", normalized[0].Intents)
+	}
+}
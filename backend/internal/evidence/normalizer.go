@@ -1,33 +1,132 @@
 package evidence
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"net/url"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"rectaify/internal/scrub"
 	"rectaify/pkg/types"
 )
 
 // Normalizer handles evidence normalization and deduplication
 type Normalizer struct {
-	minHashSize int
+	minHashSize   int
+	sourceWeights map[string]float64
+	defaultWeight float64
+	scrubber      *scrub.Scrubber
+	redirects     *RedirectResolver
+	sourceCaps    map[string]int // most items of each source type Select keeps
+	includePDFs   bool
 }
 
-// NewNormalizer creates a new evidence normalizer
-func NewNormalizer() *Normalizer {
+// DefaultSourceWeights returns the built-in trust weight for each source type
+func DefaultSourceWeights() map[string]float64 {
+	return map[string]float64{
+		"news":         1.0,
+		"database":     0.9,
+		"regulatory":   0.9,
+		"academic":     0.8,
+		"professional": 0.7,
+		"startup":      0.7,
+		"code":         0.6,
+		"blog":         0.5,
+		"forum":        0.4,
+		"social":       0.3,
+		"video":        0.3,
+		"website":      0.2,
+		"unknown":      0.1,
+	}
+}
+
+// DefaultSourceWeight is applied to source types missing from the weight map
+const DefaultSourceWeight = 0.1
+
+// NewNormalizer creates a new evidence normalizer. sourceWeights overrides the
+// built-in weights per source type (nil keeps the defaults) and defaultWeight
+// applies to any source type not in the map. A nil scrubber disables redaction
+// and a nil redirect resolver leaves URLs unresolved. sourceCaps limits how many
+// items of a source type Select keeps; nil or a missing type means no cap.
+// includePDFs decides whether PDF results are kept at all.
+func NewNormalizer(sourceWeights map[string]float64, defaultWeight float64, scrubber *scrub.Scrubber, redirects *RedirectResolver, sourceCaps map[string]int, includePDFs bool) *Normalizer {
+	weights := DefaultSourceWeights()
+	for sourceType, weight := range sourceWeights {
+		weights[sourceType] = weight
+	}
+
 	return &Normalizer{
-		minHashSize: 3, // MinHash signature size
+		minHashSize:   3, // MinHash signature size
+		sourceWeights: weights,
+		defaultWeight: defaultWeight,
+		scrubber:      scrubber,
+		redirects:     redirects,
+		sourceCaps:    sourceCaps,
+		includePDFs:   includePDFs,
+	}
+}
+
+// Select keeps at most max of the normalized evidence, best first, holding
+// each source type to its cap so one highly weighted type can't crowd out the
+// rest. When the capped types can't fill max on their own, the best over-cap
+// items fill the remaining slots, so a pool of a single source type isn't
+// starved. The result stays in quality order.
+func (n *Normalizer) Select(evidence []types.Evidence, max int) []types.Evidence {
+	if len(evidence) <= max {
+		return evidence
+	}
+	if len(n.sourceCaps) == 0 {
+		return evidence[:max]
+	}
+
+	keep := make([]bool, len(evidence))
+	kept := 0
+	perType := make(map[string]int)
+	for i, ev := range evidence {
+		if kept == max {
+			break
+		}
+		if limit, capped := n.sourceCaps[ev.SourceType]; capped && perType[ev.SourceType] >= limit {
+			continue
+		}
+		keep[i] = true
+		kept++
+		perType[ev.SourceType]++
+	}
+	for i := range evidence {
+		if kept == max {
+			break
+		}
+		if !keep[i] {
+			keep[i] = true
+			kept++
+		}
+	}
+
+	selected := make([]types.Evidence, 0, max)
+	for i, ev := range evidence {
+		if keep[i] {
+			selected = append(selected, ev)
+		}
 	}
+	return selected
 }
 
 // Normalize processes and normalizes evidence
-func (n *Normalizer) Normalize(evidence []types.Evidence) []types.Evidence {
+func (n *Normalizer) Normalize(ctx context.Context, evidence []types.Evidence) []types.Evidence {
+	// Follow redirects first so IDs and deduplication use the final URL
+	finalURLs := n.resolveRedirects(ctx, evidence)
+
 	// First pass: normalize individual evidence entries
 	normalized := make([]types.Evidence, 0, len(evidence))
 	for _, ev := range evidence {
+		if final, ok := finalURLs[ev.URL]; ok {
+			ev.URL = final
+		}
 		if normalizedEv := n.normalizeEvidence(ev); normalizedEv != nil {
 			normalized = append(normalized, *normalizedEv)
 		}
@@ -55,17 +154,35 @@ func (n *Normalizer) normalizeEvidence(ev types.Evidence) *types.Evidence {
 		return nil // Invalid URL
 	}
 
-	// Clean title and snippet
-	cleanTitle := n.cleanText(ev.Title)
-	cleanSnippet := n.cleanText(ev.Snippet)
+	// Clean title and snippet, bounding the raw text first so pathological
+	// search content can't make cleaning expensive
+	cleanTitle := n.cleanText(clipRawText(ev.Title))
+	cleanSnippet := n.cleanText(clipRawText(ev.Snippet))
+
+	// Tag PDFs, videos and paywalled pages; a paywall prompt says nothing
+	// about the source, so it isn't kept as a snippet
+	contentType := detectContentType(canonicalURL, cleanTitle, cleanSnippet)
+	if contentType == ContentTypePDF && !n.includePDFs {
+		return nil
+	}
+	if contentType == ContentTypePaywalled {
+		cleanSnippet = ""
+	}
 
 	// Generate stable ID
 	stableID := n.generateStableID(canonicalURL, cleanTitle, ev.PublishedAt)
 
+	// Redact personal data after the ID so it stays stable across scrub settings
+	cleanTitle = n.scrubber.Text(cleanTitle)
+	cleanSnippet = n.scrubber.Text(cleanSnippet)
+
 	// Infer source type if not provided
 	sourceType := ev.SourceType
 	if sourceType == "" {
 		sourceType = n.inferSourceType(canonicalURL)
+		if contentType == ContentTypeVideo && sourceType == "website" {
+			sourceType = "video"
+		}
 	}
 
 	return &types.Evidence{
@@ -76,7 +193,41 @@ func (n *Normalizer) normalizeEvidence(ev types.Evidence) *types.Evidence {
 		PublishedAt: ev.PublishedAt,
 		RetrievedAt: ev.RetrievedAt,
 		SourceType:  sourceType,
+		ContentType: contentType,
+		Intents:     ev.Intents,
+	}
+}
+
+// resolveRedirects maps evidence URLs to the canonical URL they redirect to,
+// omitting URLs that don't redirect
+func (n *Normalizer) resolveRedirects(ctx context.Context, evidence []types.Evidence) map[string]string {
+	if n.redirects == nil {
+		return nil
+	}
+
+	// Look up canonical URLs so tracking-parameter variants share a lookup
+	canonical := make(map[string]string, len(evidence))
+	urls := make([]string, 0, len(evidence))
+	for _, ev := range evidence {
+		if _, seen := canonical[ev.URL]; seen {
+			continue
+		}
+		if canonicalURL := n.canonicalizeURL(ev.URL); canonicalURL != "" {
+			canonical[ev.URL] = canonicalURL
+			urls = append(urls, canonicalURL)
+		}
+	}
+
+	resolved := n.redirects.Resolve(ctx, urls)
+
+	finalURLs := make(map[string]string)
+	for original, canonicalURL := range canonical {
+		final := n.canonicalizeURL(resolved[canonicalURL])
+		if final != "" && final != canonicalURL {
+			finalURLs[original] = final
+		}
 	}
+	return finalURLs
 }
 
 // canonicalizeURL normalizes URLs by removing tracking parameters
@@ -138,6 +289,21 @@ func (n *Normalizer) cleanText(text string) string {
 	return text
 }
 
+// maxRawTextBytes bounds search text before it is cleaned; cleaning keeps at most 500
+const maxRawTextBytes = 8 << 10
+
+// clipRawText cuts text to maxRawTextBytes without splitting a UTF-8 sequence
+func clipRawText(text string) string {
+	if len(text) <= maxRawTextBytes {
+		return text
+	}
+	cut := maxRawTextBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
+
 // generateStableID creates a stable ID for evidence
 func (n *Normalizer) generateStableID(url, title string, publishedAt *time.Time) string {
 	var timeStr string
@@ -226,10 +392,14 @@ func (n *Normalizer) deduplicateEvidence(evidence []types.Evidence) []types.Evid
 	for _, ev := range evidence {
 		key := ev.URL + "|" + ev.Title
 		if existing, exists := urlTitleMap[key]; exists {
-			// Keep the one with more recent publication date
+			// Keep the one with more recent publication date, relevant to
+			// the intents of both
+			intents := MergeIntents(existing.Intents, ev.Intents)
 			if ev.PublishedAt != nil && (existing.PublishedAt == nil || ev.PublishedAt.After(*existing.PublishedAt)) {
-				urlTitleMap[key] = ev
+				existing = ev
 			}
+			existing.Intents = intents
+			urlTitleMap[key] = existing
 		} else {
 			urlTitleMap[key] = ev
 		}
@@ -390,9 +560,11 @@ func (n *Normalizer) selectBestEvidence(evidence []types.Evidence, indices []int
 	// Score each evidence based on quality factors
 	best := evidence[indices[0]]
 	bestScore := n.scoreEvidenceQuality(best)
+	intents := best.Intents
 
 	for i := 1; i < len(indices); i++ {
 		ev := evidence[indices[i]]
+		intents = MergeIntents(intents, ev.Intents)
 		score := n.scoreEvidenceQuality(ev)
 		if score > bestScore {
 			best = ev
@@ -400,32 +572,34 @@ func (n *Normalizer) selectBestEvidence(evidence []types.Evidence, indices []int
 		}
 	}
 
+	// The kept item stands in for the others, whatever they were found for
+	best.Intents = intents
 	return best
 }
 
+// QualityScore is the score evidence is ranked by; items scoring 0.3 or less
+// are dropped during normalization
+func (n *Normalizer) QualityScore(ev types.Evidence) float64 {
+	return n.scoreEvidenceQuality(ev)
+}
+
+// SourceWeight is the configured weight of a source type
+func (n *Normalizer) SourceWeight(sourceType string) float64 {
+	if weight, exists := n.sourceWeights[sourceType]; exists {
+		return weight
+	}
+	return n.defaultWeight
+}
+
 // scoreEvidenceQuality assigns a quality score to evidence
 func (n *Normalizer) scoreEvidenceQuality(ev types.Evidence) float64 {
 	score := 0.0
 
 	// Source type scoring
-	sourceScores := map[string]float64{
-		"news":        1.0,
-		"database":    0.9,
-		"regulatory":  0.9,
-		"academic":    0.8,
-		"professional": 0.7,
-		"startup":     0.7,
-		"code":        0.6,
-		"blog":        0.5,
-		"forum":       0.4,
-		"social":      0.3,
-		"video":       0.3,
-		"website":     0.2,
-		"unknown":     0.1,
-	}
-
-	if sourceScore, exists := sourceScores[ev.SourceType]; exists {
+	if sourceScore, exists := n.sourceWeights[ev.SourceType]; exists {
 		score += sourceScore
+	} else {
+		score += n.defaultWeight
 	}
 
 	// Published date scoring (more recent = better)
@@ -453,6 +627,9 @@ func (n *Normalizer) scoreEvidenceQuality(ev types.Evidence) float64 {
 		score += 0.1
 	}
 
+	// Documents, videos and paywalled pages rarely yield quotable content
+	score -= contentPenalties[ev.ContentType]
+
 	return score
 }
 
@@ -467,7 +644,9 @@ func (n *Normalizer) filterByQuality(evidence []types.Evidence) []types.Evidence
 	scored := make([]scoredEvidence, 0, len(evidence))
 	for _, ev := range evidence {
 		score := n.scoreEvidenceQuality(ev)
-		if score > 0.3 { // Minimum quality threshold
+		// Minimum quality threshold; titled PDFs are kept without a snippet
+		// and flagged instead, as reports are often primary sources
+		if score > 0.3 || ev.ContentType == ContentTypePDF {
 			scored = append(scored, scoredEvidence{evidence: ev, score: score})
 		}
 	}
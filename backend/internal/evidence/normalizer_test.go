@@ -0,0 +1,113 @@
+package evidence
+
+import (
+	"strings"
+	"testing"
+
+	"rectaify/internal/scrub"
+	"rectaify/pkg/types"
+)
+
+func TestNormalizeScrubsTitleAndSnippet(t *testing.T) {
+	n := NewNormalizer(nil, 0.5, scrub.NewScrubber(nil), nil, nil, true)
+
+	ev := n.normalizeEvidence(types.Evidence{
+		URL:     "https://techcrunch.com/2024/acme-raises",
+		Title:   "Acme raises €12.500.000; founder reachable at 415-555-0123",
+		Snippet: "The round was led by Example Ventures. Press: press@acme.io. Valued at 1.250.000.000.",
+	})
+	if ev == nil {
+		t.Fatal("evidence was dropped")
+	}
+
+	if strings.Contains(ev.Title, "415-555-0123") || !strings.Contains(ev.Title, "[phone redacted]") {
+		t.Errorf("phone not redacted from title: %q", ev.Title)
+	}
+	if !strings.Contains(ev.Title, "€12.500.000") {
+		t.Errorf("funding amount lost from title: %q", ev.Title)
+	}
+	if strings.Contains(ev.Snippet, "press@acme.io") || !strings.Contains(ev.Snippet, "[email redacted]") {
+		t.Errorf("email not redacted from snippet: %q", ev.Snippet)
+	}
+	if !strings.Contains(ev.Snippet, "1.250.000.000") {
+		t.Errorf("valuation lost from snippet: %q", ev.Snippet)
+	}
+}
+
+// pool returns evidence of the given source types, in quality order
+func pool(sourceTypes ...string) []types.Evidence {
+	evidence := make([]types.Evidence, len(sourceTypes))
+	for i, sourceType := range sourceTypes {
+		evidence[i] = types.Evidence{ID: string(rune('a' + i)), SourceType: sourceType}
+	}
+	return evidence
+}
+
+func selectedIDs(evidence []types.Evidence) string {
+	var ids strings.Builder
+	for _, ev := range evidence {
+		ids.WriteString(ev.ID)
+	}
+	return ids.String()
+}
+
+func TestSelectSourceCaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		caps     map[string]int
+		evidence []types.Evidence
+		max      int
+		want     string
+	}{
+		{
+			name:     "no caps keeps the best",
+			evidence: pool("news", "news", "news", "forum", "regulatory"),
+			max:      3,
+			want:     "abc",
+		},
+		{
+			name:     "capped type makes room for others",
+			caps:     map[string]int{"news": 2},
+			evidence: pool("news", "news", "news", "news", "forum", "regulatory"),
+			max:      4,
+			want:     "abef",
+		},
+		{
+			name:     "quality order is kept",
+			caps:     map[string]int{"news": 1},
+			evidence: pool("forum", "news", "news", "regulatory", "news"),
+			max:      3,
+			want:     "abd",
+		},
+		{
+			name:     "single source type isn't starved",
+			caps:     map[string]int{"news": 2},
+			evidence: pool("news", "news", "news", "news", "news"),
+			max:      4,
+			want:     "abcd",
+		},
+		{
+			name:     "over-cap items fill what others can't",
+			caps:     map[string]int{"news": 1},
+			evidence: pool("news", "news", "forum", "news", "news"),
+			max:      4,
+			want:     "abcd",
+		},
+		{
+			name:     "pool within max is kept whole",
+			caps:     map[string]int{"news": 1},
+			evidence: pool("news", "news", "news"),
+			max:      5,
+			want:     "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNormalizer(nil, 0.5, nil, nil, tt.caps, true)
+			if got := selectedIDs(n.Select(tt.evidence, tt.max)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package evidence
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+
+	"rectaify/internal/landing"
+)
+
+// Resolved redirects are cached so repeat analyses don't look them up again
+const (
+	redirectCacheSize = 10000
+	redirectCacheTTL  = 24 * time.Hour
+	maxRedirects      = 5
+)
+
+// RedirectResolver follows HTTP redirects with HEAD requests so evidence
+// reached through link shorteners or http-to-https hops is deduplicated by the
+// page it actually lands on
+type RedirectResolver struct {
+	httpClient  *http.Client
+	concurrency int
+	cache       *expirable.LRU[string, string]
+}
+
+// NewRedirectResolver creates a resolver that gives each lookup at most
+// timeout and runs at most concurrency lookups at once. rootCAs adds to the
+// system roots when set.
+func NewRedirectResolver(timeout time.Duration, concurrency int, rootCAs *x509.CertPool) *RedirectResolver {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &RedirectResolver{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: landing.SafeTransport(rootCAs),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return errors.New("too many redirects")
+				}
+				return nil
+			},
+		},
+		concurrency: concurrency,
+		cache:       expirable.NewLRU[string, string](redirectCacheSize, nil, redirectCacheTTL),
+	}
+}
+
+// Resolve returns the final URL of each given URL, keyed by the given URL.
+// URLs that fail to resolve map to themselves. A nil resolver resolves nothing.
+func (r *RedirectResolver) Resolve(ctx context.Context, urls []string) map[string]string {
+	resolved := make(map[string]string, len(urls))
+	if r == nil {
+		return resolved
+	}
+
+	var pending []string
+	for _, u := range urls {
+		if _, seen := resolved[u]; seen {
+			continue
+		}
+		if final, ok := r.cache.Get(u); ok {
+			resolved[u] = final
+			continue
+		}
+		resolved[u] = u
+		pending = append(pending, u)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, r.concurrency)
+
+	for _, u := range pending {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			final, ok := r.resolve(ctx, u)
+			if !ok {
+				return
+			}
+			r.cache.Add(u, final)
+
+			mu.Lock()
+			resolved[u] = final
+			mu.Unlock()
+		}(u)
+	}
+
+	wg.Wait()
+	return resolved
+}
+
+// resolve follows the redirects of one URL. Any response, even an error
+// status, ends the chain; only a failed request reports false. Failures are
+// cached as resolving to the URL itself unless the analysis was cancelled.
+func (r *RedirectResolver) resolve(ctx context.Context, u string) (string, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return u, true
+	}
+	req.Header.Set("User-Agent", "RectAIfy/1.0 (+evidence-dedup)")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return u, false
+		}
+		return u, true
+	}
+	resp.Body.Close()
+
+	return resp.Request.URL.String(), true
+}
@@ -0,0 +1,291 @@
+package landing
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"rectaify/internal/egress"
+	"rectaify/pkg/types"
+)
+
+var (
+	ErrInvalidURL          = errors.New("source URL must be an absolute http or https URL")
+	ErrBlockedAddress      = errors.New("source URL resolves to a private or reserved address")
+	ErrUnsupportedContent  = errors.New("source URL did not return an HTML page")
+	ErrInsufficientContent = errors.New("could not extract a title and description from the source URL")
+)
+
+// minDescriptionLength mirrors the minimum one-liner length accepted by the API
+const minDescriptionLength = 10
+
+var (
+	titleRe     = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	metaTagRe   = regexp.MustCompile(`(?is)<meta\s+[^>]*>`)
+	attrRe      = regexp.MustCompile(`(?is)([a-z:-]+)\s*=\s*("([^"]*)"|'([^']*)')`)
+	paragraphRe = regexp.MustCompile(`(?is)<p[^>]*>(.*?)</p>`)
+	tagRe       = regexp.MustCompile(`(?s)<[^>]+>`)
+	spaceRe     = regexp.MustCompile(`\s+`)
+)
+
+// Fetcher retrieves a landing page and extracts an idea from it
+type Fetcher struct {
+	httpClient *http.Client
+	maxBytes   int64
+}
+
+// NewFetcher creates a landing page fetcher that refuses to connect to
+// internal addresses and reads at most maxBytes of the response body.
+// rootCAs adds to the system roots when set.
+func NewFetcher(maxBytes int64, timeout time.Duration, rootCAs *x509.CertPool) *Fetcher {
+	return &Fetcher{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: SafeTransport(rootCAs),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return ErrInvalidURL
+				}
+				return nil
+			},
+		},
+		maxBytes: maxBytes,
+	}
+}
+
+// SafeTransport returns a transport that refuses to connect to loopback,
+// private and other non-public addresses, for requests to URLs that come from
+// users or search results. It never uses a proxy, which would bypass the
+// check; rootCAs adds to the system roots when set.
+func SafeTransport(rootCAs *x509.CertPool) *http.Transport {
+	return &http.Transport{
+		Proxy:           nil, // A proxy would bypass the address check
+		DialContext:     newSafeDialer().DialContext,
+		TLSClientConfig: egress.TLSConfig(rootCAs),
+	}
+}
+
+// safeDialer connects only to public addresses. It resolves the host itself
+// and dials the address it checked, so DNS rebinding and redirects to internal
+// hosts are blocked as well.
+type safeDialer struct {
+	lookup func(ctx context.Context, host string) ([]netip.Addr, error)
+	dial   func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+func newSafeDialer() *safeDialer {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	return &safeDialer{
+		lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+		dial: dialer.DialContext,
+	}
+}
+
+// DialContext connects to the first public address the host resolves to
+func (d *safeDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ErrBlockedAddress
+	for _, addr := range addrs {
+		if isBlockedAddr(addr) {
+			continue
+		}
+		var conn net.Conn
+		if conn, err = d.dial(ctx, network, net.JoinHostPort(addr.Unmap().String(), port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// Fetch downloads the page at rawURL and extracts a title and one-liner
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (types.IdeaInput, error) {
+	u, err := ParseURL(rawURL)
+	if err != nil {
+		return types.IdeaInput{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return types.IdeaInput{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "RectAIfy/1.0 (+landing-page-import)")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return types.IdeaInput{}, ErrBlockedAddress
+		}
+		return types.IdeaInput{}, fmt.Errorf("failed to fetch source URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return types.IdeaInput{}, fmt.Errorf("source URL returned status %d", resp.StatusCode)
+	}
+
+	contentType := strings.ToLower(resp.Header.Get("Content-Type"))
+	if !strings.Contains(contentType, "text/html") && !strings.Contains(contentType, "application/xhtml") {
+		return types.IdeaInput{}, ErrUnsupportedContent
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes))
+	if err != nil {
+		return types.IdeaInput{}, fmt.Errorf("failed to read source URL: %w", err)
+	}
+
+	idea := Extract(string(body))
+	if idea.Title == "" || len(idea.OneLiner) < minDescriptionLength {
+		return idea, ErrInsufficientContent
+	}
+
+	return idea, nil
+}
+
+// ParseURL checks that rawURL is an absolute http or https URL
+func ParseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+	return u, nil
+}
+
+// Extract pulls a title and description from an HTML document, preferring
+// Open Graph and meta tags and falling back to the first paragraph
+func Extract(document string) types.IdeaInput {
+	meta := make(map[string]string)
+	for _, tag := range metaTagRe.FindAllString(document, -1) {
+		attrs := make(map[string]string)
+		for _, m := range attrRe.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[3] + m[4]
+		}
+		key := attrs["property"]
+		if key == "" {
+			key = attrs["name"]
+		}
+		if key != "" && attrs["content"] != "" {
+			meta[strings.ToLower(key)] = cleanText(attrs["content"])
+		}
+	}
+
+	title := meta["og:title"]
+	if title == "" {
+		if m := titleRe.FindStringSubmatch(document); m != nil {
+			title = cleanText(m[1])
+		}
+	}
+
+	description := meta["og:description"]
+	if description == "" {
+		description = meta["description"]
+	}
+	if description == "" {
+		for _, m := range paragraphRe.FindAllStringSubmatch(document, -1) {
+			if text := cleanText(m[1]); len(text) >= minDescriptionLength {
+				description = text
+				break
+			}
+		}
+	}
+
+	return types.IdeaInput{
+		Title:    truncate(title, 200),
+		OneLiner: truncate(description, 500),
+	}
+}
+
+// blockedPrefixes are the IANA special-purpose address ranges, plus multicast.
+// None of them serves a public landing page.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("10.0.0.0/8"),      // private
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local
+	netip.MustParsePrefix("172.16.0.0/12"),   // private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("192.31.196.0/24"), // AS112
+	netip.MustParsePrefix("192.52.193.0/24"), // AMT
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private
+	netip.MustParsePrefix("192.175.48.0/24"), // AS112 direct delegation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, and broadcast
+	netip.MustParsePrefix("::/128"),          // unspecified
+	netip.MustParsePrefix("::1/128"),         // loopback
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2001::/23"),       // IETF protocol assignments, incl. Teredo
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("3fff::/20"),       // documentation
+	netip.MustParsePrefix("5f00::/16"),       // segment routing SIDs
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("fec0::/10"),       // site-local, deprecated
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+// isBlockedAddr reports whether addr is in a special-purpose range. IPv4
+// addresses mapped into IPv6 are checked as IPv4.
+func isBlockedAddr(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return true
+	}
+	addr = addr.Unmap().WithZone("")
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanText strips tags, decodes entities and collapses whitespace
+func cleanText(text string) string {
+	text = tagRe.ReplaceAllString(text, " ")
+	text = html.UnescapeString(text)
+	return strings.TrimSpace(spaceRe.ReplaceAllString(text, " "))
+}
+
+// truncate limits text to max bytes without splitting a UTF-8 sequence
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return strings.TrimSpace(text[:cut])
+}
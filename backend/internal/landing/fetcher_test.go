@@ -0,0 +1,147 @@
+package landing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+const landingPage = `<html><head>
+<title>Tutor Match</title>
+<meta property="og:description" content="Matches students with vetted tutors in minutes">
+</head><body></body></html>`
+
+// testFetcher returns a fetcher whose dialer resolves hosts from addrs, and IP
+// literals as themselves, and connects every address it lets through to server
+func testFetcher(server *httptest.Server, addrs map[string]string, maxBytes int64) *Fetcher {
+	dialer := &safeDialer{
+		lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			if addr, err := netip.ParseAddr(host); err == nil {
+				return []netip.Addr{addr}, nil
+			}
+			addr, ok := addrs[host]
+			if !ok {
+				return nil, fmt.Errorf("no such host %s", host)
+			}
+			return []netip.Addr{netip.MustParseAddr(addr)}, nil
+		},
+		dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}
+
+	fetcher := NewFetcher(maxBytes, 5*time.Second, nil)
+	fetcher.httpClient.Transport.(*http.Transport).DialContext = dialer.DialContext
+	return fetcher
+}
+
+func TestFetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, landingPage)
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head><title>Tutor Match</title><!-- "+strings.Repeat("x", 4096)+" -->")
+		fmt.Fprint(w, `<meta name="description" content="Matches students with vetted tutors"></head></html>`)
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"title": "Tutor Match"}`)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	addrs := map[string]string{
+		"landing.test":  "93.184.216.34",
+		"internal.test": "10.1.2.3",
+		"cgnat.test":    "100.64.0.1",
+		"mapped.test":   "::ffff:127.0.0.1",
+		"nat64.test":    "64:ff9b::a9fe:a9fe",
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		maxBytes  int64
+		wantTitle string
+		wantErr   error
+	}{
+		{"public page", "http://landing.test/page", 1 << 20, "Tutor Match", nil},
+		{"private address", "http://internal.test/page", 1 << 20, "", ErrBlockedAddress},
+		{"carrier-grade NAT address", "http://cgnat.test/page", 1 << 20, "", ErrBlockedAddress},
+		{"IPv4-mapped loopback", "http://mapped.test/page", 1 << 20, "", ErrBlockedAddress},
+		{"NAT64 link-local", "http://nat64.test/page", 1 << 20, "", ErrBlockedAddress},
+		{"loopback literal", "http://127.0.0.1/page", 1 << 20, "", ErrBlockedAddress},
+		{"redirect to a private address", "http://landing.test/redirect?to=http://internal.test/page", 1 << 20, "", ErrBlockedAddress},
+		{"redirect to a public page", "http://landing.test/redirect?to=/page", 1 << 20, "Tutor Match", nil},
+		{"description past the size cap", "http://landing.test/large", 1024, "Tutor Match", ErrInsufficientContent},
+		{"description within the size cap", "http://landing.test/large", 1 << 20, "Tutor Match", nil},
+		{"not HTML", "http://landing.test/json", 1 << 20, "", ErrUnsupportedContent},
+		{"not http", "ftp://landing.test/page", 1 << 20, "", ErrInvalidURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idea, err := testFetcher(server, addrs, tt.maxBytes).Fetch(context.Background(), tt.url)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if idea.Title != tt.wantTitle {
+				t.Errorf("got title %q, want %q", idea.Title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestIsBlockedAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", false},
+		{"2606:2800:220:1:248:1893:25c8:1946", false},
+		{"0.0.0.0", true},
+		{"10.0.0.1", true},
+		{"100.64.0.1", true},
+		{"100.127.255.254", true},
+		{"127.0.0.1", true},
+		{"169.254.169.254", true},
+		{"172.16.0.1", true},
+		{"192.0.0.8", true},
+		{"192.168.1.1", true},
+		{"198.18.0.1", true},
+		{"203.0.113.7", true},
+		{"224.0.0.1", true},
+		{"240.0.0.1", true},
+		{"255.255.255.255", true},
+		{"::", true},
+		{"::1", true},
+		{"::ffff:10.0.0.1", true},
+		{"::ffff:169.254.169.254", true},
+		{"64:ff9b::7f00:1", true},
+		{"2001:db8::1", true},
+		{"2002:7f00:1::1", true},
+		{"fd00::1", true},
+		{"fe80::1%eth0", true},
+		{"ff02::1", true},
+	}
+
+	for _, tt := range tests {
+		if got := isBlockedAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -3,63 +3,230 @@ package llm
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"golang.org/x/time/rate"
 
+	"rectaify/internal/egress"
 	"rectaify/pkg/types"
 )
 
+// DefaultBaseURL is the public OpenAI API endpoint
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// DefaultModel is used for analysis, and for search unless a search model is set
+const DefaultModel = "gpt-4o"
+
+// ErrMissingAPIKey is returned by every call of a client built without an API
+// key, before any request is sent
+var ErrMissingAPIKey = errors.New("OpenAI API key is not set (OPENAI_API_KEY)")
+
 // Client wraps OpenAI API with rate limiting and web search
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
-	limiter    *rate.Limiter
+	apiKey      string
+	baseURL     string
+	apiVersion  string // set for Azure OpenAI deployments
+	model       string
+	searchModel string
+	// fallbackModels take over from model, in order, when it is overloaded
+	// or refuses a request
+	fallbackModels []string
+	httpClient     *http.Client
+	limiter        *rate.Limiter
+	tpmLimiter     *rate.Limiter // nil when no tokens-per-minute budget is set
+	maxRetries     int
+	timeout        time.Duration // each attempt of a request; 0 leaves it to the context
+	killSwitch     *KillSwitch   // nil when calls can't be switched off
+}
+
+// ClientConfig holds the settings used to construct a Client
+type ClientConfig struct {
+	APIKey string
+	// BaseURL overrides the API endpoint, e.g. for LLM proxies or
+	// Azure OpenAI (https://{resource}.openai.azure.com/openai/deployments/{deployment})
+	BaseURL string
+	// APIVersion enables Azure OpenAI conventions: the api-version query
+	// parameter and the api-key header instead of a bearer token
+	APIVersion string
+	// Model is used for analysis; SearchModel for the retrieval step and
+	// falls back to Model when empty
+	Model       string
+	SearchModel string
+	// FallbackModels answer analysis requests, in order, when Model is
+	// overloaded (429 or 503) or refuses one
+	FallbackModels []string
+	RPS            int
+	Burst          int
+	// StrictRateLimit disables bursting so requests are spaced evenly at RPS
+	StrictRateLimit bool
+	// TPM is the tokens-per-minute budget; 0 disables token-aware limiting
+	TPM int
+	// MaxRetries is how many times one request is retried after a transient
+	// failure (rate limiting, server or network errors); 0 disables retries
+	MaxRetries int
+	// RequestTimeout bounds each attempt of a request, from sending it to
+	// reading the whole response; 0 leaves attempts to the context deadline.
+	// WithRequestTimeout overrides it for calls that need more or less time.
+	RequestTimeout time.Duration
+	// KillSwitch, when engaged, fails every call with ErrDisabled
+	KillSwitch *KillSwitch
+	// RootCAs adds to the system roots, e.g. behind a TLS-inspecting proxy
+	RootCAs *x509.CertPool
 }
 
 // NewClient creates a new OpenAI client with rate limiting
-func NewClient(apiKey string, rps int, burst int) *Client {
+func NewClient(cfg ClientConfig) *Client {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = DefaultModel
+	}
+	searchModel := cfg.SearchModel
+	if searchModel == "" {
+		searchModel = model
+	}
+
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: "https://api.openai.com/v1",
+		apiKey:         cfg.APIKey,
+		baseURL:        baseURL,
+		apiVersion:     cfg.APIVersion,
+		model:          model,
+		searchModel:    searchModel,
+		fallbackModels: cfg.FallbackModels,
+		// Deadlines come from the request context, per call, so a long
+		// verdict isn't cut off by a limit meant for short calls
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: egress.NewTransport(cfg.RootCAs),
 		},
-		limiter: rate.NewLimiter(rate.Limit(rps), burst),
+		limiter:    newRequestLimiter(cfg.RPS, cfg.Burst, cfg.StrictRateLimit),
+		tpmLimiter: newTokenLimiter(cfg.TPM),
+		maxRetries: max(cfg.MaxRetries, 0),
+		timeout:    max(cfg.RequestTimeout, 0),
+		killSwitch: cfg.KillSwitch,
+	}
+}
+
+// Validate reports whether the client is configured well enough to make calls
+func (c *Client) Validate() error {
+	if strings.TrimSpace(c.apiKey) == "" {
+		return ErrMissingAPIKey
+	}
+	return nil
+}
+
+// newRequestLimiter limits requests per second; strict mode allows no bursts
+func newRequestLimiter(rps, burst int, strict bool) *rate.Limiter {
+	if strict || burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// newTokenLimiter refills a minute's token budget continuously
+func newTokenLimiter(tpm int) *rate.Limiter {
+	if tpm <= 0 {
+		return nil
 	}
+	return rate.NewLimiter(rate.Limit(float64(tpm)/60), tpm)
 }
 
-// SearchRequest represents a web search request
-type SearchRequest struct {
-	Model    string              `json:"model"`
-	Messages []ChatMessage       `json:"messages"`
-	Tools    []Tool              `json:"tools"`
-	ToolChoice string            `json:"tool_choice"`
-	Temperature float64          `json:"temperature"`
+const (
+	// bytesPerToken is a conservative estimate for English text and JSON
+	bytesPerToken = 4
+	// completionTokenAllowance reserves budget for the response, which
+	// counts toward TPM but is unknown before the request
+	completionTokenAllowance = 1000
+)
+
+// estimateTokens approximates the tokens a request will consume
+func estimateTokens(payloadBytes int) int {
+	return payloadBytes/bytesPerToken + completionTokenAllowance
+}
+
+// waitForTokens blocks until the TPM budget can cover the request
+func (c *Client) waitForTokens(ctx context.Context, payloadBytes int) error {
+	if c.tpmLimiter == nil {
+		return nil
+	}
+
+	tokens := estimateTokens(payloadBytes)
+	// A single request larger than the whole budget can still proceed once the budget is full
+	if tokens > c.tpmLimiter.Burst() {
+		tokens = c.tpmLimiter.Burst()
+	}
+	if err := c.tpmLimiter.WaitN(ctx, tokens); err != nil {
+		return fmt.Errorf("token rate limit wait failed: %w", err)
+	}
+	return nil
 }
 
 // ChatMessage represents a chat message
 type ChatMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	Refusal string `json:"refusal,omitempty"` // set instead of content when the model declines
 }
 
-// Tool represents a tool definition
-type Tool struct {
-	Type     string       `json:"type"`
-	Function ToolFunction `json:"function"`
+// WebSearchRequest is a Responses API request using the web search tool
+type WebSearchRequest struct {
+	Model      string          `json:"model"`
+	Input      string          `json:"input"`
+	Tools      []WebSearchTool `json:"tools"`
+	ToolChoice string          `json:"tool_choice"`
+}
+
+// WebSearchTool configures the hosted web search tool
+type WebSearchTool struct {
+	Type         string        `json:"type"`
+	UserLocation *UserLocation `json:"user_location,omitempty"`
+}
+
+// UserLocation biases web search results toward a location
+type UserLocation struct {
+	Type    string `json:"type"` // always "approximate"
+	Country string `json:"country,omitempty"`
+	Region  string `json:"region,omitempty"`
+}
+
+// WebSearchResponse is the subset of a Responses API response carrying the
+// answer text and its URL citations
+type WebSearchResponse struct {
+	Output []ResponseOutputItem `json:"output"`
 }
 
-// ToolFunction represents a function tool
-type ToolFunction struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Parameters  interface{} `json:"parameters,omitempty"`
+// ResponseOutputItem is one output item; only "message" items hold citations
+type ResponseOutputItem struct {
+	Type    string                `json:"type"`
+	Content []ResponseContentPart `json:"content,omitempty"`
+}
+
+// ResponseContentPart is a piece of message content
+type ResponseContentPart struct {
+	Type        string               `json:"type"`
+	Text        string               `json:"text"`
+	Annotations []ResponseAnnotation `json:"annotations,omitempty"`
+}
+
+// ResponseAnnotation marks a span of the text as citing a URL
+type ResponseAnnotation struct {
+	Type       string `json:"type"`
+	URL        string `json:"url"`
+	Title      string `json:"title"`
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
 }
 
 // SearchResponse represents the OpenAI response
@@ -146,6 +313,41 @@ func (c *Client) ConstrainedJSON(ctx context.Context, systemPrompt string, userP
 		return nil, fmt.Errorf("rate limit wait failed: %w", err)
 	}
 
+	request, err := c.constrainedRequest(systemPrompt, userPrompt, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// A refusal is checked per attempt so the next model in the chain can answer
+	var response []byte
+	err = c.withRetries(ctx, request, func(jsonPayload []byte) (err error) {
+		response, err = c.send(ctx, "/chat/completions", jsonPayload)
+		if refusal := refusalOf(response); err == nil && refusal != "" {
+			return fmt.Errorf("%w: %s", ErrRefused, refusal)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var chatResponse SearchResponse
+	if err := json.Unmarshal(response, &chatResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(chatResponse.Choices) == 0 {
+		return nil, fmt.Errorf("no response choices returned")
+	}
+
+	content := json.RawMessage(chatResponse.Choices[0].Message.Content)
+	recordResponse(ctx, content)
+	return content, nil
+}
+
+// constrainedRequest builds the chat completion request of a constrained JSON
+// generation
+func (c *Client) constrainedRequest(systemPrompt string, userPrompt interface{}, schema []byte) (map[string]interface{}, error) {
 	// Convert user prompt to string if needed
 	var userString string
 	switch v := userPrompt.(type) {
@@ -165,8 +367,8 @@ func (c *Client) ConstrainedJSON(ctx context.Context, systemPrompt string, userP
 		return nil, fmt.Errorf("failed to parse schema: %w", err)
 	}
 
-	request := map[string]interface{}{
-		"model": "gpt-4o",
+	return map[string]interface{}{
+		"model": c.model,
 		"messages": []ChatMessage{
 			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: userString},
@@ -180,78 +382,79 @@ func (c *Client) ConstrainedJSON(ctx context.Context, systemPrompt string, userP
 				"schema": schemaObj,
 			},
 		},
-	}
-
-	response, err := c.makeRequest(ctx, "/chat/completions", request)
-	if err != nil {
-		return nil, err
-	}
-
-	var chatResponse SearchResponse
-	if err := json.Unmarshal(response, &chatResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if len(chatResponse.Choices) == 0 {
-		return nil, fmt.Errorf("no response choices returned")
-	}
-
-	return json.RawMessage(chatResponse.Choices[0].Message.Content), nil
+	}, nil
 }
 
-// performWebSearch executes a web search query
+// performWebSearch executes a web search query through the Responses API's
+// hosted web search tool and turns the answer's URL citations into results
 func (c *Client) performWebSearch(ctx context.Context, query string, location *types.ApproxLocation) ([]WebSearchResult, error) {
-	locationStr := ""
+	tool := WebSearchTool{Type: "web_search_preview"}
 	if location != nil && location.Country != "" {
-		locationStr = fmt.Sprintf(" in %s", location.Country)
-		if location.Region != "" {
-			locationStr = fmt.Sprintf(" in %s, %s", location.Region, location.Country)
+		tool.UserLocation = &UserLocation{
+			Type:    "approximate",
+			Country: location.Country,
+			Region:  location.Region,
 		}
 	}
 
-	searchQuery := query + locationStr
-
-	request := SearchRequest{
-		Model: "gpt-4o",
-		Messages: []ChatMessage{
-			{
-				Role:    "user",
-				Content: fmt.Sprintf("Search for information about: %s", searchQuery),
-			},
-		},
-		Tools: []Tool{
-			{
-				Type: "web_search",
-				Function: ToolFunction{
-					Name:        "web_search",
-					Description: "Search the web for current information",
-				},
-			},
-		},
-		ToolChoice:  "required",
-		Temperature: 0.2,
+	request := WebSearchRequest{
+		Model:      c.searchModel,
+		Input:      fmt.Sprintf("Search the web for information about: %s. Cite every source you use.", query),
+		Tools:      []WebSearchTool{tool},
+		ToolChoice: "required",
 	}
 
-	response, err := c.makeRequest(ctx, "/chat/completions", request)
+	response, err := c.makeRequest(ctx, "/responses", request)
 	if err != nil {
 		return nil, err
 	}
 
-	var searchResponse SearchResponse
-	if err := json.Unmarshal(response, &searchResponse); err != nil {
+	return parseWebSearchResponse(response)
+}
+
+// maxResultContentBytes stops merging citations into a search result once its
+// content reaches this size; evidence snippets keep far less
+const maxResultContentBytes = 2000
+
+// parseWebSearchResponse extracts one result per cited URL. The snippet is
+// the sentence of the answer that carries the citation.
+func parseWebSearchResponse(body []byte) ([]WebSearchResult, error) {
+	var searchResponse WebSearchResponse
+	if err := json.Unmarshal(body, &searchResponse); err != nil {
 		return nil, fmt.Errorf("failed to parse search response: %w", err)
 	}
 
-	// Extract web search results from tool calls
 	var results []WebSearchResult
-	for _, choice := range searchResponse.Choices {
-		for _, toolCall := range choice.ToolCalls {
-			if toolCall.Function.Name == "web_search" {
-				var searchResults []WebSearchResult
-				if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &searchResults); err != nil {
-					continue // Skip malformed results
+	seen := make(map[string]int)
+	for _, item := range searchResponse.Output {
+		if item.Type != "message" {
+			continue
+		}
+		for _, part := range item.Content {
+			for _, annotation := range part.Annotations {
+				if annotation.Type != "url_citation" || annotation.URL == "" {
+					continue
+				}
+
+				snippet := citedSentence(part.Text, annotation.StartIndex)
+				if i, ok := seen[annotation.URL]; ok {
+					// Merge further statements citing the same source, up to a bound
+					if snippet != "" && len(results[i].Content) < maxResultContentBytes && !strings.Contains(results[i].Content, snippet) {
+						results[i].Content = strings.TrimSpace(results[i].Content + " " + snippet)
+					}
+					continue
 				}
-				results = append(results, searchResults...)
+
+				title := annotation.Title
+				if title == "" {
+					title = annotation.URL
+				}
+				seen[annotation.URL] = len(results)
+				results = append(results, WebSearchResult{
+					URL:     annotation.URL,
+					Title:   title,
+					Content: snippet,
+				})
 			}
 		}
 	}
@@ -259,35 +462,153 @@ func (c *Client) performWebSearch(ctx context.Context, query string, location *t
 	return results, nil
 }
 
-// makeRequest performs an HTTP request to the OpenAI API
+// citedSentence returns the sentence of text that precedes the citation
+// starting at rune index start, since citations follow the statement they support
+func citedSentence(text string, start int) string {
+	runes := []rune(text)
+	if start <= 0 || start > len(runes) {
+		return ""
+	}
+
+	// Walk back past the previous sentence end or line break
+	begin := start - 1
+	for begin > 0 {
+		prev := runes[begin-1]
+		if prev == '\n' || ((prev == '.' || prev == '!' || prev == '?') && runes[begin] == ' ' && begin < start-1) {
+			break
+		}
+		begin--
+	}
+
+	sentence := strings.TrimSpace(string(runes[begin:start]))
+	sentence = strings.TrimRight(sentence, " ([")
+	return strings.TrimSpace(strings.TrimLeft(sentence, "-*# "))
+}
+
+// makeRequest performs an HTTP request to the OpenAI API, retrying transient
+// failures with backoff up to the client's per-request limit and the
+// context's retry budget
 func (c *Client) makeRequest(ctx context.Context, endpoint string, payload interface{}) ([]byte, error) {
+	var responseBody []byte
+	err := c.withRetries(ctx, payload, func(jsonPayload []byte) (err error) {
+		responseBody, err = c.send(ctx, endpoint, jsonPayload)
+		return err
+	})
+	return responseBody, err
+}
+
+// withRetries marshals payload and makes attempts at sending it until one
+// succeeds or a failure may not be retried. An overloaded or refusing model
+// hands the request to the next model of its fallback chain straight away;
+// the hop spends from the retry budget like a retry does, but not from the
+// per-request retries, so a request makes at most MaxRetries+1 attempts plus
+// one per fallback model.
+func (c *Client) withRetries(ctx context.Context, payload interface{}, attempt func(jsonPayload []byte) error) error {
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// Fail clearly rather than with the API's 401
+	if err := c.Validate(); err != nil {
+		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	chain := c.modelChain(payloadModel(jsonPayload))
+	current := 0
+	for n := 0; ; {
+		// Checked before every attempt so engaging the switch also stops retries
+		if c.killSwitch.Engaged() {
+			return ErrDisabled
+		}
 
-	resp, err := c.httpClient.Do(req)
+		err := attempt(jsonPayload)
+		if err == nil {
+			recordModel(ctx, chain[current])
+			return nil
+		}
+
+		if current+1 < len(chain) && shouldFallBack(ctx, err) {
+			if !takeRetry(ctx) {
+				return errors.Join(err, ErrRetryBudgetExhausted)
+			}
+			log.Printf("LLM model %s failed, falling back to %s: %v", chain[current], chain[current+1], err)
+			current++
+			if jsonPayload, err = withModel(jsonPayload, chain[current]); err != nil {
+				return err
+			}
+		} else {
+			retry, err := c.allowRetry(ctx, n, err)
+			if !retry {
+				return err
+			}
+			if err := retryBackoff(ctx, n); err != nil {
+				return err
+			}
+			n++
+		}
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// send makes a single attempt at a request, within its request timeout
+func (c *Client) send(ctx context.Context, endpoint string, jsonPayload []byte) ([]byte, error) {
+	if err := c.waitForTokens(ctx, len(jsonPayload)); err != nil {
+		return nil, err
+	}
+
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+
+	resp, err := c.post(ctx, attemptCtx, endpoint, jsonPayload)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", c.attemptError(ctx, attemptCtx, err))
+	}
+
+	return responseBody, nil
+}
+
+// post sends a request under attemptCtx and returns the response once its
+// status is known to be 200; the caller reads and closes the body
+func (c *Client) post(ctx, attemptCtx context.Context, endpoint string, jsonPayload []byte) (*http.Response, error) {
+	requestURL := c.baseURL + endpoint
+	if c.apiVersion != "" {
+		requestURL += "?api-version=" + url.QueryEscape(c.apiVersion)
+	}
+
+	req, err := http.NewRequestWithContext(attemptCtx, "POST", requestURL, bytes.NewReader(jsonPayload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if c.apiVersion != "" {
+		req.Header.Set("api-key", c.apiKey)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", c.attemptError(ctx, attemptCtx, err))
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(responseBody))
+		defer resp.Body.Close()
+		responseBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", c.attemptError(ctx, attemptCtx, err))
+		}
+		return nil, &statusError{status: resp.StatusCode, body: string(responseBody)}
 	}
 
-	return responseBody, nil
+	return resp, nil
 }
@@ -0,0 +1,167 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// webSearchFixture is a recorded Responses API answer to a web search: a
+// web_search_call item, then a message whose text cites three sources, one of
+// them twice and one without a title
+const webSearchFixture = "testdata/web_search_response.json"
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	return body
+}
+
+func TestParseWebSearchResponse(t *testing.T) {
+	results, err := parseWebSearchResponse(readFixture(t, webSearchFixture))
+	if err != nil {
+		t.Fatalf("parseWebSearchResponse: %v", err)
+	}
+
+	want := []WebSearchResult{
+		{
+			URL:     "https://www.grandviewresearch.com/industry-analysis/online-tutoring-services-market",
+			Title:   "Online Tutoring Services Market Size Report, 2030",
+			Content: "The online tutoring market reached $7.7 billion in 2023.",
+		},
+		{
+			URL:   "https://www.edweek.org/technology/tutoring-demand",
+			Title: "Why Tutoring Demand Keeps Growing",
+			Content: "Demand is driven by K-12 test preparation and adult upskilling. " +
+				"Churn stays high because families cancel after exam season.",
+		},
+		{
+			// No title in the citation, so the URL stands in
+			URL:     "https://www.marketwatch.com/press-release/tutoring-forecast",
+			Title:   "https://www.marketwatch.com/press-release/tutoring-forecast",
+			Content: "Analysts expect 14% annual growth through 2030.",
+		},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d:\n got %+v\nwant %+v", i, results[i], want[i])
+		}
+	}
+}
+
+func TestParseWebSearchResponseMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			name:    "invalid JSON",
+			body:    `{"output": [`,
+			wantErr: true,
+		},
+		{
+			name:    "output of the wrong type",
+			body:    `{"output": {"type": "message"}}`,
+			wantErr: true,
+		},
+		{
+			// A Chat Completions body whose tool call arguments aren't results;
+			// it carries no Responses API output, so nothing is cited
+			name: "chat completion with malformed tool_calls",
+			body: `{"id": "chatcmpl-1", "object": "chat.completion", "choices": [{"index": 0,
+				"message": {"role": "assistant", "content": null, "tool_calls": [{"id": "call_1",
+				"type": "function", "function": {"name": "web_search", "arguments": "{\"query\": "}}]},
+				"finish_reason": "tool_calls"}]}`,
+		},
+		{
+			name: "function call output item with malformed arguments",
+			body: `{"output": [{"type": "function_call", "call_id": "call_1", "name": "web_search",
+				"arguments": "{not json"}]}`,
+		},
+		{
+			name: "search call without a message",
+			body: `{"output": [{"type": "web_search_call", "id": "ws_1", "status": "failed"}]}`,
+		},
+		{
+			name: "citation without a URL",
+			body: `{"output": [{"type": "message", "content": [{"type": "output_text", "text": "Claim.",
+				"annotations": [{"type": "url_citation", "start_index": 6, "end_index": 6, "title": "Orphan"}]}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := parseWebSearchResponse([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if len(results) != 0 {
+				t.Errorf("got %d results from a malformed response, want none: %+v", len(results), results)
+			}
+		})
+	}
+}
+
+func TestParseWebSearchResponseIndexOutOfRange(t *testing.T) {
+	body := `{"output": [{"type": "message", "content": [{"type": "output_text", "text": "Short.",
+		"annotations": [
+			{"type": "url_citation", "start_index": 400, "end_index": 410, "url": "https://example.com/a", "title": "A"},
+			{"type": "url_citation", "start_index": -3, "end_index": 0, "url": "https://example.com/b", "title": "B"}
+		]}]}]}`
+
+	results, err := parseWebSearchResponse([]byte(body))
+	if err != nil {
+		t.Fatalf("parseWebSearchResponse: %v", err)
+	}
+	// The sources are still cited, only without a sentence to quote
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	for _, result := range results {
+		if result.Content != "" {
+			t.Errorf("%s: got content %q for an out-of-range citation, want none", result.URL, result.Content)
+		}
+	}
+}
+
+func TestPerformWebSearch(t *testing.T) {
+	fixture := readFixture(t, webSearchFixture)
+	var request WebSearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/responses" {
+			t.Errorf("request sent to %s, want /responses", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{APIKey: "test", BaseURL: server.URL, SearchModel: "search-model", RPS: 100})
+	results, err := client.performWebSearch(context.Background(), "online tutoring market", nil)
+	if err != nil {
+		t.Fatalf("performWebSearch: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("got %d results, want 3", len(results))
+	}
+
+	if request.Model != "search-model" {
+		t.Errorf("searched with model %q, want the search model", request.Model)
+	}
+	if len(request.Tools) != 1 || request.Tools[0].Type != "web_search_preview" || request.Tools[0].UserLocation != nil {
+		t.Errorf("got tools %+v, want one web_search_preview tool without a location", request.Tools)
+	}
+}
@@ -0,0 +1,121 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ErrRefused marks a response in which the model declined to answer
+var ErrRefused = errors.New("model refused the request")
+
+// modelChain returns the models a request for model is tried with, in order.
+// Only the analysis model has fallbacks; the search model must support the
+// web search tool, which fallbacks might not.
+func (c *Client) modelChain(model string) []string {
+	if model != c.model {
+		return []string{model}
+	}
+	return append([]string{c.model}, c.fallbackModels...)
+}
+
+// shouldFallBack reports whether a failed attempt should be handed to the
+// next model of its chain: the model is overloaded or refused to answer.
+// Failures partway through a streamed response stay with their model.
+func shouldFallBack(ctx context.Context, err error) bool {
+	var streamed *errStreamed
+	if ctx.Err() != nil || errors.As(err, &streamed) {
+		return false
+	}
+	if errors.Is(err, ErrRefused) {
+		return true
+	}
+	var statusErr *statusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.status == http.StatusTooManyRequests || statusErr.status == http.StatusServiceUnavailable)
+}
+
+// payloadModel returns the model a marshalled request is for
+func payloadModel(jsonPayload []byte) string {
+	var request struct {
+		Model string `json:"model"`
+	}
+	json.Unmarshal(jsonPayload, &request)
+	return request.Model
+}
+
+// withModel returns a marshalled request with its model replaced
+func withModel(jsonPayload []byte, model string) ([]byte, error) {
+	var request map[string]json.RawMessage
+	if err := json.Unmarshal(jsonPayload, &request); err != nil {
+		return nil, fmt.Errorf("failed to switch request model: %w", err)
+	}
+	request["model"], _ = json.Marshal(model)
+	return json.Marshal(request)
+}
+
+// refusalOf returns the refusal of a chat completion response, if the model
+// declined to answer
+func refusalOf(responseBody []byte) string {
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Refusal string `json:"refusal"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if json.Unmarshal(responseBody, &response) != nil || len(response.Choices) == 0 {
+		return ""
+	}
+	return response.Choices[0].Message.Refusal
+}
+
+// ModelLog records which model answered the LLM calls made under a context,
+// by the label the call was made under
+type ModelLog struct {
+	mu     sync.Mutex
+	models map[string]string
+}
+
+type modelLogKey struct{}
+type modelLabelKey struct{}
+
+// WithModelLog returns a context whose labeled LLM calls record the model
+// that answered them in the returned log
+func WithModelLog(ctx context.Context) (context.Context, *ModelLog) {
+	log := &ModelLog{models: make(map[string]string)}
+	return context.WithValue(ctx, modelLogKey{}, log), log
+}
+
+// WithModelLabel returns a context whose LLM calls are recorded under label,
+// e.g. the dimension they analyze
+func WithModelLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, modelLabelKey{}, label)
+}
+
+// Models returns the model that answered each label's last call
+func (l *ModelLog) Models() map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	models := make(map[string]string, len(l.models))
+	for label, model := range l.models {
+		models[label] = model
+	}
+	return models
+}
+
+// recordModel notes that model answered a call made under ctx
+func recordModel(ctx context.Context, model string) {
+	log, ok := ctx.Value(modelLogKey{}).(*ModelLog)
+	label, labeled := ctx.Value(modelLabelKey{}).(string)
+	if !ok || !labeled {
+		return
+	}
+	log.mu.Lock()
+	log.models[label] = model
+	log.mu.Unlock()
+}
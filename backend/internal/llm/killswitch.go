@@ -0,0 +1,33 @@
+package llm
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrDisabled is returned by every call made while the kill switch is engaged
+var ErrDisabled = errors.New("LLM calls are disabled for maintenance")
+
+// KillSwitch stops all LLM calls at once, so operators can halt spend during
+// an incident or cost overrun without taking the API down. A nil switch is
+// never engaged.
+type KillSwitch struct {
+	engaged atomic.Bool
+}
+
+// NewKillSwitch creates a kill switch in the given state
+func NewKillSwitch(engaged bool) *KillSwitch {
+	k := &KillSwitch{}
+	k.engaged.Store(engaged)
+	return k
+}
+
+// Set engages or releases the switch
+func (k *KillSwitch) Set(engaged bool) {
+	k.engaged.Store(engaged)
+}
+
+// Engaged reports whether LLM calls are switched off
+func (k *KillSwitch) Engaged() bool {
+	return k != nil && k.engaged.Load()
+}
@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// ResponseLog keeps the raw output of the constrained JSON calls made under
+// a context, by the label the call was made under, for debugging analyses
+type ResponseLog struct {
+	mu        sync.Mutex
+	responses map[string]json.RawMessage
+}
+
+type responseLogKey struct{}
+
+// WithResponseLog returns a context whose labeled constrained JSON calls keep
+// their raw output in the returned log
+func WithResponseLog(ctx context.Context) (context.Context, *ResponseLog) {
+	log := &ResponseLog{responses: make(map[string]json.RawMessage)}
+	return context.WithValue(ctx, responseLogKey{}, log), log
+}
+
+// Responses returns the raw output of each label's last call
+func (l *ResponseLog) Responses() map[string]json.RawMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	responses := make(map[string]json.RawMessage, len(l.responses))
+	for label, response := range l.responses {
+		responses[label] = response
+	}
+	return responses
+}
+
+// recordResponse keeps the raw output of a call made under ctx, if a response
+// log is attached
+func recordResponse(ctx context.Context, response json.RawMessage) {
+	log, ok := ctx.Value(responseLogKey{}).(*ResponseLog)
+	label, labeled := ctx.Value(modelLabelKey{}).(string)
+	if !ok || !labeled {
+		return
+	}
+	log.mu.Lock()
+	log.responses[label] = response
+	log.mu.Unlock()
+}
@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ErrRetryBudgetExhausted marks a failure that was not retried because the
+// analysis had used up its retry budget
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// Backoff between retries of one request
+const (
+	retryInitialBackoff = 500 * time.Millisecond
+	retryMaxBackoff     = 8 * time.Second
+)
+
+// RetryBudget caps the retries of every LLM call made under one context, so
+// during a provider brownout an analysis fails fast instead of retrying each
+// of its calls to the limit
+type RetryBudget struct {
+	limit int
+	used  atomic.Int64
+}
+
+// RetryBudgetStats reports how much of a retry budget was used
+type RetryBudgetStats struct {
+	Budget    int `json:"budget"`
+	Used      int `json:"used"`
+	Remaining int `json:"remaining"`
+}
+
+type retryBudgetKey struct{}
+
+// WithRetryBudget returns a context whose LLM calls share a budget of limit
+// retries in total
+func WithRetryBudget(ctx context.Context, limit int) (context.Context, *RetryBudget) {
+	budget := &RetryBudget{limit: limit}
+	return context.WithValue(ctx, retryBudgetKey{}, budget), budget
+}
+
+// take spends one retry, reporting false when none is left
+func (b *RetryBudget) take() bool {
+	if b.used.Add(1) > int64(b.limit) {
+		b.used.Add(-1)
+		return false
+	}
+	return true
+}
+
+// Stats reports the budget and how much of it was spent
+func (b *RetryBudget) Stats() RetryBudgetStats {
+	used := int(b.used.Load())
+	return RetryBudgetStats{Budget: b.limit, Used: used, Remaining: b.limit - used}
+}
+
+// statusError is a non-200 response from the API
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.status, e.body)
+}
+
+// retryable reports whether a failed request may succeed if sent again:
+// transport errors, rate limiting and server errors. Cancellation is final,
+// as are a refusal and a failure partway through a streamed response.
+func retryable(ctx context.Context, err error) bool {
+	var streamed *errStreamed
+	if ctx.Err() != nil || errors.As(err, &streamed) || errors.Is(err, ErrRefused) {
+		return false
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status == http.StatusTooManyRequests || statusErr.status >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// allowRetry decides whether attempt (counting from 0) of a failed request may
+// be retried, spending from the context's retry budget if it has one
+func (c *Client) allowRetry(ctx context.Context, attempt int, err error) (bool, error) {
+	if attempt >= c.maxRetries || !retryable(ctx, err) {
+		return false, err
+	}
+	if !takeRetry(ctx) {
+		return false, errors.Join(err, ErrRetryBudgetExhausted)
+	}
+	return true, err
+}
+
+// takeRetry spends one retry from the context's retry budget, reporting
+// false when none is left; a context without a budget always allows it
+func takeRetry(ctx context.Context) bool {
+	budget, ok := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return !ok || budget.take()
+}
+
+// retryBackoff waits before retry attempt+1, doubling the delay each time
+func retryBackoff(ctx context.Context, attempt int) error {
+	delay := retryInitialBackoff << attempt
+	if delay > retryMaxBackoff {
+		delay = retryMaxBackoff
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
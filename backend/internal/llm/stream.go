@@ -0,0 +1,122 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// streamChunk is one server-sent event of a streamed chat completion
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// errStreamed marks a failure after part of a response was delivered, which
+// must not be retried or the receiver would see the response twice
+type errStreamed struct{ err error }
+
+func (e *errStreamed) Error() string { return e.err.Error() }
+func (e *errStreamed) Unwrap() error { return e.err }
+
+// ConstrainedJSONStream performs a constrained JSON generation request with
+// the response streamed: onDelta receives each piece of the JSON content as
+// it is generated, and the whole content is returned at the end. Failed
+// attempts are retried like ConstrainedJSON's only until the first piece
+// arrives, so onDelta never sees part of a response twice.
+func (c *Client) ConstrainedJSONStream(ctx context.Context, systemPrompt string, userPrompt interface{}, schema []byte, onDelta func(string)) (json.RawMessage, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit wait failed: %w", err)
+	}
+
+	request, err := c.constrainedRequest(systemPrompt, userPrompt, schema)
+	if err != nil {
+		return nil, err
+	}
+	request["stream"] = true
+
+	var content string
+	err = c.withRetries(ctx, request, func(jsonPayload []byte) (err error) {
+		content, err = c.sendStream(ctx, "/chat/completions", jsonPayload, onDelta)
+		return err
+	})
+	var streamed *errStreamed
+	if errors.As(err, &streamed) {
+		return nil, streamed.err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, fmt.Errorf("no response content streamed")
+	}
+
+	return json.RawMessage(content), nil
+}
+
+// sendStream makes a single attempt at a streamed request, within its
+// request timeout, passing content to onDelta as it arrives
+func (c *Client) sendStream(ctx context.Context, endpoint string, jsonPayload []byte, onDelta func(string)) (string, error) {
+	if err := c.waitForTokens(ctx, len(jsonPayload)); err != nil {
+		return "", err
+	}
+
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+
+	resp, err := c.post(ctx, attemptCtx, endpoint, jsonPayload)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var content strings.Builder
+	fail := func(err error) (string, error) {
+		if content.Len() > 0 {
+			err = &errStreamed{err}
+		}
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			return content.String(), nil
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fail(fmt.Errorf("failed to parse stream event: %w", err))
+		}
+		if chunk.Error != nil {
+			return fail(fmt.Errorf("stream failed: %s", chunk.Error.Message))
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			onDelta(choice.Delta.Content)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fail(fmt.Errorf("failed to read stream: %w", c.attemptError(ctx, attemptCtx, err)))
+	}
+
+	return fail(fmt.Errorf("stream ended before completion"))
+}
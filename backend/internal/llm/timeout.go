@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRequestTimeout marks an attempt that got no complete response within its
+// request timeout. Unlike the caller's own deadline passing, it is retried.
+var ErrRequestTimeout = errors.New("LLM request timed out")
+
+type requestTimeoutKey struct{}
+
+// WithRequestTimeout returns a context whose LLM calls give each attempt
+// timeout to respond in, instead of the client's request timeout. A timeout
+// of 0 bounds attempts by the context's own deadline only.
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout)
+}
+
+// requestTimeout returns the time one attempt of a call under ctx may take
+func (c *Client) requestTimeout(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(requestTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return c.timeout
+}
+
+// attemptContext bounds one attempt by its request timeout, on top of any
+// deadline ctx already has
+func (c *Client) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.requestTimeout(ctx)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// attemptError reports an attempt cut off by its request timeout, while the
+// caller's context is still live, as ErrRequestTimeout
+func (c *Client) attemptError(ctx, attemptCtx context.Context, err error) error {
+	if ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", ErrRequestTimeout, c.requestTimeout(ctx))
+	}
+	return err
+}
@@ -0,0 +1,277 @@
+package money
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"rectaify/pkg/types"
+)
+
+// Rates are US dollars per unit of each currency, keyed by ISO 4217 code
+type Rates map[string]float64
+
+// DefaultRates returns approximate exchange rates used for currencies the
+// configuration does not set. They are only meant for comparing orders of
+// magnitude, not for accounting.
+func DefaultRates() Rates {
+	return Rates{
+		"USD": 1,
+		"EUR": 1.08,
+		"GBP": 1.27,
+		"CHF": 1.13,
+		"CAD": 0.73,
+		"AUD": 0.66,
+		"SGD": 0.74,
+		"HKD": 0.128,
+		"JPY": 0.0067,
+		"CNY": 0.14,
+		"KRW": 0.00073,
+		"INR": 0.012,
+		"BRL": 0.18,
+		"MXN": 0.055,
+		"SEK": 0.095,
+		"ILS": 0.27,
+		"ZAR": 0.054,
+		"NGN": 0.00065,
+	}
+}
+
+// Merge returns a copy of the rates with overrides applied
+func (r Rates) Merge(overrides map[string]float64) Rates {
+	merged := make(Rates, len(r)+len(overrides))
+	for currency, rate := range r {
+		merged[currency] = rate
+	}
+	for currency, rate := range overrides {
+		merged[currency] = rate
+	}
+	return merged
+}
+
+// currencySymbols maps symbols to currencies, longest first so "US$" and "C$"
+// win over "$"
+var currencySymbols = []struct {
+	symbol   string
+	currency string
+}{
+	{"us$", "USD"}, {"ca$", "CAD"}, {"au$", "AUD"}, {"hk$", "HKD"},
+	{"c$", "CAD"}, {"a$", "AUD"}, {"s$", "SGD"}, {"r$", "BRL"},
+	{"$", "USD"}, {"£", "GBP"}, {"€", "EUR"}, {"¥", "JPY"}, {"₹", "INR"},
+	{"₩", "KRW"}, {"₪", "ILS"}, {"₦", "NGN"},
+}
+
+// currencyWords maps currency codes and names, matched as whole words
+var currencyWords = map[string]string{
+	"usd": "USD", "dollar": "USD", "dollars": "USD",
+	"eur": "EUR", "euro": "EUR", "euros": "EUR",
+	"gbp": "GBP", "pound": "GBP", "pounds": "GBP", "sterling": "GBP",
+	"chf": "CHF", "cad": "CAD", "aud": "AUD", "sgd": "SGD", "hkd": "HKD",
+	"jpy": "JPY", "yen": "JPY",
+	"cny": "CNY", "rmb": "CNY", "yuan": "CNY",
+	"krw": "KRW",
+	"inr": "INR", "rs": "INR", "rupee": "INR", "rupees": "INR",
+	"brl": "BRL", "reais": "BRL",
+	"mxn": "MXN", "sek": "SEK", "ils": "ILS", "shekels": "ILS",
+	"zar": "ZAR", "ngn": "NGN", "naira": "NGN",
+}
+
+// multipliers scale a number by the word or suffix that follows it
+var multipliers = map[string]float64{
+	"k": 1e3, "thousand": 1e3,
+	"m": 1e6, "mm": 1e6, "mn": 1e6, "mil": 1e6, "mio": 1e6, "million": 1e6, "millions": 1e6,
+	"b": 1e9, "bn": 1e9, "mrd": 1e9, "billion": 1e9, "billions": 1e9,
+	"t": 1e12, "tn": 1e12, "trillion": 1e12,
+	"lakh": 1e5, "lakhs": 1e5, "lac": 1e5, "lacs": 1e5,
+	"cr": 1e7, "crore": 1e7, "crores": 1e7,
+}
+
+// indianMultipliers imply rupees when no currency is stated
+var indianMultipliers = map[string]bool{
+	"lakh": true, "lakhs": true, "lac": true, "lacs": true,
+	"cr": true, "crore": true, "crores": true,
+}
+
+var (
+	// amountRe matches a number, grouped ("1,500,000", "1.234.567,89",
+	// "1 000 000") or plain ("2.5", "2,5"), and an optional multiplier
+	amountRe = regexp.MustCompile(`(\d{1,3}(?:[.,' \x{00a0}]\d{3})+(?:[.,]\d+)?|\d+(?:[.,]\d+)?)\s*(thousand|millions?|billions?|trillion|lakhs?|lacs?|crores?|mio|mrd|mil|mm|mn|bn|tn|cr|k|m|b|t)?\b`)
+	// rangeRe matches what separates the two ends of a range
+	rangeRe = regexp.MustCompile(`^\s*(?:-|–|—|to|and)\s*\S{0,3}\s*$`)
+	wordRe  = regexp.MustCompile(`[a-z]+`)
+)
+
+// number is one figure found in the text
+type number struct {
+	value      float64
+	multiplier string
+	start, end int
+}
+
+// Parse normalizes a monetary string such as "£2M", "¥500M", "2 crore",
+// "€1.234.567,89" or "$2-5M" to US dollars using rates. Values that can't be
+// read, or whose currency has no rate, come back with Parsed false.
+func Parse(text string, rates Rates) types.MoneyAmount {
+	amount := types.MoneyAmount{Original: text}
+
+	lower := strings.ToLower(text)
+	numbers := findNumbers(lower)
+	i := amountIndex(lower, numbers)
+	if i < 0 {
+		return amount
+	}
+
+	low, high := numbers[i], numbers[i]
+	switch {
+	case i+1 < len(numbers) && rangeRe.MatchString(lower[numbers[i].end:numbers[i+1].start]):
+		high = numbers[i+1]
+	case i > 0 && rangeRe.MatchString(lower[numbers[i-1].end:numbers[i].start]):
+		low = numbers[i-1]
+	}
+	// "$2-5M" and "2 to 5 million" share the multiplier of the upper end
+	if low.multiplier == "" {
+		low.multiplier = high.multiplier
+	}
+
+	currency := detectCurrency(lower)
+	if currency == "" {
+		if indianMultipliers[high.multiplier] {
+			currency = "INR"
+		} else {
+			currency = "USD"
+			amount.CurrencyAssumed = true
+		}
+	}
+	amount.Currency = currency
+
+	rate, ok := rates[currency]
+	if !ok || rate <= 0 {
+		return amount
+	}
+
+	amount.USDMin = scale(low) * rate
+	amount.USDMax = scale(high) * rate
+	amount.USD = (amount.USDMin + amount.USDMax) / 2
+	amount.Parsed = true
+	return amount
+}
+
+// findNumbers returns every figure in the text, in order
+func findNumbers(text string) []number {
+	var numbers []number
+	for _, match := range amountRe.FindAllStringSubmatchIndex(text, -1) {
+		n := number{start: match[0], end: match[1]}
+		if match[4] >= 0 {
+			n.multiplier = text[match[4]:match[5]]
+		}
+		value, ok := parseNumber(text[match[2]:match[3]], n.multiplier != "")
+		if !ok {
+			continue
+		}
+		n.value = value
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// amountIndex picks the figure that states the amount: the first with a
+// multiplier or a currency right before it, so years and counts elsewhere in
+// the text are skipped. A lone figure is used as is.
+func amountIndex(text string, numbers []number) int {
+	for i, n := range numbers {
+		if n.multiplier != "" || hasCurrencyBefore(text[:n.start]) {
+			return i
+		}
+	}
+	if len(numbers) == 1 && !isYear(numbers[0]) {
+		return 0
+	}
+	return -1
+}
+
+// hasCurrencyBefore reports whether the text ends in a currency symbol or code
+func hasCurrencyBefore(text string) bool {
+	text = strings.TrimSpace(text)
+	for _, s := range currencySymbols {
+		if strings.HasSuffix(text, s.symbol) {
+			return true
+		}
+	}
+	words := wordRe.FindAllString(text, -1)
+	return len(words) > 0 && strings.HasSuffix(text, words[len(words)-1]) && currencyWords[words[len(words)-1]] != ""
+}
+
+// isYear reports whether a bare figure looks like a year rather than an amount
+func isYear(n number) bool {
+	return n.multiplier == "" && n.value >= 1900 && n.value <= 2100 && n.value == float64(int(n.value))
+}
+
+// detectCurrency returns the ISO code of the first currency named in the text
+func detectCurrency(text string) string {
+	// Prefixed dollar symbols are the most specific, then codes and names,
+	// then bare symbols
+	for _, s := range currencySymbols[:8] {
+		if strings.Contains(text, s.symbol) {
+			return s.currency
+		}
+	}
+	for _, word := range wordRe.FindAllString(text, -1) {
+		if currency, ok := currencyWords[word]; ok {
+			return currency
+		}
+	}
+	for _, s := range currencySymbols[8:] {
+		if strings.Contains(text, s.symbol) {
+			return s.currency
+		}
+	}
+	return ""
+}
+
+// parseNumber reads a figure written with either "." or "," as the decimal
+// separator. When both appear, the later one is the decimal separator. A lone
+// separator followed by exactly three digits groups thousands ("1,500",
+// "2.000"), unless a multiplier follows ("1.250 billion").
+func parseNumber(raw string, scaled bool) (float64, bool) {
+	s := strings.NewReplacer(" ", "", " ", "", "'", "").Replace(raw)
+
+	lastDot, lastComma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	var decimal byte
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		decimal = '.'
+		if lastComma > lastDot {
+			decimal = ','
+		}
+	case lastDot >= 0 || lastComma >= 0:
+		sep, last := byte('.'), lastDot
+		if lastComma >= 0 {
+			sep, last = ',', lastComma
+		}
+		grouped := strings.Count(s, string(sep)) > 1 || (len(s)-last-1 == 3 && !scaled)
+		if !grouped {
+			decimal = sep
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			b.WriteByte(c)
+		case c == decimal:
+			b.WriteByte('.')
+		}
+	}
+
+	value, err := strconv.ParseFloat(b.String(), 64)
+	return value, err == nil
+}
+
+// scale applies a figure's multiplier
+func scale(n number) float64 {
+	if m, ok := multipliers[n.multiplier]; ok {
+		return n.value * m
+	}
+	return n.value
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"rectaify/pkg/types"
+)
+
+// SectionCaps limits how many items each report section lists; 0 lists all.
+// A capped section lists its most important items first and notes how many
+// were left out. The analysis itself always keeps every item.
+type SectionCaps struct {
+	Competitors int // by threat score
+	Risks       int // by impact, severity times likelihood
+	Barriers    int // by weight
+	Graveyard   int // in the order the analyzer gave them
+}
+
+// topItems returns at most limit items, highest score first, and the number
+// left out. Items keep their order when limit is 0 or not exceeded.
+func topItems[T any](items []T, limit int, score func(T) float64) ([]T, int) {
+	if limit <= 0 || len(items) <= limit {
+		return items, 0
+	}
+
+	ranked := append([]T(nil), items...)
+	if score != nil {
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return score(ranked[i]) > score(ranked[j])
+		})
+	}
+	return ranked[:limit], len(items) - limit
+}
+
+func cappedCompetitors(competitors []types.Competitor, limit int) ([]types.Competitor, int) {
+	return topItems(competitors, limit, func(c types.Competitor) float64 { return c.ThreatScore })
+}
+
+func cappedRisks(risks []types.Risk, limit int) ([]types.Risk, int) {
+	return topItems(risks, limit, func(r types.Risk) float64 { return float64(r.Severity * r.Likelihood) })
+}
+
+func cappedBarriers(barriers []types.Barrier, limit int) ([]types.Barrier, int) {
+	return topItems(barriers, limit, func(b types.Barrier) float64 { return b.Weight })
+}
+
+func cappedGraveyard(cases []types.GraveyardCase, limit int) ([]types.GraveyardCase, int) {
+	return topItems(cases, limit, nil)
+}
+
+// moreNote tells readers how many items a capped section left out
+func moreNote(hidden int, singular, plural string) string {
+	noun := plural
+	if hidden == 1 {
+		noun = singular
+	}
+	return fmt.Sprintf("...and %d more %s not shown", hidden, noun)
+}
@@ -0,0 +1,214 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"rectaify/internal/score"
+	"rectaify/pkg/types"
+)
+
+// DiffBuilder compares two analyses of the same idea
+type DiffBuilder struct{}
+
+// NewDiffBuilder creates a new diff builder
+func NewDiffBuilder() *DiffBuilder {
+	return &DiffBuilder{}
+}
+
+// Build produces the changes from base (the earlier analysis) to current
+func (db *DiffBuilder) Build(base, current types.Analysis) types.AnalysisDiff {
+	diff := types.AnalysisDiff{
+		BaseID:               base.ID,
+		CompareID:            current.ID,
+		RecommendationBefore: base.Verdict.Recommendation,
+		RecommendationAfter:  current.Verdict.Recommendation,
+	}
+
+	// Score deltas
+	dimensions := []struct {
+		name          string
+		before, after float64
+	}{
+		{"overall", base.Verdict.OverallScore, current.Verdict.OverallScore},
+		{"market", base.Verdict.MarketScore, current.Verdict.MarketScore},
+		{"problem", base.Verdict.ProblemScore, current.Verdict.ProblemScore},
+		{"barriers", base.Verdict.BarrierScore, current.Verdict.BarrierScore},
+		{"execution", base.Verdict.ExecutionScore, current.Verdict.ExecutionScore},
+		{"risks", base.Verdict.RiskScore, current.Verdict.RiskScore},
+		{"graveyard", base.Verdict.GraveyardScore, current.Verdict.GraveyardScore},
+	}
+	for _, d := range dimensions {
+		diff.ScoreDeltas = append(diff.ScoreDeltas, types.ScoreDelta{
+			Dimension: d.name,
+			Before:    d.before,
+			After:     d.after,
+			Delta:     math.Round((d.after-d.before)*10) / 10,
+		})
+	}
+
+	// Competitor changes
+	baseCompetitors := make(map[string]bool)
+	for _, c := range base.Market.Competitors {
+		baseCompetitors[normalizeName(c.Name)] = true
+	}
+	currentCompetitors := make(map[string]bool)
+	for _, c := range current.Market.Competitors {
+		key := normalizeName(c.Name)
+		currentCompetitors[key] = true
+		if !baseCompetitors[key] {
+			diff.AddedCompetitors = append(diff.AddedCompetitors, c.Name)
+		}
+	}
+	for _, c := range base.Market.Competitors {
+		if !currentCompetitors[normalizeName(c.Name)] {
+			diff.DroppedCompetitors = append(diff.DroppedCompetitors, c.Name)
+		}
+	}
+
+	// Risk changes, matched on category + description
+	baseRisks := make(map[string]bool)
+	for _, r := range base.Risks.Risks {
+		baseRisks[riskKey(r)] = true
+	}
+	currentRisks := make(map[string]bool)
+	for _, r := range current.Risks.Risks {
+		key := riskKey(r)
+		currentRisks[key] = true
+		if !baseRisks[key] {
+			diff.NewRisks = append(diff.NewRisks, r)
+		}
+	}
+	for _, r := range base.Risks.Risks {
+		if !currentRisks[riskKey(r)] {
+			diff.DroppedRisks = append(diff.DroppedRisks, r)
+		}
+	}
+
+	beforeLabel := recommendationLabel(base.Verdict.Recommendation)
+	afterLabel := recommendationLabel(current.Verdict.Recommendation)
+	diff.RecommendationChanged = beforeLabel != afterLabel
+
+	diff.Notes = db.compareWeights(base, current)
+	diff.Summary = db.summarize(diff, beforeLabel, afterLabel)
+
+	return diff
+}
+
+// Markdown renders a diff as a markdown section
+func (db *DiffBuilder) Markdown(diff types.AnalysisDiff) string {
+	var out strings.Builder
+
+	out.WriteString(fmt.Sprintf("## What Changed (%s → %s)\n\n", diff.BaseID, diff.CompareID))
+	out.WriteString(fmt.Sprintf("%s\n\n", diff.Summary))
+
+	for _, note := range diff.Notes {
+		out.WriteString(fmt.Sprintf("⚠️ **Note:** %s\n\n", note))
+	}
+
+	out.WriteString("| Dimension | Before | After | Change |\n")
+	out.WriteString("|-----------|--------|-------|--------|\n")
+	for _, d := range diff.ScoreDeltas {
+		out.WriteString(fmt.Sprintf("| %s | %.1f | %.1f | %+.1f |\n", strings.Title(d.Dimension), d.Before, d.After, d.Delta))
+	}
+	out.WriteString("\n")
+
+	if len(diff.AddedCompetitors) > 0 {
+		out.WriteString(fmt.Sprintf("**New competitors:** %s\n\n", strings.Join(diff.AddedCompetitors, ", ")))
+	}
+	if len(diff.DroppedCompetitors) > 0 {
+		out.WriteString(fmt.Sprintf("**No longer found:** %s\n\n", strings.Join(diff.DroppedCompetitors, ", ")))
+	}
+
+	if len(diff.NewRisks) > 0 {
+		out.WriteString("**New risks:**\n\n")
+		for _, r := range diff.NewRisks {
+			out.WriteString(fmt.Sprintf("- %s: %s\n", r.Category, r.Description))
+		}
+		out.WriteString("\n")
+	}
+
+	return out.String()
+}
+
+// compareWeights notes when the two analyses were scored with different weights
+func (db *DiffBuilder) compareWeights(base, current types.Analysis) []string {
+	baseWeights, baseOK := score.WeightsFromMeta(base.Meta)
+	currentWeights, currentOK := score.WeightsFromMeta(current.Meta)
+
+	if !baseOK || !currentOK {
+		return []string{"Score weights were not recorded for one or both analyses; score changes may reflect scoring changes rather than new evidence."}
+	}
+	if baseWeights != currentWeights {
+		return []string{"The analyses were scored with different weights; score changes partly reflect the weighting change."}
+	}
+	return nil
+}
+
+// summarize builds a one-paragraph description of the most important changes
+func (db *DiffBuilder) summarize(diff types.AnalysisDiff, beforeLabel, afterLabel string) string {
+	var parts []string
+
+	overall := diff.ScoreDeltas[0]
+	switch {
+	case overall.Delta > 0:
+		parts = append(parts, fmt.Sprintf("Overall score rose %.1f points (%.1f → %.1f)", overall.Delta, overall.Before, overall.After))
+	case overall.Delta < 0:
+		parts = append(parts, fmt.Sprintf("Overall score fell %.1f points (%.1f → %.1f)", -overall.Delta, overall.Before, overall.After))
+	default:
+		parts = append(parts, fmt.Sprintf("Overall score unchanged at %.1f", overall.After))
+	}
+
+	if diff.RecommendationChanged {
+		parts = append(parts, fmt.Sprintf("recommendation changed from %s to %s", beforeLabel, afterLabel))
+	} else if afterLabel != "" {
+		parts = append(parts, fmt.Sprintf("recommendation remains %s", afterLabel))
+	}
+
+	// Call out the dimension that moved the most
+	var biggest *types.ScoreDelta
+	for i := 1; i < len(diff.ScoreDeltas); i++ {
+		d := diff.ScoreDeltas[i]
+		if biggest == nil || math.Abs(d.Delta) > math.Abs(biggest.Delta) {
+			biggest = &diff.ScoreDeltas[i]
+		}
+	}
+	if biggest != nil && biggest.Delta != 0 {
+		parts = append(parts, fmt.Sprintf("the largest shift was in %s (%+.1f)", biggest.Dimension, biggest.Delta))
+	}
+
+	summary := strings.Join(parts, "; ") + "."
+	if len(diff.AddedCompetitors) > 0 || len(diff.NewRisks) > 0 {
+		summary += fmt.Sprintf(" %d new competitor(s) and %d new risk(s) identified.", len(diff.AddedCompetitors), len(diff.NewRisks))
+	}
+
+	return summary
+}
+
+// verdictTier returns the stored verdict band, deriving it from the overall
+// score for analyses saved before the band was recorded
+func verdictTier(verdict types.Viability) types.VerdictTier {
+	if verdict.Verdict != "" {
+		return verdict.Verdict
+	}
+	return score.VerdictForScore(verdict.OverallScore)
+}
+
+// recommendationLabel extracts the verdict label (e.g. "GO") from a recommendation
+func recommendationLabel(recommendation string) string {
+	if idx := strings.Index(recommendation, ":"); idx > 0 {
+		return strings.TrimSpace(recommendation[:idx])
+	}
+	return strings.TrimSpace(recommendation)
+}
+
+// normalizeName normalizes a company name for comparison
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// riskKey identifies a risk for comparison across analyses
+func riskKey(r types.Risk) string {
+	return strings.ToLower(r.Category) + "|" + strings.ToLower(strings.TrimSpace(r.Description))
+}
@@ -9,24 +9,33 @@ import (
 )
 
 // HTMLBuilder generates HTML reports from analysis results
-type HTMLBuilder struct{}
+type HTMLBuilder struct {
+	evidenceOrder EvidenceOrder
+	scorer        EvidenceScorer // nil keeps stored order unless ordering by date
+	caps          SectionCaps
+	theme         Theme
+}
 
-// NewHTMLBuilder creates a new HTML builder
-func NewHTMLBuilder() *HTMLBuilder {
-	return &HTMLBuilder{}
+// NewHTMLBuilder creates a new HTML builder listing evidence in the given
+// order, capping sections at caps and branded with theme
+func NewHTMLBuilder(evidenceOrder EvidenceOrder, scorer EvidenceScorer, caps SectionCaps, theme Theme) *HTMLBuilder {
+	return &HTMLBuilder{evidenceOrder: evidenceOrder, scorer: scorer, caps: caps, theme: theme}
 }
 
 // Build generates an HTML report from analysis
 func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 	var report strings.Builder
 
+	// Number evidence in reference order so citation links match the list
+	analysis.Evidence = orderEvidence(analysis.Evidence, hb.evidenceOrder, hb.scorer)
+
 	// HTML header
 	report.WriteString("<!DOCTYPE html>\n")
 	report.WriteString("<html lang=\"en\">\n")
 	report.WriteString("<head>\n")
 	report.WriteString("    <meta charset=\"UTF-8\">\n")
 	report.WriteString("    <meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n")
-	report.WriteString(fmt.Sprintf("    <title>RectAify: %s</title>\n", html.EscapeString(analysis.Idea.Title)))
+	report.WriteString(fmt.Sprintf("    <title>%s: %s</title>\n", ProductName, html.EscapeString(analysis.Idea.Title)))
 	report.WriteString("    <style>\n")
 	report.WriteString(hb.getCSS())
 	report.WriteString("    </style>\n")
@@ -35,14 +44,25 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 
 	// Header
 	report.WriteString("    <header class=\"header\">\n")
-	report.WriteString(fmt.Sprintf("        <h1>RectAify: %s</h1>\n", html.EscapeString(analysis.Idea.Title)))
+	report.WriteString(hb.theme.logo())
+	report.WriteString(fmt.Sprintf("        <h1>%s: %s</h1>\n", ProductName, html.EscapeString(analysis.Idea.Title)))
 	report.WriteString(fmt.Sprintf("        <p class=\"one-liner\">%s</p>\n", html.EscapeString(analysis.Idea.OneLiner)))
 	report.WriteString("        <p class=\"analysis-date\">Analysis Date: " + analysis.CreatedAt.Format("January 2, 2006") + "</p>\n")
 	if analysis.Partial {
 		report.WriteString("        <div class=\"warning\">⚠️ This analysis is partial due to timeout or processing limitations.</div>\n")
 	}
+	for _, warning := range analysis.Warnings {
+		report.WriteString(fmt.Sprintf("        <div class=\"warning\">⚠️ %s</div>\n", html.EscapeString(warning)))
+	}
 	report.WriteString("    </header>\n\n")
 
+	// TL;DR
+	if analysis.Summary != "" {
+		report.WriteString("    <section class=\"tldr\">\n")
+		report.WriteString(fmt.Sprintf("        <p><strong>TL;DR:</strong> %s</p>\n", html.EscapeString(analysis.Summary)))
+		report.WriteString("    </section>\n\n")
+	}
+
 	// Executive Summary
 	report.WriteString("    <section class=\"executive-summary\">\n")
 	report.WriteString("        <h2>Executive Summary</h2>\n")
@@ -55,6 +75,11 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 	report.WriteString("            </div>\n")
 	report.WriteString("            <div class=\"recommendation\">\n")
 	report.WriteString("                <h3>Recommendation</h3>\n")
+	tier := verdictTier(analysis.Verdict)
+	report.WriteString(fmt.Sprintf("                <span class=\"verdict-badge %s\">%s</span>\n", strings.ReplaceAll(string(tier), "_", "-"), tier.Label()))
+	if analysis.Flagged {
+		report.WriteString(fmt.Sprintf("                <p class=\"review-flag\"><strong>Flagged for review:</strong> %s</p>\n", reviewFlagText(analysis)))
+	}
 	report.WriteString(fmt.Sprintf("                <p>%s</p>\n", html.EscapeString(analysis.Verdict.Recommendation)))
 	report.WriteString("            </div>\n")
 	report.WriteString("        </div>\n")
@@ -87,6 +112,39 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 	}
 
 	report.WriteString("            </div>\n")
+	for _, note := range scoreRationale(analysis.Verdict) {
+		report.WriteString(fmt.Sprintf("            <p class=\"score-rationale\"><strong>%s:</strong> %s</p>\n", note.label, html.EscapeString(note.text)))
+	}
+	report.WriteString("        </div>\n")
+
+	// Score Sensitivity
+	sensitivity := analysisSensitivity(analysis)
+	report.WriteString("        <div class=\"score-sensitivity\">\n")
+	report.WriteString("            <h3>Score Sensitivity</h3>\n")
+	report.WriteString(fmt.Sprintf("            <p>The overall score ranges from %.1f to %.1f when any one dimension moves %.0f points or the dimensions are weighted differently.</p>\n",
+		sensitivity.ScoreMin, sensitivity.ScoreMax, sensitivity.Delta))
+	report.WriteString("            <table>\n")
+	report.WriteString(fmt.Sprintf("                <tr><th>Dimension</th><th>&minus;%.0f</th><th>+%.0f</th><th>Points to Change Verdict</th></tr>\n", sensitivity.Delta, sensitivity.Delta))
+	for _, d := range sensitivity.Dimensions {
+		flip := "&mdash;"
+		if d.PointsToFlip > 0 {
+			flip = fmt.Sprintf("%.1f", d.PointsToFlip)
+		}
+		class := ""
+		if d.Pivotal {
+			class = " class=\"pivotal\""
+			flip += " (pivotal)"
+		}
+		report.WriteString(fmt.Sprintf("                <tr%s><td>%s</td><td>%.1f (%s)</td><td>%.1f (%s)</td><td>%s</td></tr>\n",
+			class, dimensionLabel(d.Dimension), d.LowScore, d.LowVerdict.Label(), d.HighScore, d.HighVerdict.Label(), flip))
+	}
+	report.WriteString("            </table>\n")
+	report.WriteString("            <table>\n")
+	report.WriteString("                <tr><th>Weighting</th><th>Overall Score</th><th>Verdict</th></tr>\n")
+	for _, p := range sensitivity.Profiles {
+		report.WriteString(fmt.Sprintf("                <tr><td>%s</td><td>%.1f</td><td>%s</td></tr>\n", strings.Title(p.Profile), p.OverallScore, p.Verdict.Label()))
+	}
+	report.WriteString("            </table>\n")
 	report.WriteString("        </div>\n")
 
 	// Key Insights
@@ -94,8 +152,13 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 		report.WriteString("        <div class=\"key-insights\">\n")
 		report.WriteString("            <h3>Key Insights</h3>\n")
 		report.WriteString("            <ul>\n")
+		refs := evidenceNumbers(analysis.Evidence)
 		for _, insight := range analysis.Verdict.KeyInsights {
-			report.WriteString(fmt.Sprintf("                <li>%s</li>\n", html.EscapeString(insight)))
+			if links := citationLinks(insight.EvidenceIDs, refs); links != "" {
+				report.WriteString(fmt.Sprintf("                <li>%s <span class=\"citations\">[%s]</span></li>\n", html.EscapeString(insight.Text), links))
+			} else {
+				report.WriteString(fmt.Sprintf("                <li>%s</li>\n", html.EscapeString(insight.Text)))
+			}
 		}
 		report.WriteString("            </ul>\n")
 		report.WriteString("        </div>\n")
@@ -118,19 +181,30 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 	if len(analysis.Market.Competitors) > 0 {
 		report.WriteString("            <h4>Competitors</h4>\n")
 		report.WriteString("            <div class=\"competitors\">\n")
-		for _, competitor := range analysis.Market.Competitors {
+		competitors, hidden := cappedCompetitors(analysis.Market.Competitors, hb.caps.Competitors)
+		for _, competitor := range competitors {
 			report.WriteString("                <div class=\"competitor\">\n")
-			report.WriteString(fmt.Sprintf("                    <h5>%s</h5>\n", html.EscapeString(competitor.Name)))
+			if competitor.ThreatScore > 0 {
+				report.WriteString(fmt.Sprintf("                    <h5>%s <span class=\"threat %s\">Threat %.0f</span></h5>\n", html.EscapeString(competitor.Name), hb.getScoreClass(100-competitor.ThreatScore), competitor.ThreatScore))
+			} else {
+				report.WriteString(fmt.Sprintf("                    <h5>%s</h5>\n", html.EscapeString(competitor.Name)))
+			}
 			report.WriteString(fmt.Sprintf("                    <p>%s</p>\n", html.EscapeString(competitor.Description)))
 			if competitor.Funding != "" {
-				report.WriteString(fmt.Sprintf("                    <p><strong>Funding:</strong> %s</p>\n", html.EscapeString(competitor.Funding)))
+				report.WriteString(fmt.Sprintf("                    <p><strong>Funding:</strong> %s</p>\n", html.EscapeString(fundingText(competitor))))
 			}
 			if competitor.Stage != "" {
 				report.WriteString(fmt.Sprintf("                    <p><strong>Stage:</strong> %s</p>\n", html.EscapeString(competitor.Stage)))
 			}
+			if competitor.Unsupported {
+				report.WriteString(fmt.Sprintf("                    <p class=\"unsupported\">%s</p>\n", unsupportedNote))
+			}
 			report.WriteString("                </div>\n")
 		}
 		report.WriteString("            </div>\n")
+		if hidden > 0 {
+			report.WriteString(fmt.Sprintf("            <p class=\"more\"><em>%s</em></p>\n", moreNote(hidden, "competitor", "competitors")))
+		}
 	}
 	report.WriteString("        </div>\n")
 
@@ -151,6 +225,27 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 	}
 	report.WriteString("        </div>\n")
 
+	// Next Steps
+	if len(analysis.NextSteps) > 0 {
+		refs := evidenceNumbers(analysis.Evidence)
+		report.WriteString("        <div class=\"analysis-section next-steps\">\n")
+		report.WriteString("            <h3>Next Steps</h3>\n")
+		report.WriteString("            <p>Experiments and research to de-risk the weakest areas, most impactful first.</p>\n")
+		report.WriteString("            <ol>\n")
+		for _, step := range analysis.NextSteps {
+			report.WriteString(fmt.Sprintf("                <li><strong>%s:</strong> %s", dimensionLabel(step.Dimension), html.EscapeString(step.Action)))
+			if links := citationLinks(step.EvidenceIDs, refs); links != "" {
+				report.WriteString(fmt.Sprintf(" <span class=\"citations\">[%s]</span>", links))
+			}
+			if step.SuccessSignal != "" {
+				report.WriteString(fmt.Sprintf("<br><em>Success signal:</em> %s", html.EscapeString(step.SuccessSignal)))
+			}
+			report.WriteString("</li>\n")
+		}
+		report.WriteString("            </ol>\n")
+		report.WriteString("        </div>\n")
+	}
+
 	// Additional sections would continue here...
 	// For brevity, I'll add the closing tags
 
@@ -162,19 +257,24 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 		report.WriteString("        <h2>Evidence References</h2>\n")
 		report.WriteString("        <div class=\"evidence-list\">\n")
 		for i, ev := range analysis.Evidence {
-			report.WriteString("            <div class=\"evidence-item\">\n")
+			report.WriteString(fmt.Sprintf("            <div class=\"evidence-item\" id=\"evidence-%d\">\n", i+1))
 			report.WriteString(fmt.Sprintf("                <span class=\"evidence-number\">[%d]</span>\n", i+1))
 			report.WriteString("                <div class=\"evidence-content\">\n")
 			report.WriteString(fmt.Sprintf("                    <h4><a href=\"%s\" target=\"_blank\">%s</a></h4>\n", 
 				html.EscapeString(ev.URL), html.EscapeString(ev.Title)))
 			if ev.Snippet != "" {
 				report.WriteString(fmt.Sprintf("                    <p class=\"snippet\">%s</p>\n", html.EscapeString(ev.Snippet)))
+			} else if ev.MissingContent() {
+				report.WriteString(fmt.Sprintf("                    <p class=\"snippet\"><em>%s</em></p>\n", html.EscapeString(missingContentNote(ev))))
 			}
 			report.WriteString("                    <div class=\"evidence-meta\">\n")
 			if ev.PublishedAt != nil {
 				report.WriteString(fmt.Sprintf("                        <span>Published: %s</span>\n", ev.PublishedAt.Format("Jan 2, 2006")))
 			}
 			report.WriteString(fmt.Sprintf("                        <span>Source: %s</span>\n", html.EscapeString(strings.Title(ev.SourceType))))
+			if dimensions := analysis.EvidenceCitations[ev.ID]; len(dimensions) > 0 {
+				report.WriteString(fmt.Sprintf("                        <span>Cited by: %s</span>\n", html.EscapeString(citedByText(dimensions))))
+			}
 			report.WriteString("                    </div>\n")
 			report.WriteString("                </div>\n")
 			report.WriteString("            </div>\n")
@@ -185,7 +285,7 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 
 	// Footer
 	report.WriteString("    <footer class=\"footer\">\n")
-	report.WriteString("        <p>Generated by RectAIfy</p>\n")
+	report.WriteString(fmt.Sprintf("        <p>%s</p>\n", html.EscapeString(hb.theme.footer())))
 	report.WriteString("    </footer>\n")
 
 	report.WriteString("</body>\n")
@@ -194,9 +294,18 @@ func (hb *HTMLBuilder) Build(analysis types.Analysis) string {
 	return report.String()
 }
 
+// citationLinks links cited evidence to its Evidence References entries
+func citationLinks(evidenceIDs []string, refs map[string]int) string {
+	var links []string
+	for _, n := range citationNumbers(evidenceIDs, refs) {
+		links = append(links, fmt.Sprintf("<a href=\"#evidence-%s\">%s</a>", n, n))
+	}
+	return strings.Join(links, ", ")
+}
+
 // getCSS returns the CSS styles for the HTML report
 func (hb *HTMLBuilder) getCSS() string {
-	return `
+	return hb.theme.cssVariables() + `
         * {
             margin: 0;
             padding: 0;
@@ -212,13 +321,19 @@ func (hb *HTMLBuilder) getCSS() string {
         }
 
         .header {
-            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            background: var(--header-background);
             color: white;
             padding: 2rem;
             text-align: center;
             box-shadow: 0 4px 20px rgba(0,0,0,0.1);
         }
 
+        .header .logo {
+            max-height: 4rem;
+            max-width: 16rem;
+            margin-bottom: 1rem;
+        }
+
         .header h1 {
             font-size: 2.5rem;
             margin-bottom: 0.5rem;
@@ -244,6 +359,42 @@ func (hb *HTMLBuilder) getCSS() string {
             border: 1px solid rgba(255, 193, 7, 0.3);
         }
 
+        .verdict-badge {
+            display: inline-block;
+            padding: 4px 12px;
+            border-radius: 12px;
+            color: #fff;
+            font-weight: bold;
+            font-size: 0.85em;
+            letter-spacing: 0.05em;
+            margin-bottom: 8px;
+        }
+
+        .verdict-badge.strong-go { background: #28a745; }
+        .verdict-badge.go { background: #5cb85c; }
+        .verdict-badge.caution { background: #f0ad4e; }
+        .verdict-badge.high-risk { background: #fd7e14; }
+        .verdict-badge.no-go { background: #dc3545; }
+
+        .review-flag {
+            background: rgba(220, 53, 69, 0.1);
+            color: #842029;
+            padding: 0.5rem 0.75rem;
+            border-radius: 0.5rem;
+            border: 1px solid rgba(220, 53, 69, 0.3);
+            margin-bottom: 0.5rem;
+        }
+
+        .tldr {
+            background: white;
+            margin: 2rem 2rem 0;
+            padding: 1.25rem 2rem;
+            border-radius: 1rem;
+            border-left: 4px solid var(--primary);
+            box-shadow: 0 8px 32px rgba(0,0,0,0.1);
+            font-size: 1.1rem;
+        }
+
         .executive-summary {
             background: white;
             margin: 2rem;
@@ -333,6 +484,41 @@ func (hb *HTMLBuilder) getCSS() string {
             color: #555;
         }
 
+        .score-rationale {
+            margin-top: 1rem;
+            font-size: 0.9rem;
+            color: #666;
+        }
+
+        .unsupported {
+            color: #b45309;
+            font-size: 0.9rem;
+        }
+
+        .more {
+            color: #666;
+            font-size: 0.9rem;
+            margin-top: 0.5rem;
+        }
+
+        .score-sensitivity table {
+            width: 100%;
+            border-collapse: collapse;
+            margin-top: 1rem;
+            font-size: 0.9rem;
+        }
+
+        .score-sensitivity th,
+        .score-sensitivity td {
+            text-align: left;
+            padding: 0.4rem 0.6rem;
+            border-bottom: 1px solid #e0e0e0;
+        }
+
+        .score-sensitivity tr.pivotal {
+            font-weight: bold;
+        }
+
         .score-bar-container {
             background: #e0e0e0;
             height: 8px;
@@ -400,7 +586,7 @@ func (hb *HTMLBuilder) getCSS() string {
             background: #f8f9fa;
             padding: 1rem;
             border-radius: 0.5rem;
-            border-left: 4px solid #667eea;
+            border-left: 4px solid var(--primary);
         }
 
         .evidence {
@@ -425,8 +611,26 @@ func (hb *HTMLBuilder) getCSS() string {
             border-radius: 0.5rem;
         }
 
+        .threat {
+            font-size: 0.75em;
+            font-weight: normal;
+            padding: 2px 8px;
+            border-radius: 10px;
+            color: #fff;
+            background: #6c757d;
+        }
+
+        .threat.critical, .threat.poor { background: #dc3545; }
+        .threat.fair { background: #f0ad4e; }
+        .threat.good, .threat.excellent { background: #28a745; }
+
+        .citations {
+            font-size: 0.85em;
+            color: #666;
+        }
+
         .evidence-number {
-            background: #667eea;
+            background: var(--primary);
             color: white;
             width: 30px;
             height: 30px;
@@ -443,7 +647,7 @@ func (hb *HTMLBuilder) getCSS() string {
         }
 
         .evidence-content a {
-            color: #667eea;
+            color: var(--primary);
             text-decoration: none;
         }
 
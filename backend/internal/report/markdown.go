@@ -2,35 +2,58 @@ package report
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"rectaify/internal/score"
 	"rectaify/pkg/types"
 )
 
 // MarkdownBuilder generates markdown reports from analysis results
-type MarkdownBuilder struct{}
+type MarkdownBuilder struct {
+	evidenceOrder EvidenceOrder
+	scorer        EvidenceScorer // nil keeps stored order unless ordering by date
+	caps          SectionCaps
+}
 
-// NewMarkdownBuilder creates a new markdown builder
-func NewMarkdownBuilder() *MarkdownBuilder {
-	return &MarkdownBuilder{}
+// NewMarkdownBuilder creates a new markdown builder listing evidence in the
+// given order and capping sections at caps
+func NewMarkdownBuilder(evidenceOrder EvidenceOrder, scorer EvidenceScorer, caps SectionCaps) *MarkdownBuilder {
+	return &MarkdownBuilder{evidenceOrder: evidenceOrder, scorer: scorer, caps: caps}
 }
 
 // Build generates a markdown report from analysis
 func (mb *MarkdownBuilder) Build(analysis types.Analysis) string {
 	var report strings.Builder
 
+	// Number evidence in reference order so citations match the list
+	analysis.Evidence = orderEvidence(analysis.Evidence, mb.evidenceOrder, mb.scorer)
+	refs := evidenceNumbers(analysis.Evidence)
+
 	// Header
-	report.WriteString(fmt.Sprintf("# RectAify: %s\n\n", analysis.Idea.Title))
+	report.WriteString(fmt.Sprintf("# %s: %s\n\n", ProductName, analysis.Idea.Title))
 	report.WriteString(fmt.Sprintf("**One-liner:** %s\n\n", analysis.Idea.OneLiner))
 	report.WriteString(fmt.Sprintf("**Analysis Date:** %s\n\n", analysis.CreatedAt.Format("January 2, 2006")))
 
 	if analysis.Partial {
 		report.WriteString("⚠️ **Note:** This analysis is partial due to timeout or processing limitations.\n\n")
 	}
+	for _, warning := range analysis.Warnings {
+		report.WriteString(fmt.Sprintf("⚠️ **Warning:** %s\n\n", warning))
+	}
+
+	// TL;DR
+	if analysis.Summary != "" {
+		report.WriteString(fmt.Sprintf("> **TL;DR:** %s\n\n", analysis.Summary))
+	}
 
 	// Executive Summary
 	report.WriteString("## Executive Summary\n\n")
 	report.WriteString(fmt.Sprintf("**Overall Score:** %.1f/100\n\n", analysis.Verdict.OverallScore))
+	report.WriteString(fmt.Sprintf("**Verdict:** %s\n\n", verdictTier(analysis.Verdict).Label()))
+	if analysis.Flagged {
+		report.WriteString(fmt.Sprintf("🚩 **Flagged for review:** %s\n\n", reviewFlagText(analysis)))
+	}
 	report.WriteString(fmt.Sprintf("**Recommendation:** %s\n\n", analysis.Verdict.Recommendation))
 
 	// Score Breakdown
@@ -45,11 +68,42 @@ func (mb *MarkdownBuilder) Build(analysis types.Analysis) string {
 	report.WriteString(fmt.Sprintf("| Graveyard | %.1f/100 | %s |\n", analysis.Verdict.GraveyardScore, mb.getScoreAssessment(analysis.Verdict.GraveyardScore)))
 	report.WriteString("\n")
 
+	// How the calculator reached scores that need explaining
+	if notes := scoreRationale(analysis.Verdict); len(notes) > 0 {
+		for _, note := range notes {
+			report.WriteString(fmt.Sprintf("- **%s:** %s\n", note.label, note.text))
+		}
+		report.WriteString("\n")
+	}
+
+	// How far the verdict is from changing
+	sensitivity := analysisSensitivity(analysis)
+	report.WriteString("### Score Sensitivity\n\n")
+	report.WriteString(fmt.Sprintf("The overall score ranges from %.1f to %.1f when any one dimension moves %.0f points or the dimensions are weighted differently.\n\n",
+		sensitivity.ScoreMin, sensitivity.ScoreMax, sensitivity.Delta))
+	report.WriteString(fmt.Sprintf("| Dimension | −%.0f | +%.0f | Points to Change Verdict |\n", sensitivity.Delta, sensitivity.Delta))
+	report.WriteString("|-----------|-----|-----|--------------------------|\n")
+	for _, d := range sensitivity.Dimensions {
+		report.WriteString(fmt.Sprintf("| %s | %.1f (%s) | %.1f (%s) | %s |\n",
+			dimensionLabel(d.Dimension), d.LowScore, d.LowVerdict.Label(), d.HighScore, d.HighVerdict.Label(), flipText(d)))
+	}
+	report.WriteString("\n")
+	report.WriteString("| Weighting | Overall Score | Verdict |\n")
+	report.WriteString("|-----------|---------------|---------|\n")
+	for _, p := range sensitivity.Profiles {
+		report.WriteString(fmt.Sprintf("| %s | %.1f/100 | %s |\n", strings.Title(p.Profile), p.OverallScore, p.Verdict.Label()))
+	}
+	report.WriteString("\n")
+
 	// Key Insights
 	if len(analysis.Verdict.KeyInsights) > 0 {
 		report.WriteString("### Key Insights\n\n")
 		for _, insight := range analysis.Verdict.KeyInsights {
-			report.WriteString(fmt.Sprintf("- %s\n", insight))
+			if citations := citationNumbers(insight.EvidenceIDs, refs); len(citations) > 0 {
+				report.WriteString(fmt.Sprintf("- %s [%s]\n", insight.Text, strings.Join(citations, ", ")))
+			} else {
+				report.WriteString(fmt.Sprintf("- %s\n", insight.Text))
+			}
 		}
 		report.WriteString("\n")
 	}
@@ -66,20 +120,30 @@ func (mb *MarkdownBuilder) Build(analysis types.Analysis) string {
 
 	if len(analysis.Market.Competitors) > 0 {
 		report.WriteString("#### Competitors\n\n")
-		for i, competitor := range analysis.Market.Competitors {
-			report.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, competitor.Name))
+		competitors, hidden := cappedCompetitors(analysis.Market.Competitors, mb.caps.Competitors)
+		for i, competitor := range competitors {
+			if competitor.ThreatScore > 0 {
+				report.WriteString(fmt.Sprintf("%d. **%s** (threat %.0f/100)\n", i+1, competitor.Name, competitor.ThreatScore))
+			} else {
+				report.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, competitor.Name))
+			}
 			report.WriteString(fmt.Sprintf("   - %s\n", competitor.Description))
 			if competitor.Funding != "" {
-				report.WriteString(fmt.Sprintf("   - Funding: %s\n", competitor.Funding))
+				report.WriteString(fmt.Sprintf("   - Funding: %s\n", fundingText(competitor)))
 			}
 			if competitor.Stage != "" {
 				report.WriteString(fmt.Sprintf("   - Stage: %s\n", competitor.Stage))
 			}
 			if len(competitor.EvidenceIDs) > 0 {
-				report.WriteString(fmt.Sprintf("   - Sources: %s\n", mb.formatEvidenceRefs(competitor.EvidenceIDs)))
+				report.WriteString(fmt.Sprintf("   - Sources: %s\n", formatEvidenceRefs(competitor.EvidenceIDs, refs)))
+			} else if competitor.Unsupported {
+				report.WriteString(fmt.Sprintf("   - %s\n", unsupportedNote))
 			}
 			report.WriteString("\n")
 		}
+		if hidden > 0 {
+			report.WriteString(fmt.Sprintf("_%s_\n\n", moreNote(hidden, "competitor", "competitors")))
+		}
 	}
 
 	// Problem Analysis
@@ -100,15 +164,21 @@ func (mb *MarkdownBuilder) Build(analysis types.Analysis) string {
 	// Barriers Analysis
 	if len(analysis.Barriers.Barriers) > 0 {
 		report.WriteString("### Execution Barriers\n\n")
-		for i, barrier := range analysis.Barriers.Barriers {
+		barriers, hidden := cappedBarriers(analysis.Barriers.Barriers, mb.caps.Barriers)
+		for i, barrier := range barriers {
 			weight := barrier.Weight * 100
 			report.WriteString(fmt.Sprintf("%d. **%s** (Impact: %.0f%%)\n", i+1, strings.Title(barrier.Type), weight))
 			report.WriteString(fmt.Sprintf("   %s\n", barrier.Description))
 			if len(barrier.EvidenceIDs) > 0 {
-				report.WriteString(fmt.Sprintf("   Sources: %s\n", mb.formatEvidenceRefs(barrier.EvidenceIDs)))
+				report.WriteString(fmt.Sprintf("   Sources: %s\n", formatEvidenceRefs(barrier.EvidenceIDs, refs)))
+			} else if barrier.Unsupported {
+				report.WriteString(fmt.Sprintf("   %s\n", unsupportedNote))
 			}
 			report.WriteString("\n")
 		}
+		if hidden > 0 {
+			report.WriteString(fmt.Sprintf("_%s_\n\n", moreNote(hidden, "barrier", "barriers")))
+		}
 	}
 
 	// Execution Analysis
@@ -121,7 +191,8 @@ func (mb *MarkdownBuilder) Build(analysis types.Analysis) string {
 	// Risk Analysis
 	if len(analysis.Risks.Risks) > 0 {
 		report.WriteString("### Risk Analysis\n\n")
-		for i, risk := range analysis.Risks.Risks {
+		risks, hidden := cappedRisks(analysis.Risks.Risks, mb.caps.Risks)
+		for i, risk := range risks {
 			impact := risk.Severity * risk.Likelihood
 			report.WriteString(fmt.Sprintf("%d. **%s Risk** (Severity: %d/5, Likelihood: %d/5, Impact: %d/25)\n", 
 				i+1, risk.Category, risk.Severity, risk.Likelihood, impact))
@@ -130,23 +201,50 @@ func (mb *MarkdownBuilder) Build(analysis types.Analysis) string {
 				report.WriteString(fmt.Sprintf("   **Mitigation:** %s\n", risk.Mitigation))
 			}
 			if len(risk.EvidenceIDs) > 0 {
-				report.WriteString(fmt.Sprintf("   Sources: %s\n", mb.formatEvidenceRefs(risk.EvidenceIDs)))
+				report.WriteString(fmt.Sprintf("   Sources: %s\n", formatEvidenceRefs(risk.EvidenceIDs, refs)))
+			} else if risk.Unsupported {
+				report.WriteString(fmt.Sprintf("   %s\n", unsupportedNote))
 			}
 			report.WriteString("\n")
 		}
+		if hidden > 0 {
+			report.WriteString(fmt.Sprintf("_%s_\n\n", moreNote(hidden, "risk", "risks")))
+		}
 	}
 
 	// Graveyard Analysis
 	if len(analysis.Graveyard.Cases) > 0 {
 		report.WriteString("### Graveyard Analysis\n\n")
 		report.WriteString("#### Failed Similar Companies\n\n")
-		for i, graveyardCase := range analysis.Graveyard.Cases {
+		cases, hidden := cappedGraveyard(analysis.Graveyard.Cases, mb.caps.Graveyard)
+		for i, graveyardCase := range cases {
 			report.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, graveyardCase.CompanyName))
 			report.WriteString(fmt.Sprintf("   - **Description:** %s\n", graveyardCase.Description))
 			report.WriteString(fmt.Sprintf("   - **Failure Cause:** %s\n", graveyardCase.FailureCause))
 			report.WriteString(fmt.Sprintf("   - **Lessons:** %s\n", graveyardCase.Lessons))
 			if len(graveyardCase.EvidenceIDs) > 0 {
-				report.WriteString(fmt.Sprintf("   - Sources: %s\n", mb.formatEvidenceRefs(graveyardCase.EvidenceIDs)))
+				report.WriteString(fmt.Sprintf("   - Sources: %s\n", formatEvidenceRefs(graveyardCase.EvidenceIDs, refs)))
+			} else if graveyardCase.Unsupported {
+				report.WriteString(fmt.Sprintf("   - %s\n", unsupportedNote))
+			}
+			report.WriteString("\n")
+		}
+		if hidden > 0 {
+			report.WriteString(fmt.Sprintf("_%s_\n\n", moreNote(hidden, "failed company", "failed companies")))
+		}
+	}
+
+	// Next Steps
+	if len(analysis.NextSteps) > 0 {
+		report.WriteString("## Next Steps\n\n")
+		report.WriteString("Experiments and research to de-risk the weakest areas, most impactful first.\n\n")
+		for i, step := range analysis.NextSteps {
+			report.WriteString(fmt.Sprintf("%d. **%s:** %s\n", i+1, dimensionLabel(step.Dimension), step.Action))
+			if step.SuccessSignal != "" {
+				report.WriteString(fmt.Sprintf("   - Success signal: %s\n", step.SuccessSignal))
+			}
+			if len(step.EvidenceIDs) > 0 {
+				report.WriteString(fmt.Sprintf("   - Sources: %s\n", formatEvidenceRefs(step.EvidenceIDs, refs)))
 			}
 			report.WriteString("\n")
 		}
@@ -166,11 +264,16 @@ func (mb *MarkdownBuilder) Build(analysis types.Analysis) string {
 			report.WriteString(fmt.Sprintf("    %s\n", ev.URL))
 			if ev.Snippet != "" {
 				report.WriteString(fmt.Sprintf("    %s\n", ev.Snippet))
+			} else if ev.MissingContent() {
+				report.WriteString(fmt.Sprintf("    _%s_\n", missingContentNote(ev)))
 			}
 			if ev.PublishedAt != nil {
 				report.WriteString(fmt.Sprintf("    Published: %s\n", ev.PublishedAt.Format("January 2, 2006")))
 			}
 			report.WriteString(fmt.Sprintf("    Source: %s\n", strings.Title(ev.SourceType)))
+			if dimensions := analysis.EvidenceCitations[ev.ID]; len(dimensions) > 0 {
+				report.WriteString(fmt.Sprintf("    Cited by: %s\n", citedByText(dimensions)))
+			}
 			report.WriteString("\n")
 			counter++
 		}
@@ -178,7 +281,7 @@ func (mb *MarkdownBuilder) Build(analysis types.Analysis) string {
 
 	// Footer
 	report.WriteString("---\n\n")
-	report.WriteString("*Generated by RectAIfy*\n")
+	report.WriteString("*Generated by " + ProductName + "*\n")
 
 	return report.String()
 }
@@ -198,18 +301,152 @@ func (mb *MarkdownBuilder) getScoreAssessment(score float64) string {
 	}
 }
 
-// formatEvidenceRefs formats evidence IDs as numbered references
-func (mb *MarkdownBuilder) formatEvidenceRefs(evidenceIDs []string) string {
-	if len(evidenceIDs) == 0 {
-		return ""
+// unsupportedNote marks items that cite no valid evidence
+const unsupportedNote = "⚠️ Not supported by any provided evidence"
+
+// missingContentNote explains why a reference has no excerpt
+func missingContentNote(ev types.Evidence) string {
+	switch ev.ContentType {
+	case "paywalled":
+		return "Paywalled; no excerpt was available."
+	case "pdf":
+		return "PDF content could not be read; cited by title only."
+	default:
+		return strings.Title(ev.ContentType) + " content could not be read; cited by title only."
+	}
+}
+
+// rationaleNote is the explanation of one dimension's score
+type rationaleNote struct {
+	label string
+	text  string
+}
+
+// breakdownDimensions lists the ScoreRationale keys in breakdown order
+var breakdownDimensions = []struct{ key, label string }{
+	{"market", "Market"},
+	{"problem", "Problem"},
+	{"barriers", "Barriers"},
+	{"execution", "Execution"},
+	{"risks", "Risks"},
+	{"graveyard", "Graveyard"},
+}
+
+// reviewFlagText explains why an analysis was flagged for review
+func reviewFlagText(analysis types.Analysis) string {
+	return fmt.Sprintf("the overall score of %.1f is below the review threshold of %g.",
+		analysis.Verdict.OverallScore, analysis.ReviewThreshold)
+}
+
+// scoreRationale returns the verdict's score rationale in breakdown order
+func scoreRationale(verdict types.Viability) []rationaleNote {
+	var notes []rationaleNote
+	for _, dimension := range breakdownDimensions {
+		if text := verdict.ScoreRationale[dimension.key]; text != "" {
+			notes = append(notes, rationaleNote{label: dimension.label, text: text})
+		}
+	}
+	return notes
+}
+
+// dimensionLabel returns the display name of a ScoreRationale key
+func dimensionLabel(key string) string {
+	for _, dimension := range breakdownDimensions {
+		if dimension.key == key {
+			return dimension.label
+		}
+	}
+	return key
+}
+
+// citedByText lists the dimensions citing an evidence item
+func citedByText(dimensions []string) string {
+	labels := make([]string, len(dimensions))
+	for i, dimension := range dimensions {
+		labels[i] = dimensionLabel(dimension)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// analysisSensitivity measures the analysis's score sensitivity under the
+// weights it was scored with
+func analysisSensitivity(analysis types.Analysis) types.Sensitivity {
+	var weights *score.ScoreWeights
+	if recorded, ok := score.WeightsFromMeta(analysis.Meta); ok {
+		weights = &recorded
+	}
+	return score.NewCalculator(weights, nil).Sensitivity(analysis.Verdict, score.DefaultSensitivityDelta)
+}
+
+// flipText describes how far a dimension is from changing the verdict
+func flipText(d types.DimensionSensitivity) string {
+	switch {
+	case d.PointsToFlip == 0:
+		return "—"
+	case d.Pivotal:
+		return fmt.Sprintf("**%.1f** (pivotal)", d.PointsToFlip)
+	default:
+		return fmt.Sprintf("%.1f", d.PointsToFlip)
 	}
+}
 
-	refs := make([]string, len(evidenceIDs))
-	for i := range evidenceIDs {
-		// For now, just use index+1. In a full implementation,
-		// we'd maintain a mapping from evidence ID to reference number
-		refs[i] = fmt.Sprintf("[%d]", i+1)
+// fundingText is a competitor's funding as reported, followed by its US dollar
+// equivalent when the original was in another currency
+func fundingText(competitor types.Competitor) string {
+	amount := competitor.FundingNormalized
+	if amount == nil || !amount.Parsed || amount.Currency == "USD" {
+		return competitor.Funding
+	}
+	usd := formatUSD(amount.USD)
+	if amount.USDMin != amount.USDMax {
+		usd = formatUSD(amount.USDMin) + "–" + formatUSD(amount.USDMax)
 	}
+	return fmt.Sprintf("%s (≈ %s)", competitor.Funding, usd)
+}
+
+// formatUSD abbreviates a dollar amount, e.g. $2.5M
+func formatUSD(usd float64) string {
+	units := []struct {
+		size   float64
+		suffix string
+	}{
+		{1e9, "B"},
+		{1e6, "M"},
+		{1e3, "K"},
+	}
+	for _, unit := range units {
+		if usd >= unit.size {
+			return "$" + strings.TrimSuffix(fmt.Sprintf("%.1f", usd/unit.size), ".0") + unit.suffix
+		}
+	}
+	return fmt.Sprintf("$%.0f", usd)
+}
 
-	return strings.Join(refs, ", ")
+// evidenceNumbers maps evidence IDs to their 1-based position in the Sources list
+func evidenceNumbers(evidence []types.Evidence) map[string]int {
+	numbers := make(map[string]int, len(evidence))
+	for i, ev := range evidence {
+		numbers[ev.ID] = i + 1
+	}
+	return numbers
+}
+
+// citationNumbers returns the Sources numbers for the cited evidence, skipping unknown IDs
+func citationNumbers(evidenceIDs []string, numbers map[string]int) []string {
+	var citations []string
+	for _, id := range evidenceIDs {
+		if n, ok := numbers[id]; ok {
+			citations = append(citations, strconv.Itoa(n))
+		}
+	}
+	return citations
+}
+
+// formatEvidenceRefs formats evidence IDs as their Evidence References numbers
+func formatEvidenceRefs(evidenceIDs []string, numbers map[string]int) string {
+	citations := citationNumbers(evidenceIDs, numbers)
+	for i, n := range citations {
+		citations[i] = "[" + n + "]"
+	}
+	return strings.Join(citations, ", ")
 }
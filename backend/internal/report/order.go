@@ -0,0 +1,70 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"rectaify/pkg/types"
+)
+
+// EvidenceOrder decides the order of the Evidence References section, and so
+// the numbers citations use
+type EvidenceOrder string
+
+const (
+	EvidenceOrderQuality EvidenceOrder = "quality" // most credible first
+	EvidenceOrderDate    EvidenceOrder = "date"    // newest first; undated last
+	EvidenceOrderSource  EvidenceOrder = "source"  // highest weighted source type first
+)
+
+// ParseEvidenceOrder parses an order name; empty means quality
+func ParseEvidenceOrder(name string) (EvidenceOrder, error) {
+	switch order := EvidenceOrder(strings.ToLower(strings.TrimSpace(name))); order {
+	case "":
+		return EvidenceOrderQuality, nil
+	case EvidenceOrderQuality, EvidenceOrderDate, EvidenceOrderSource:
+		return order, nil
+	default:
+		return "", fmt.Errorf("unknown evidence order %q", name)
+	}
+}
+
+// EvidenceScorer rates evidence for ordering; evidence.Normalizer implements it
+type EvidenceScorer interface {
+	QualityScore(ev types.Evidence) float64
+	SourceWeight(sourceType string) float64
+}
+
+// orderEvidence returns a sorted copy of evidence. Ties, and every item when
+// no scorer is given for a quality or source order, keep their stored order.
+func orderEvidence(evidence []types.Evidence, order EvidenceOrder, scorer EvidenceScorer) []types.Evidence {
+	ordered := append([]types.Evidence(nil), evidence...)
+
+	switch {
+	case order == EvidenceOrderDate:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			a, b := ordered[i].PublishedAt, ordered[j].PublishedAt
+			if a == nil || b == nil {
+				return a != nil
+			}
+			return a.After(*b)
+		})
+	case scorer == nil:
+		// Nothing to rank by
+	case order == EvidenceOrderSource:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return scorer.SourceWeight(ordered[i].SourceType) > scorer.SourceWeight(ordered[j].SourceType)
+		})
+	default:
+		scores := make(map[string]float64, len(ordered))
+		for _, ev := range ordered {
+			scores[ev.ID] = scorer.QualityScore(ev)
+		}
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return scores[ordered[i].ID] > scores[ordered[j].ID]
+		})
+	}
+
+	return ordered
+}
@@ -0,0 +1,48 @@
+package report
+
+import (
+	"fmt"
+	"html"
+)
+
+// ProductName is the product name shown in reports, CLI help and server logs
+const ProductName = "RectAIfy"
+
+// Theme brands HTML reports, e.g. for an agency white-labeling them for its
+// clients. Fields left empty keep the default theme.
+type Theme struct {
+	PrimaryColor string // hex color of the header and accents, replacing the purple gradient
+	LogoURL      string // image shown above the report title
+	FooterText   string // replaces "Generated by RectAIfy"
+}
+
+// cssVariables declares the theme's colors for the stylesheet. PrimaryColor
+// is validated as a hex color by the config, so it is safe to inline.
+func (t Theme) cssVariables() string {
+	primary, header := "#667eea", "linear-gradient(135deg, #667eea 0%, #764ba2 100%)"
+	if t.PrimaryColor != "" {
+		primary, header = t.PrimaryColor, t.PrimaryColor
+	}
+	return fmt.Sprintf(`
+        :root {
+            --primary: %s;
+            --header-background: %s;
+        }
+`, primary, header)
+}
+
+// logo returns the header logo, or nothing without one
+func (t Theme) logo() string {
+	if t.LogoURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("        <img class=\"logo\" src=\"%s\" alt=\"\">\n", html.EscapeString(t.LogoURL))
+}
+
+// footer returns the footer text
+func (t Theme) footer() string {
+	if t.FooterText == "" {
+		return "Generated by " + ProductName
+	}
+	return t.FooterText
+}
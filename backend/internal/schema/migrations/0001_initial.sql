@@ -0,0 +1,119 @@
+-- RectAIfy Database Schema
+-- PostgreSQL 15+
+--
+-- Baseline migration. It stays idempotent because databases created before
+-- versioned migrations already contain these objects; later changes belong
+-- in new numbered files.
+
+-- Create the main analyses table
+CREATE TABLE IF NOT EXISTS analyses (
+    id TEXT PRIMARY KEY,
+    idea JSONB NOT NULL,
+    result JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Soft-delete marker used by the retention worker
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Denormalized analytics fields; the result blob remains the source of truth
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS category TEXT;
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS overall_score DOUBLE PRECISION;
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS market_score DOUBLE PRECISION;
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS problem_score DOUBLE PRECISION;
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS barrier_score DOUBLE PRECISION;
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS execution_score DOUBLE PRECISION;
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS risk_score DOUBLE PRECISION;
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS graveyard_score DOUBLE PRECISION;
+
+-- Backfill analytics fields for analyses saved before the columns existed
+UPDATE analyses SET
+    category = NULLIF(idea->>'category', ''),
+    overall_score = (result->'verdict'->>'overall_score')::DOUBLE PRECISION,
+    market_score = (result->'verdict'->>'market_score')::DOUBLE PRECISION,
+    problem_score = (result->'verdict'->>'problem_score')::DOUBLE PRECISION,
+    barrier_score = (result->'verdict'->>'barrier_score')::DOUBLE PRECISION,
+    execution_score = (result->'verdict'->>'execution_score')::DOUBLE PRECISION,
+    risk_score = (result->'verdict'->>'risk_score')::DOUBLE PRECISION,
+    graveyard_score = (result->'verdict'->>'graveyard_score')::DOUBLE PRECISION
+WHERE overall_score IS NULL
+  AND jsonb_typeof(result->'verdict'->'overall_score') = 'number';
+
+-- Create the evidence table for research citations
+CREATE TABLE IF NOT EXISTS evidence (
+    id TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    title TEXT NOT NULL,
+    snippet TEXT,
+    published_at TIMESTAMPTZ,
+    retrieved_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    source_type TEXT
+);
+
+-- Create the many-to-many relationship table for analysis-evidence
+CREATE TABLE IF NOT EXISTS analysis_evidence (
+    analysis_id TEXT REFERENCES analyses(id) ON DELETE CASCADE,
+    evidence_id TEXT REFERENCES evidence(id) ON DELETE CASCADE,
+    PRIMARY KEY(analysis_id, evidence_id)
+);
+
+-- Create the web cache table for search results
+CREATE TABLE IF NOT EXISTS web_cache (
+    hash TEXT PRIMARY KEY,
+    query TEXT NOT NULL,
+    result JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ttl_seconds INTEGER NOT NULL DEFAULT 86400
+);
+
+-- Competitors extracted from analysis results for cross-analysis aggregation
+CREATE TABLE IF NOT EXISTS analysis_competitors (
+    analysis_id TEXT REFERENCES analyses(id) ON DELETE CASCADE,
+    normalized_name TEXT NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT,
+    PRIMARY KEY(analysis_id, normalized_name)
+);
+
+-- Backfill competitors for analyses saved before the table existed
+INSERT INTO analysis_competitors (analysis_id, normalized_name, name, category)
+SELECT a.id, LOWER(TRIM(c->>'name')), TRIM(c->>'name'), NULLIF(a.idea->>'category', '')
+FROM analyses a
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(a.result->'market'->'competitors') = 'array'
+         THEN a.result->'market'->'competitors'
+         ELSE '[]'::jsonb END
+) AS c
+WHERE COALESCE(TRIM(c->>'name'), '') <> ''
+ON CONFLICT DO NOTHING;
+
+-- Webhook deliveries that exhausted their retries, kept for inspection and replay
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    event_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY(event_id, url)
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_evidence_url_hash ON evidence(MD5(url));
+CREATE INDEX IF NOT EXISTS idx_analyses_result_gin ON analyses USING GIN (result jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_web_cache_created_at ON web_cache (created_at);
+CREATE INDEX IF NOT EXISTS idx_evidence_retrieved_at ON evidence (retrieved_at);
+CREATE INDEX IF NOT EXISTS idx_analyses_created_at ON analyses (created_at);
+CREATE INDEX IF NOT EXISTS idx_analyses_deleted_at ON analyses (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_analyses_category ON analyses (LOWER(category));
+CREATE INDEX IF NOT EXISTS idx_analysis_competitors_name ON analysis_competitors (normalized_name);
+CREATE INDEX IF NOT EXISTS idx_analysis_competitors_category ON analysis_competitors (LOWER(category));
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_created_at ON webhook_dead_letters (created_at);
+
+-- Create index for cache expiration cleanup
+-- Note: Using a simpler index on created_at since the complex expression isn't IMMUTABLE
+CREATE INDEX IF NOT EXISTS idx_web_cache_expiry ON web_cache (created_at, ttl_seconds);
+
+-- Create extension for better JSON operations if available
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
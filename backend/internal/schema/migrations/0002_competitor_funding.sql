@@ -0,0 +1,17 @@
+-- Competitor funding normalized to US dollars, so funding compares across
+-- analyses regardless of the currency it was reported in
+ALTER TABLE analysis_competitors ADD COLUMN IF NOT EXISTS funding_usd DOUBLE PRECISION;
+
+-- Backfill from stored results that already carry a normalized amount
+UPDATE analysis_competitors ac
+SET funding_usd = (c->'funding_normalized'->>'usd')::DOUBLE PRECISION
+FROM analyses a
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(a.result->'market'->'competitors') = 'array'
+         THEN a.result->'market'->'competitors'
+         ELSE '[]'::jsonb END
+) AS c
+WHERE ac.analysis_id = a.id
+  AND ac.normalized_name = LOWER(TRIM(c->>'name'))
+  AND (c->'funding_normalized'->>'parsed')::BOOLEAN
+  AND ac.funding_usd IS NULL;
@@ -0,0 +1,3 @@
+-- Non-article evidence (pdf, document, video or paywalled), tagged during
+-- normalization; articles and evidence stored before tagging are ''
+ALTER TABLE evidence ADD COLUMN IF NOT EXISTS content_type TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Free-form labels grouping analyses, e.g. by project or screening round;
+-- mirrored from the result blob so listings can filter on them by index
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_analyses_tags ON analyses USING GIN (tags);
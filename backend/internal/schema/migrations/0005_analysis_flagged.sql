@@ -0,0 +1,4 @@
+-- Analyses whose overall score fell below the review gate they were screened
+-- against; mirrored from the result blob so listings can filter on it
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_analyses_flagged ON analyses (flagged) WHERE flagged;
@@ -0,0 +1,59 @@
+-- Risks, barriers and graveyard failure causes extracted from each result, so
+-- insights can aggregate them across a category. Labels are stored trimmed
+-- and lowercased to group on.
+CREATE TABLE IF NOT EXISTS analysis_risks (
+    analysis_id TEXT REFERENCES analyses(id) ON DELETE CASCADE,
+    category TEXT NOT NULL,
+    severity INT,
+    likelihood INT
+);
+
+CREATE TABLE IF NOT EXISTS analysis_barriers (
+    analysis_id TEXT REFERENCES analyses(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    weight DOUBLE PRECISION
+);
+
+CREATE TABLE IF NOT EXISTS analysis_failure_causes (
+    analysis_id TEXT REFERENCES analyses(id) ON DELETE CASCADE,
+    cause TEXT NOT NULL,
+    company_name TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_analysis_risks_analysis_id ON analysis_risks (analysis_id);
+CREATE INDEX IF NOT EXISTS idx_analysis_barriers_analysis_id ON analysis_barriers (analysis_id);
+CREATE INDEX IF NOT EXISTS idx_analysis_failure_causes_analysis_id ON analysis_failure_causes (analysis_id);
+
+-- Backfill from stored results; an analysis with rows already extracted is skipped
+INSERT INTO analysis_risks (analysis_id, category, severity, likelihood)
+SELECT a.id, LOWER(TRIM(r->>'category')), (r->>'severity')::INT, (r->>'likelihood')::INT
+FROM analyses a
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(a.result->'risks'->'risks') = 'array'
+         THEN a.result->'risks'->'risks'
+         ELSE '[]'::jsonb END
+) AS r
+WHERE COALESCE(TRIM(r->>'category'), '') <> ''
+  AND NOT EXISTS (SELECT 1 FROM analysis_risks ar WHERE ar.analysis_id = a.id);
+
+INSERT INTO analysis_barriers (analysis_id, type, weight)
+SELECT a.id, LOWER(TRIM(b->>'type')), (b->>'weight')::DOUBLE PRECISION
+FROM analyses a
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(a.result->'barriers'->'barriers') = 'array'
+         THEN a.result->'barriers'->'barriers'
+         ELSE '[]'::jsonb END
+) AS b
+WHERE COALESCE(TRIM(b->>'type'), '') <> ''
+  AND NOT EXISTS (SELECT 1 FROM analysis_barriers ab WHERE ab.analysis_id = a.id);
+
+INSERT INTO analysis_failure_causes (analysis_id, cause, company_name)
+SELECT a.id, LOWER(TRIM(g->>'failure_cause')), NULLIF(TRIM(g->>'company_name'), '')
+FROM analyses a
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(a.result->'graveyard'->'cases') = 'array'
+         THEN a.result->'graveyard'->'cases'
+         ELSE '[]'::jsonb END
+) AS g
+WHERE COALESCE(TRIM(g->>'failure_cause'), '') <> ''
+  AND NOT EXISTS (SELECT 1 FROM analysis_failure_causes af WHERE af.analysis_id = a.id);
@@ -0,0 +1,35 @@
+-- Audit trail of submitted ideas and the verdicts returned for them. Rows are
+-- chained: each hash covers the row and the hash before it, so an edited or
+-- removed row breaks the chain. The triggers keep the table append-only.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    caller TEXT NOT NULL,
+    action TEXT NOT NULL,
+    analysis_id TEXT,
+    idea_title TEXT NOT NULL,
+    category TEXT,
+    outcome TEXT NOT NULL,
+    verdict TEXT,
+    overall_score DOUBLE PRECISION,
+    error TEXT,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_analysis_id ON audit_log (analysis_id);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_no_change ON audit_log;
+CREATE TRIGGER audit_log_no_change BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,4 @@
+-- Analyses an operator pinned to keep them from the retention job; mirrored
+-- from the result blob so listings can filter on it
+ALTER TABLE analyses ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_analyses_pinned ON analyses (pinned) WHERE pinned;
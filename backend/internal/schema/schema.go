@@ -2,38 +2,165 @@ package schema
 
 import (
 	"context"
-	_ "embed"
+	"embed"
 	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-//go:embed migrations.sql
-var migrationSQL string
+// Migrations are numbered SQL files, e.g. 0002_add_language.sql, applied in
+// order and recorded in schema_migrations so each runs exactly once. Add a
+// new file for every schema change rather than editing an applied one.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
 
-// Migrate runs database migrations
+// migrationLockID is the Postgres advisory lock held while migrating, so
+// replicas starting together apply each migration once
+const migrationLockID int64 = 0x52454354414946 // "RECTAIF"
+
+// migrationNameRe matches migration file names: a version number, an underscore and a name
+var migrationNameRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+
+// Migration is one numbered schema change
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// Migrations returns the embedded migrations in version order
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		match := migrationNameRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %q and %q share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		sql, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: match[2], SQL: string(sql)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrate applies every migration not yet recorded in schema_migrations, each
+// in its own transaction. An advisory lock serializes concurrent callers.
 func Migrate(ctx context.Context, db *pgxpool.Pool) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
 	conn, err := db.Acquire(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to acquire connection: %w", err)
 	}
 	defer conn.Release()
 
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// The lock belongs to the session, so a connection that can't release
+		// it must not go back to the pool
+		unlockCtx := context.WithoutCancel(ctx)
+		if _, err := conn.Exec(unlockCtx, "SELECT pg_advisory_unlock($1)", migrationLockID); err != nil {
+			conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if applied[migration.Version] {
+			continue
+		}
+		if err := applyMigration(ctx, conn, migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appliedVersions returns the versions recorded in schema_migrations
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int]bool, error) {
+	rows, err := conn.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// applyMigration runs one migration and records it in the same transaction
+func applyMigration(ctx context.Context, conn *pgxpool.Conn, migration Migration) error {
 	tx, err := conn.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	// Execute migration SQL
-	_, err = tx.Exec(ctx, migrationSQL)
+	if _, err := tx.Exec(ctx, migration.SQL); err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		migration.Version, migration.Name)
 	if err != nil {
-		return fmt.Errorf("failed to execute migrations: %w", err)
+		return fmt.Errorf("failed to record migration %d_%s: %w", migration.Version, migration.Name, err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit migrations: %w", err)
+		return fmt.Errorf("failed to commit migration %d_%s: %w", migration.Version, migration.Name, err)
 	}
 
 	return nil
@@ -64,6 +191,46 @@ func InitDatabase(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	return db, nil
 }
 
+// maxConnectDelay caps the wait between startup connection attempts
+const maxConnectDelay = 30 * time.Second
+
+// ConnectAndMigrate initializes the database and applies pending migrations,
+// retrying both up to attempts times so a database that is still starting
+// doesn't crash the service. The wait starts at delay and doubles after each
+// failure. An unparseable DSN fails immediately.
+func ConnectAndMigrate(ctx context.Context, dsn string, attempts int, delay time.Duration) (*pgxpool.Pool, error) {
+	if _, err := pgxpool.ParseConfig(dsn); err != nil {
+		return nil, fmt.Errorf("failed to parse database config: %w", err)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var db *pgxpool.Pool
+		db, err = InitDatabase(ctx, dsn)
+		if err == nil {
+			if err = Migrate(ctx, db); err == nil {
+				return db, nil
+			}
+			err = fmt.Errorf("failed to run migrations: %w", err)
+			db.Close()
+		}
+
+		if attempt >= attempts {
+			break
+		}
+		log.Printf("Database attempt %d/%d failed: %v; retrying in %s", attempt, attempts, err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		delay = min(delay*2, maxConnectDelay)
+	}
+
+	return nil, fmt.Errorf("database unavailable after %d attempts: %w", attempts, err)
+}
+
 // CreateDatabase creates the database if it doesn't exist
 func CreateDatabase(ctx context.Context, dsn, dbName string) error {
 	// Parse DSN to connect to postgres database instead of target database
@@ -1,6 +1,7 @@
 package score
 
 import (
+	"fmt"
 	"math"
 	"strings"
 
@@ -9,7 +10,8 @@ import (
 
 // Calculator computes viability scores based on analysis results
 type Calculator struct {
-	weights ScoreWeights
+	weights   ScoreWeights
+	graveyard GraveyardBaselines
 }
 
 // ScoreWeights defines the relative importance of each scoring dimension
@@ -34,13 +36,40 @@ func DefaultWeights() ScoreWeights {
 	}
 }
 
-// NewCalculator creates a new score calculator
-func NewCalculator(weights *ScoreWeights) *Calculator {
+// GraveyardBaselines are the graveyard scores given when no failure cases are
+// found. Finding none after the postmortem searches came back with results
+// says more about an idea than finding none because nothing was searched.
+type GraveyardBaselines struct {
+	NoFailures float64 `json:"no_failures"` // every postmortem query returned results
+	Unsearched float64 `json:"unsearched"`  // no postmortem query returned results
+}
+
+// DefaultGraveyardBaselines returns the default no-failure graveyard scores,
+// both the 60 an empty graveyard has always scored, so existing scores don't
+// shift until an operator sets them apart
+func DefaultGraveyardBaselines() GraveyardBaselines {
+	return GraveyardBaselines{
+		NoFailures: 60.0,
+		Unsearched: 60.0,
+	}
+}
+
+// NewCalculator creates a new score calculator. Nil weights or baselines use the defaults.
+func NewCalculator(weights *ScoreWeights, baselines *GraveyardBaselines) *Calculator {
 	if weights == nil {
 		defaultWeights := DefaultWeights()
 		weights = &defaultWeights
 	}
-	return &Calculator{weights: *weights}
+	if baselines == nil {
+		defaultBaselines := DefaultGraveyardBaselines()
+		baselines = &defaultBaselines
+	}
+	return &Calculator{weights: *weights, graveyard: *baselines}
+}
+
+// Weights returns the weights used by this calculator
+func (c *Calculator) Weights() ScoreWeights {
+	return c.weights
 }
 
 // ComputeViability calculates the overall viability score
@@ -50,7 +79,7 @@ func (c *Calculator) ComputeViability(analysis types.Analysis) types.Viability {
 	barrierScore := c.computeBarrierScore(analysis.Barriers)
 	executionScore := c.computeExecutionScore(analysis.Execution)
 	riskScore := c.computeRiskScore(analysis.Risks)
-	graveyardScore := c.computeGraveyardScore(analysis.Graveyard)
+	graveyardScore, graveyardRationale := c.computeGraveyardScore(analysis.Graveyard)
 
 	// Calculate weighted overall score
 	overallScore := (marketScore * c.weights.Market) +
@@ -78,8 +107,11 @@ func (c *Calculator) ComputeViability(analysis types.Analysis) types.Viability {
 		RiskScore:       riskScore,
 		GraveyardScore:  graveyardScore,
 		Recommendation:  recommendation,
+		Verdict:         VerdictForScore(overallScore),
 		KeyInsights:     keyInsights,
 		EvidenceIDs:     evidenceIDs,
+		Confidence:      1.0,
+		ScoreRationale:  map[string]string{"graveyard": graveyardRationale},
 	}
 }
 
@@ -293,10 +325,10 @@ func (c *Calculator) computeRiskScore(risks types.RiskAnalysis) float64 {
 	return math.Max(0, math.Min(100, score))
 }
 
-// computeGraveyardScore calculates learning from failures score
-func (c *Calculator) computeGraveyardScore(graveyard types.GraveyardAnalysis) float64 {
+// computeGraveyardScore calculates learning from failures score and explains how it was reached
+func (c *Calculator) computeGraveyardScore(graveyard types.GraveyardAnalysis) (float64, string) {
 	if len(graveyard.Cases) == 0 {
-		return 60.0 // No failure cases found - could be good or bad
+		return c.noFailuresScore(graveyard.Search)
 	}
 
 	score := 40.0 // Start lower when failures exist
@@ -325,71 +357,111 @@ func (c *Calculator) computeGraveyardScore(graveyard types.GraveyardAnalysis) fl
 	evidenceBonus := math.Min(10.0, float64(len(graveyard.EvidenceIDs))*2.0)
 	score += evidenceBonus
 
-	return math.Max(0, math.Min(100, score))
+	rationale := fmt.Sprintf("%d failure case(s) found among similar companies; each lowers the score by its cause, softened where lessons are documented.", len(graveyard.Cases))
+	return math.Max(0, math.Min(100, score)), rationale
+}
+
+// noFailuresScore scores an empty graveyard between the unsearched and
+// no-failures baselines by the share of postmortem queries that returned
+// results, so "looked and found none" outscores "never looked"
+func (c *Calculator) noFailuresScore(search *types.PostmortemSearch) (float64, string) {
+	noFailures, unsearched := c.graveyard.NoFailures, c.graveyard.Unsearched
+
+	if search == nil {
+		// Analyses from before search coverage was recorded
+		return (noFailures + unsearched) / 2, "No failure cases found; postmortem search coverage was not recorded, so the score sits midway between the searched and unsearched baselines."
+	}
+	if search.Queries == 0 || search.Answered == 0 {
+		return unsearched, fmt.Sprintf("No failure cases found, but none of the %d postmortem searches returned results, so the absence of failures is not evidence either way.", search.Queries)
+	}
+
+	coverage := float64(search.Answered) / float64(search.Queries)
+	score := unsearched + (noFailures-unsearched)*coverage
+	rationale := fmt.Sprintf("No failure cases found although %d of %d postmortem searches returned results (%d results); scored by search coverage between %.0f (unsearched) and %.0f (no failures).",
+		search.Answered, search.Queries, search.Results, unsearched, noFailures)
+	return math.Max(0, math.Min(100, score)), rationale
+}
+
+// VerdictForScore maps an overall score to its verdict band
+func VerdictForScore(overall float64) types.VerdictTier {
+	switch {
+	case overall >= 75:
+		return types.VerdictStrongGo
+	case overall >= 60:
+		return types.VerdictGo
+	case overall >= 45:
+		return types.VerdictCaution
+	case overall >= 30:
+		return types.VerdictHighRisk
+	default:
+		return types.VerdictNoGo
+	}
 }
 
 // generateRecommendation creates a recommendation based on scores
 func (c *Calculator) generateRecommendation(overall, market, problem, barrier, execution, risk, graveyard float64) string {
-	if overall >= 75 {
+	switch VerdictForScore(overall) {
+	case types.VerdictStrongGo:
 		return "STRONG GO: High viability with favorable conditions across multiple dimensions."
-	} else if overall >= 60 {
+	case types.VerdictGo:
 		return "GO: Good viability with some areas requiring attention."
-	} else if overall >= 45 {
+	case types.VerdictCaution:
 		return "CAUTION: Mixed signals - proceed with careful validation and risk mitigation."
-	} else if overall >= 30 {
+	case types.VerdictHighRisk:
 		return "HIGH RISK: Significant challenges identified - major pivots likely needed."
-	} else {
+	default:
 		return "NO GO: Multiple severe challenges make success highly unlikely."
 	}
 }
 
-// generateKeyInsights extracts key insights from the scoring analysis
-func (c *Calculator) generateKeyInsights(analysis types.Analysis, market, problem, barrier, execution, risk, graveyard float64) []string {
-	var insights []string
+// generateKeyInsights extracts key insights from the scoring analysis, citing
+// the evidence behind the dimension each insight is drawn from
+func (c *Calculator) generateKeyInsights(analysis types.Analysis, market, problem, barrier, execution, risk, graveyard float64) []types.KeyInsight {
+	var insights []types.KeyInsight
 
 	// Market insights
 	if market >= 80 {
-		insights = append(insights, "Strong market opportunity with favorable competitive dynamics")
+		insights = append(insights, types.KeyInsight{Text: "Strong market opportunity with favorable competitive dynamics", EvidenceIDs: analysis.Market.EvidenceIDs})
 	} else if market <= 30 {
-		insights = append(insights, "Challenging market conditions with intense competition or declining demand")
+		insights = append(insights, types.KeyInsight{Text: "Challenging market conditions with intense competition or declining demand", EvidenceIDs: analysis.Market.EvidenceIDs})
 	}
 
 	// Problem insights
 	if problem >= 80 {
-		insights = append(insights, "Well-validated problem with clear pain points")
+		insights = append(insights, types.KeyInsight{Text: "Well-validated problem with clear pain points", EvidenceIDs: analysis.Problem.EvidenceIDs})
 	} else if problem <= 40 {
-		insights = append(insights, "Problem validation is weak - more research needed")
+		insights = append(insights, types.KeyInsight{Text: "Problem validation is weak - more research needed", EvidenceIDs: analysis.Problem.EvidenceIDs})
 	}
 
 	// Barrier insights
 	if barrier <= 40 {
-		insights = append(insights, "Significant execution barriers identified")
+		insights = append(insights, types.KeyInsight{Text: "Significant execution barriers identified", EvidenceIDs: analysis.Barriers.EvidenceIDs})
 	} else if barrier >= 80 {
-		insights = append(insights, "Clear path to execution with minimal barriers")
+		insights = append(insights, types.KeyInsight{Text: "Clear path to execution with minimal barriers", EvidenceIDs: analysis.Barriers.EvidenceIDs})
 	}
 
 	// Execution insights
 	if execution <= 40 {
-		insights = append(insights, "High execution complexity requiring substantial resources")
+		insights = append(insights, types.KeyInsight{Text: "High execution complexity requiring substantial resources", EvidenceIDs: analysis.Execution.EvidenceIDs})
 	} else if execution >= 80 {
-		insights = append(insights, "Manageable execution complexity with available resources")
+		insights = append(insights, types.KeyInsight{Text: "Manageable execution complexity with available resources", EvidenceIDs: analysis.Execution.EvidenceIDs})
 	}
 
 	// Risk insights
 	if risk <= 40 {
-		insights = append(insights, "High business risks requiring careful mitigation")
+		insights = append(insights, types.KeyInsight{Text: "High business risks requiring careful mitigation", EvidenceIDs: analysis.Risks.EvidenceIDs})
 	} else if risk >= 80 {
-		insights = append(insights, "Well-managed risk profile")
+		insights = append(insights, types.KeyInsight{Text: "Well-managed risk profile", EvidenceIDs: analysis.Risks.EvidenceIDs})
 	}
 
 	// Graveyard insights
 	if graveyard <= 40 && len(analysis.Graveyard.Cases) > 0 {
-		insights = append(insights, "Multiple similar ventures have failed - learn from their mistakes")
+		insights = append(insights, types.KeyInsight{Text: "Multiple similar ventures have failed - learn from their mistakes", EvidenceIDs: analysis.Graveyard.EvidenceIDs})
 	}
 
 	// Ensure we have at least one insight
 	if len(insights) == 0 {
-		insights = append(insights, "Further research recommended to validate assumptions")
+		insights = append(insights, types.KeyInsight{Text: "Further research recommended to validate assumptions"})
 	}
 
 	return insights
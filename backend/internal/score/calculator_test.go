@@ -0,0 +1,279 @@
+package score
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func competitors(n int) []types.Competitor {
+	found := make([]types.Competitor, n)
+	for i := range found {
+		found[i].Name = "competitor"
+	}
+	return found
+}
+
+func ids(n int) []string {
+	return make([]string, n)
+}
+
+func assertScore(t *testing.T, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("got score %g, want %g", got, want)
+	}
+}
+
+func TestComputeMarketScore(t *testing.T) {
+	longPositioning := strings.Repeat("x", 51)
+	tests := []struct {
+		name   string
+		market types.MarketAnalysis
+		want   float64
+	}{
+		{"empty", types.MarketAnalysis{}, 65},
+		{"unknown stage keeps the base", types.MarketAnalysis{MarketStage: "nascent", Competitors: competitors(1)}, 55},
+		{"early", types.MarketAnalysis{MarketStage: "early", Competitors: competitors(1)}, 90},
+		{"growing", types.MarketAnalysis{MarketStage: "growing", Competitors: competitors(1)}, 75},
+		{"mature", types.MarketAnalysis{MarketStage: "mature", Competitors: competitors(1)}, 45},
+		{"declining", types.MarketAnalysis{MarketStage: "declining", Competitors: competitors(1)}, 20},
+		{"no competitors", types.MarketAnalysis{MarketStage: "mature"}, 55},
+		{"two competitors", types.MarketAnalysis{MarketStage: "mature", Competitors: competitors(2)}, 45},
+		{"three competitors", types.MarketAnalysis{MarketStage: "mature", Competitors: competitors(3)}, 35},
+		{"five competitors", types.MarketAnalysis{MarketStage: "mature", Competitors: competitors(5)}, 35},
+		{"six competitors", types.MarketAnalysis{MarketStage: "mature", Competitors: competitors(6)}, 25},
+		{"max competitors in a declining market", types.MarketAnalysis{MarketStage: "declining", Competitors: competitors(100)}, 0},
+		{"short positioning", types.MarketAnalysis{MarketStage: "mature", Competitors: competitors(1), Positioning: "cheaper"}, 45},
+		{"long positioning", types.MarketAnalysis{MarketStage: "mature", Competitors: competitors(1), Positioning: longPositioning}, 50},
+		{"evidence bonus is capped", types.MarketAnalysis{MarketStage: "mature", Competitors: competitors(1), EvidenceIDs: ids(20)}, 55},
+		{"clamped to 100", types.MarketAnalysis{MarketStage: "early", Positioning: longPositioning, EvidenceIDs: ids(5)}, 100},
+	}
+
+	calculator := NewCalculator(nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertScore(t, calculator.computeMarketScore(tt.market), tt.want)
+		})
+	}
+}
+
+func TestComputeProblemScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem types.ProblemAnalysis
+		want    float64
+	}{
+		{"empty", types.ProblemAnalysis{}, 30},
+		{"one pain point", types.ProblemAnalysis{PainPoints: []string{"a"}}, 40},
+		{"two pain points", types.ProblemAnalysis{PainPoints: []string{"a", "b"}}, 45},
+		{"three pain points", types.ProblemAnalysis{PainPoints: []string{"a", "b", "c"}}, 55},
+		{"some validation", types.ProblemAnalysis{Validation: strings.Repeat("x", 51)}, 40},
+		{"strong validation", types.ProblemAnalysis{Validation: strings.Repeat("x", 101)}, 50},
+		{"evidence bonus is capped", types.ProblemAnalysis{EvidenceIDs: ids(10)}, 45},
+		{"everything", types.ProblemAnalysis{PainPoints: []string{"a", "b", "c"}, Validation: strings.Repeat("x", 101), EvidenceIDs: ids(5)}, 90},
+	}
+
+	calculator := NewCalculator(nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertScore(t, calculator.computeProblemScore(tt.problem), tt.want)
+		})
+	}
+}
+
+func TestComputeBarrierScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		barriers types.BarrierAnalysis
+		want     float64
+	}{
+		{"no barriers", types.BarrierAnalysis{}, 85},
+		{"only weightless barriers", types.BarrierAnalysis{Barriers: []types.Barrier{{Type: "regulation"}}}, 85},
+		// Higher-impact barriers score lower
+		{"regulation", types.BarrierAnalysis{Barriers: []types.Barrier{{Type: "regulation", Weight: 1}}}, 15},
+		{"supply", types.BarrierAnalysis{Barriers: []types.Barrier{{Type: "supply", Weight: 1}}}, 30},
+		{"distribution", types.BarrierAnalysis{Barriers: []types.Barrier{{Type: "distribution", Weight: 1}}}, 40},
+		{"trust", types.BarrierAnalysis{Barriers: []types.Barrier{{Type: "trust", Weight: 1}}}, 50},
+		{"tech", types.BarrierAnalysis{Barriers: []types.Barrier{{Type: "tech", Weight: 1}}}, 60},
+		{"unknown type is moderate", types.BarrierAnalysis{Barriers: []types.Barrier{{Type: "weather", Weight: 1}}}, 50},
+		{"weighted average of impacts", types.BarrierAnalysis{Barriers: []types.Barrier{
+			{Type: "regulation", Weight: 0.75},
+			{Type: "tech", Weight: 0.25},
+		}}, 100 - (0.75*85 + 0.25*40)},
+		{"many barriers", types.BarrierAnalysis{Barriers: []types.Barrier{
+			{Type: "regulation", Weight: 1},
+			{Type: "supply", Weight: 1},
+			{Type: "distribution", Weight: 1},
+			{Type: "trust", Weight: 1},
+			{Type: "tech", Weight: 1},
+		}}, 100 - (85+70+60+50+40)/5.0},
+		{"evidence of barriers lowers the score, capped", types.BarrierAnalysis{
+			Barriers:    []types.Barrier{{Type: "tech", Weight: 1}},
+			EvidenceIDs: ids(9),
+		}, 55},
+	}
+
+	calculator := NewCalculator(nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertScore(t, calculator.computeBarrierScore(tt.barriers), tt.want)
+		})
+	}
+}
+
+func TestComputeExecutionScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		execution types.ExecutionAnalysis
+		want      float64
+	}{
+		{"empty", types.ExecutionAnalysis{}, 70},
+		{"low capital", types.ExecutionAnalysis{CapitalRequirement: "low"}, 80},
+		{"very high capital", types.ExecutionAnalysis{CapitalRequirement: "very high"}, 40},
+		{"rare talent", types.ExecutionAnalysis{TalentRarity: "rare"}, 47.5},
+		{"capital then talent", types.ExecutionAnalysis{CapitalRequirement: "low", TalentRarity: "common"}, 82.5},
+		{"integration penalty is capped", types.ExecutionAnalysis{IntegrationCount: 50}, 40},
+		{"complexity", types.ExecutionAnalysis{Complexity: 0.5}, 60},
+		{"evidence bonus is capped", types.ExecutionAnalysis{EvidenceIDs: ids(10)}, 75},
+		{"hardest inputs", types.ExecutionAnalysis{CapitalRequirement: "very high", TalentRarity: "rare", IntegrationCount: 10, Complexity: 1}, 1.25},
+	}
+
+	calculator := NewCalculator(nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertScore(t, calculator.computeExecutionScore(tt.execution), tt.want)
+		})
+	}
+}
+
+func TestComputeRiskScore(t *testing.T) {
+	mitigation := strings.Repeat("x", 21)
+	tests := []struct {
+		name  string
+		risks types.RiskAnalysis
+		want  float64
+	}{
+		{"no risks", types.RiskAnalysis{}, 80},
+		{"lowest severity and likelihood", types.RiskAnalysis{Risks: []types.Risk{{Severity: 1, Likelihood: 1}}}, 100 - 20.0/25},
+		{"highest severity and likelihood", types.RiskAnalysis{Risks: []types.Risk{{Severity: 5, Likelihood: 5}}}, 80},
+		{"severe but unlikely", types.RiskAnalysis{Risks: []types.Risk{{Severity: 5, Likelihood: 1}}}, 96},
+		{"short mitigation earns nothing", types.RiskAnalysis{Risks: []types.Risk{{Severity: 5, Likelihood: 5, Mitigation: "insure"}}}, 80},
+		{"mitigation bonus", types.RiskAnalysis{Risks: []types.Risk{{Severity: 5, Likelihood: 5, Mitigation: mitigation}}}, 83},
+		{"evidence bonus is capped", types.RiskAnalysis{Risks: []types.Risk{{Severity: 5, Likelihood: 5}}, EvidenceIDs: ids(10)}, 85},
+		{"many maximal risks clamp to 0", types.RiskAnalysis{Risks: []types.Risk{
+			{Severity: 5, Likelihood: 5}, {Severity: 5, Likelihood: 5}, {Severity: 5, Likelihood: 5},
+			{Severity: 5, Likelihood: 5}, {Severity: 5, Likelihood: 5}, {Severity: 5, Likelihood: 5},
+		}}, 0},
+		{"clamped to 100", types.RiskAnalysis{Risks: []types.Risk{{Severity: 1, Likelihood: 1, Mitigation: mitigation}}, EvidenceIDs: ids(5)}, 100},
+	}
+
+	calculator := NewCalculator(nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertScore(t, calculator.computeRiskScore(tt.risks), tt.want)
+		})
+	}
+}
+
+func TestComputeGraveyardScore(t *testing.T) {
+	lessons := strings.Repeat("x", 31)
+	tests := []struct {
+		name      string
+		graveyard types.GraveyardAnalysis
+		want      float64
+	}{
+		{"one case without lessons", types.GraveyardAnalysis{Cases: []types.GraveyardCase{{FailureCause: "unknown"}}}, 30},
+		{"one case with lessons", types.GraveyardAnalysis{Cases: []types.GraveyardCase{{FailureCause: "unknown", Lessons: lessons}}}, 35},
+		{"short lessons earn nothing", types.GraveyardAnalysis{Cases: []types.GraveyardCase{{FailureCause: "unknown", Lessons: "ran out"}}}, 30},
+		{"funding failure", types.GraveyardAnalysis{Cases: []types.GraveyardCase{{FailureCause: "Ran out of money"}}}, 25},
+		{"market failure", types.GraveyardAnalysis{Cases: []types.GraveyardCase{{FailureCause: "No demand"}}}, 22},
+		{"execution failure", types.GraveyardAnalysis{Cases: []types.GraveyardCase{{FailureCause: "Team split up"}}}, 27},
+		{"evidence bonus is capped", types.GraveyardAnalysis{Cases: []types.GraveyardCase{{FailureCause: "unknown"}}, EvidenceIDs: ids(10)}, 40},
+		{"many cases clamp to 0", types.GraveyardAnalysis{Cases: []types.GraveyardCase{
+			{FailureCause: "demand"}, {FailureCause: "demand"}, {FailureCause: "demand"},
+		}}, 0},
+	}
+
+	calculator := NewCalculator(nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, rationale := calculator.computeGraveyardScore(tt.graveyard)
+			assertScore(t, score, tt.want)
+			if rationale == "" {
+				t.Error("no rationale given")
+			}
+		})
+	}
+}
+
+func TestNoFailuresScore(t *testing.T) {
+	calculator := NewCalculator(nil, &GraveyardBaselines{NoFailures: 80, Unsearched: 40})
+	tests := []struct {
+		name   string
+		search *types.PostmortemSearch
+		want   float64
+	}{
+		{"coverage not recorded", nil, 60},
+		{"nothing searched", &types.PostmortemSearch{}, 40},
+		{"no search answered", &types.PostmortemSearch{Queries: 4}, 40},
+		{"half the searches answered", &types.PostmortemSearch{Queries: 4, Answered: 2, Results: 7}, 60},
+		{"every search answered", &types.PostmortemSearch{Queries: 4, Answered: 4, Results: 20}, 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, _ := calculator.computeGraveyardScore(types.GraveyardAnalysis{Search: tt.search})
+			assertScore(t, score, tt.want)
+		})
+	}
+
+	// The defaults score an empty graveyard 60 however it was searched
+	defaults := NewCalculator(nil, nil)
+	for _, search := range []*types.PostmortemSearch{nil, {Queries: 3}, {Queries: 3, Answered: 3}} {
+		score, _ := defaults.computeGraveyardScore(types.GraveyardAnalysis{Search: search})
+		assertScore(t, score, 60)
+	}
+}
+
+func TestComputeViability(t *testing.T) {
+	t.Run("empty analysis", func(t *testing.T) {
+		viability := NewCalculator(nil, nil).ComputeViability(types.Analysis{})
+
+		assertScore(t, viability.MarketScore, 65)
+		assertScore(t, viability.ProblemScore, 30)
+		assertScore(t, viability.BarrierScore, 85)
+		assertScore(t, viability.ExecutionScore, 70)
+		assertScore(t, viability.RiskScore, 80)
+		assertScore(t, viability.GraveyardScore, 60)
+		assertScore(t, viability.OverallScore, 65*0.25+30*0.20+85*0.15+70*0.15+80*0.15+60*0.10)
+		if viability.Verdict != VerdictForScore(viability.OverallScore) {
+			t.Errorf("got verdict %s for overall %g", viability.Verdict, viability.OverallScore)
+		}
+	})
+
+	t.Run("default weights sum to one", func(t *testing.T) {
+		weights := DefaultWeights()
+		sum := weights.Market + weights.Problem + weights.Barriers + weights.Execution + weights.Risks + weights.Graveyard
+		assertScore(t, sum, 1)
+	})
+
+	t.Run("weights apply as given", func(t *testing.T) {
+		// Only the market counts, so the overall score is the market score
+		calculator := NewCalculator(&ScoreWeights{Market: 1}, nil)
+		viability := calculator.ComputeViability(types.Analysis{})
+		assertScore(t, viability.OverallScore, viability.MarketScore)
+	})
+
+	t.Run("weights summing past one clamp to 100", func(t *testing.T) {
+		calculator := NewCalculator(&ScoreWeights{Market: 2, Problem: 2, Barriers: 2, Execution: 2, Risks: 2, Graveyard: 2}, nil)
+		assertScore(t, calculator.ComputeViability(types.Analysis{}).OverallScore, 100)
+	})
+
+	t.Run("zero weights", func(t *testing.T) {
+		calculator := NewCalculator(&ScoreWeights{}, nil)
+		assertScore(t, calculator.ComputeViability(types.Analysis{}).OverallScore, 0)
+	})
+}
@@ -0,0 +1,204 @@
+package score
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"rectaify/pkg/types"
+)
+
+// DefaultSensitivityDelta is how many points each dimension is moved up and
+// down when testing how robust an overall score is
+const DefaultSensitivityDelta = 10.0
+
+// verdictBoundaries are the lowest overall scores of each verdict band above
+// no-go, matching VerdictForScore
+var verdictBoundaries = []float64{30, 45, 60, 75}
+
+// WeightProfile is a named alternative weighting of the scoring dimensions
+type WeightProfile struct {
+	Name    string
+	Weights ScoreWeights
+}
+
+// WeightProfiles returns the weightings an overall score is compared under:
+// investors care most about market size, bootstrappers about a validated
+// problem they can execute on without capital
+func WeightProfiles() []WeightProfile {
+	return []WeightProfile{
+		{Name: "default", Weights: DefaultWeights()},
+		{Name: "investor", Weights: ScoreWeights{
+			Market:    0.35,
+			Problem:   0.20,
+			Barriers:  0.10,
+			Execution: 0.10,
+			Risks:     0.15,
+			Graveyard: 0.10,
+		}},
+		{Name: "bootstrapper", Weights: ScoreWeights{
+			Market:    0.15,
+			Problem:   0.25,
+			Barriers:  0.20,
+			Execution: 0.20,
+			Risks:     0.15,
+			Graveyard: 0.05,
+		}},
+	}
+}
+
+// WeightsFromMeta extracts the score weights recorded in analysis meta
+func WeightsFromMeta(meta json.RawMessage) (ScoreWeights, bool) {
+	if len(meta) == 0 {
+		return ScoreWeights{}, false
+	}
+
+	var parsed struct {
+		ScoreWeights *ScoreWeights `json:"score_weights"`
+	}
+	if err := json.Unmarshal(meta, &parsed); err != nil || parsed.ScoreWeights == nil {
+		return ScoreWeights{}, false
+	}
+	return *parsed.ScoreWeights, true
+}
+
+// dimensionScore is one dimension's score and weight
+type dimensionScore struct {
+	name   string
+	score  float64
+	weight float64
+}
+
+// dimensionScores pairs a verdict's dimension scores with weights
+func dimensionScores(verdict types.Viability, weights ScoreWeights) []dimensionScore {
+	return []dimensionScore{
+		{"market", verdict.MarketScore, weights.Market},
+		{"problem", verdict.ProblemScore, weights.Problem},
+		{"barriers", verdict.BarrierScore, weights.Barriers},
+		{"execution", verdict.ExecutionScore, weights.Execution},
+		{"risks", verdict.RiskScore, weights.Risks},
+		{"graveyard", verdict.GraveyardScore, weights.Graveyard},
+	}
+}
+
+// weightedScore is the bounded weighted sum of dimension scores
+func weightedScore(dimensions []dimensionScore) float64 {
+	var overall float64
+	for _, d := range dimensions {
+		overall += d.score * d.weight
+	}
+	return math.Max(0, math.Min(100, overall))
+}
+
+// Sensitivity recomputes a verdict's overall score with each dimension moved
+// delta points up and down, and under each weight profile. A dimension is
+// pivotal when such a move changes the verdict band. Only stored dimension
+// scores are used, so nothing is re-analyzed.
+func (c *Calculator) Sensitivity(verdict types.Viability, delta float64) types.Sensitivity {
+	if delta <= 0 {
+		delta = DefaultSensitivityDelta
+	}
+
+	dimensions := dimensionScores(verdict, c.weights)
+	overall := weightedScore(dimensions)
+	tier := VerdictForScore(overall)
+
+	result := types.Sensitivity{
+		OverallScore: round1(overall),
+		Verdict:      tier,
+		Delta:        delta,
+		ScoreMin:     overall,
+		ScoreMax:     overall,
+		Pivotal:      []string{},
+	}
+
+	for i, d := range dimensions {
+		perturbed := make([]dimensionScore, len(dimensions))
+
+		copy(perturbed, dimensions)
+		perturbed[i].score = math.Max(0, d.score-delta)
+		low := weightedScore(perturbed)
+
+		perturbed[i].score = math.Min(100, d.score+delta)
+		high := weightedScore(perturbed)
+
+		flip := pointsToFlip(overall, d)
+		dimension := types.DimensionSensitivity{
+			Dimension:    d.name,
+			Score:        d.score,
+			Weight:       d.weight,
+			LowScore:     round1(low),
+			HighScore:    round1(high),
+			LowVerdict:   VerdictForScore(low),
+			HighVerdict:  VerdictForScore(high),
+			PointsToFlip: round1(flip),
+			Pivotal:      flip > 0 && flip <= delta,
+		}
+		result.Dimensions = append(result.Dimensions, dimension)
+		if dimension.Pivotal {
+			result.Pivotal = append(result.Pivotal, d.name)
+		}
+
+		result.ScoreMin = math.Min(result.ScoreMin, low)
+		result.ScoreMax = math.Max(result.ScoreMax, high)
+	}
+
+	// Dimensions closest to flipping the verdict first; ones that can't flip it last
+	sort.SliceStable(result.Dimensions, func(i, j int) bool {
+		a, b := result.Dimensions[i].PointsToFlip, result.Dimensions[j].PointsToFlip
+		if (a == 0) != (b == 0) {
+			return b == 0
+		}
+		return a < b
+	})
+
+	for _, profile := range WeightProfiles() {
+		profileScore := weightedScore(dimensionScores(verdict, profile.Weights))
+		result.Profiles = append(result.Profiles, types.ProfileScore{
+			Profile:      profile.Name,
+			OverallScore: round1(profileScore),
+			Verdict:      VerdictForScore(profileScore),
+		})
+		result.ScoreMin = math.Min(result.ScoreMin, profileScore)
+		result.ScoreMax = math.Max(result.ScoreMax, profileScore)
+	}
+
+	result.ScoreMin = round1(result.ScoreMin)
+	result.ScoreMax = round1(result.ScoreMax)
+	return result
+}
+
+// pointsToFlip is the smallest move of one dimension that takes the overall
+// score into another verdict band, or 0 when no score from 0 to 100 does
+func pointsToFlip(overall float64, d dimensionScore) float64 {
+	if d.weight <= 0 {
+		return 0
+	}
+
+	best := 0.0
+	for _, boundary := range verdictBoundaries {
+		var move float64
+		if boundary > overall {
+			// Raise the dimension until the overall score reaches the band above
+			move = (boundary - overall) / d.weight
+			if d.score+move > 100 {
+				continue
+			}
+		} else {
+			// Lower it until the overall score drops below the band's floor
+			move = (overall-boundary)/d.weight + 0.1
+			if d.score-move < 0 {
+				continue
+			}
+		}
+		if best == 0 || move < best {
+			best = move
+		}
+	}
+	return best
+}
+
+// round1 rounds to one decimal place
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,68 @@
+package scrub
+
+import (
+	"regexp"
+	"strings"
+
+	"rectaify/pkg/types"
+)
+
+var (
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// Phone numbers start with a + country code or a parenthesized area
+	// code, or else group their digits 3-3-4 or 3-4-4. Thousands-grouped
+	// amounts ("12.500.000"), IP addresses, years and dates never end in a
+	// four-digit group, so funding figures survive scrubbing.
+	phoneRe = regexp.MustCompile(`\+\d{1,3}(?:[\s.-]?\(\d{1,4}\))?(?:[\s.-]?\d{2,4}){2,4}\b` +
+		`|\(\d{2,4}\)[\s.-]?\d{3,4}[\s.-]\d{4}\b` +
+		`|\b\d{3}[\s.-]\d{3,4}[\s.-]\d{4}\b`)
+)
+
+const (
+	emailPlaceholder    = "[email redacted]"
+	phonePlaceholder    = "[phone redacted]"
+	wordlistPlaceholder = "[redacted]"
+)
+
+// Scrubber redacts emails, phone numbers and wordlist terms from text before
+// it is persisted. A nil Scrubber leaves text unchanged.
+type Scrubber struct {
+	wordRe *regexp.Regexp
+}
+
+// NewScrubber creates a scrubber; words are matched case-insensitively as whole words
+func NewScrubber(words []string) *Scrubber {
+	var quoted []string
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+
+	s := &Scrubber{}
+	if len(quoted) > 0 {
+		s.wordRe = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	}
+	return s
+}
+
+// Text returns text with sensitive content replaced by placeholders
+func (s *Scrubber) Text(text string) string {
+	if s == nil || text == "" {
+		return text
+	}
+
+	text = emailRe.ReplaceAllString(text, emailPlaceholder)
+	text = phoneRe.ReplaceAllString(text, phonePlaceholder)
+	if s.wordRe != nil {
+		text = s.wordRe.ReplaceAllString(text, wordlistPlaceholder)
+	}
+	return text
+}
+
+// Idea scrubs the free-text fields of an idea
+func (s *Scrubber) Idea(idea types.IdeaInput) types.IdeaInput {
+	idea.Title = s.Text(idea.Title)
+	idea.OneLiner = s.Text(idea.OneLiner)
+	return idea
+}
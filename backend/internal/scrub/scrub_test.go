@@ -0,0 +1,66 @@
+package scrub
+
+import "testing"
+
+func TestTextRedactsContactDetails(t *testing.T) {
+	s := NewScrubber(nil)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"email in snippet", "Reach the founders at team@acme.io for a demo.", "Reach the founders at [email redacted] for a demo."},
+		{"plus-addressed email", "Write to jane.doe+press@mail.example.co.uk today", "Write to [email redacted] today"},
+		{"US phone", "Call 415-555-0123 to order.", "Call [phone redacted] to order."},
+		{"parenthesized area code", "Office: (415) 555-0123.", "Office: [phone redacted]."},
+		{"international phone", "Hotline +44 20 7946 0958 is open.", "Hotline [phone redacted] is open."},
+		{"international with area code", "Dial +1 (415) 555-0123 now", "Dial [phone redacted] now"},
+		{"compact international", "WhatsApp +14155550123 anytime", "WhatsApp [phone redacted] anytime"},
+		{"dotted phone", "Fax 020.7946.0958 only", "Fax [phone redacted] only"},
+		{"email and phone", "Contact bob@example.com or 212 555 0199.", "Contact [email redacted] or [phone redacted]."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Text(tt.in); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextKeepsFiguresThatAreNotPhones(t *testing.T) {
+	s := NewScrubber(nil)
+
+	for _, text := range []string{
+		"The startup raised €12.500.000 in its Series A.",
+		"Valued at 1.250.000.000 after the round",
+		"Funding of $12,500,000 led by Acme Ventures",
+		"Served from 192.168.100.200 behind a proxy",
+		"Founded in 2019, relaunched 2021-06-15",
+		"Revenue grew 250 000 EUR year over year",
+		"Between 2019 2020 2021 sales tripled",
+		"Version 10.2.3 shipped",
+	} {
+		if got := s.Text(text); got != text {
+			t.Errorf("Text(%q) = %q, want it unchanged", text, got)
+		}
+	}
+}
+
+func TestTextRedactsWordlist(t *testing.T) {
+	s := NewScrubber([]string{"darn", " heck "})
+
+	got := s.Text("Darn it, what the heck; darning is fine")
+	want := "[redacted] it, what the [redacted]; darning is fine"
+	if got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+}
+
+func TestNilScrubberLeavesTextUnchanged(t *testing.T) {
+	var s *Scrubber
+	if got := s.Text("mail me at a@b.io"); got != "mail me at a@b.io" {
+		t.Errorf("nil scrubber changed text to %q", got)
+	}
+}
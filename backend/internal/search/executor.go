@@ -3,124 +3,170 @@ package search
 import (
 	"context"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
 	"rectaify/internal/cache"
-	"rectaify/internal/llm"
+	"rectaify/internal/evidence"
 	"rectaify/pkg/types"
 )
 
+// Searcher runs web searches; *llm.Client is the one used outside tests
+type Searcher interface {
+	Search(ctx context.Context, queries []string, location *types.ApproxLocation) ([]types.Evidence, error)
+	Validate() error
+}
+
 // Executor handles search query execution with caching
 type Executor struct {
-	llmClient *llm.Client
-	cache     *cache.EvidenceCache
-	timeout   time.Duration
+	searcher       Searcher
+	cache          *cache.EvidenceCache
+	concurrency    int  // in-flight searches across all priority batches
+	overlapBatches bool // start lower-priority batches before higher ones finish
 }
 
-// NewExecutor creates a new search executor
-func NewExecutor(llmClient *llm.Client, evidenceCache *cache.EvidenceCache, timeout time.Duration) *Executor {
+// NewExecutor creates a new search executor running at most concurrency
+// searches at once. Priority batches run one after another unless
+// overlapBatches is set.
+func NewExecutor(searcher Searcher, evidenceCache *cache.EvidenceCache, concurrency int, overlapBatches bool) *Executor {
 	return &Executor{
-		llmClient: llmClient,
-		cache:     evidenceCache,
-		timeout:   timeout,
+		searcher:       searcher,
+		cache:          evidenceCache,
+		concurrency:    max(concurrency, 1),
+		overlapBatches: overlapBatches,
 	}
 }
 
-// Run executes a batch of search queries with caching and deduplication
-func (e *Executor) Run(ctx context.Context, queries []types.SearchQuery, location *types.ApproxLocation) ([]types.Evidence, error) {
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(ctx, e.timeout)
-	defer cancel()
+// cacheWriteTimeout bounds how long a cache write may outlive a cancelled
+// analysis so it never holds up shutdown
+const cacheWriteTimeout = 2 * time.Second
 
+// Run executes a batch of search queries with caching and deduplication.
+// Priority batches run in turn, or concurrently when overlapBatches is set;
+// either way they share the executor's search slots, and a contended slot goes
+// to the highest-priority query waiting. Results are assembled in priority
+// order regardless of completion order, and one QueryStat
+// is returned per query in the same order. When ctx ends, searches still
+// queued or in flight are recorded as failed and the evidence gathered so far
+// is returned. bypassCache searches every query afresh, replacing cached results.
+func (e *Executor) Run(ctx context.Context, queries []types.SearchQuery, location *types.ApproxLocation, bypassCache bool) ([]types.Evidence, []types.QueryStat, error) {
 	// Group queries by priority and process in batches
 	batches := e.groupQueriesByPriority(queries)
-	
-	var allEvidence []types.Evidence
-	var mu sync.Mutex
-	
-	// Process each priority batch
+
+	slots := newSearchSlots(e.concurrency)
+
+	// Each batch goroutine owns exactly one slot, so no locking is needed
+	results := make([][]types.Evidence, 4)
+	stats := make([][]types.QueryStat, 4)
+	var wg sync.WaitGroup
+
 	for priority := 1; priority <= 3; priority++ {
-		if priorityQueries, exists := batches[priority]; exists {
-			evidence, err := e.processBatch(ctx, priorityQueries, location)
-			if err != nil {
-				// Log error but continue with other batches
-				continue
-			}
-			
-			mu.Lock()
-			allEvidence = append(allEvidence, evidence...)
-			mu.Unlock()
+		priorityQueries, exists := batches[priority]
+		if !exists {
+			continue
 		}
+
+		if !e.overlapBatches {
+			results[priority], stats[priority] = e.processBatch(ctx, priority, priorityQueries, location, bypassCache, slots)
+			continue
+		}
+
+		wg.Add(1)
+		go func(p int, batch []types.SearchQuery) {
+			defer wg.Done()
+			results[p], stats[p] = e.processBatch(ctx, p, batch, location, bypassCache, slots)
+		}(priority, priorityQueries)
 	}
-	
+
+	wg.Wait()
+
+	var allEvidence []types.Evidence
+	var allStats []types.QueryStat
+	for priority := 1; priority <= 3; priority++ {
+		allEvidence = append(allEvidence, results[priority]...)
+		allStats = append(allStats, stats[priority]...)
+	}
+
 	// Deduplicate evidence
 	deduped := e.deduplicateEvidence(allEvidence)
-	
-	return deduped, nil
+
+	return deduped, allStats, nil
 }
 
-// processBatch processes a batch of queries with the same priority
-func (e *Executor) processBatch(ctx context.Context, queries []types.SearchQuery, location *types.ApproxLocation) ([]types.Evidence, error) {
+// processBatch processes a batch of queries with the same priority, acquiring
+// one of the shared slots for each search
+func (e *Executor) processBatch(ctx context.Context, priority int, queries []types.SearchQuery, location *types.ApproxLocation, bypassCache bool, slots *searchSlots) ([]types.Evidence, []types.QueryStat) {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var allEvidence []types.Evidence
-	
-	// Limit concurrent searches
-	sem := make(chan struct{}, 3) // Max 3 concurrent searches
-	
-	for _, query := range queries {
+	stats := make([]types.QueryStat, len(queries))
+
+	for i, query := range queries {
 		wg.Add(1)
-		
-		go func(q types.SearchQuery) {
+
+		go func(i int, q types.SearchQuery) {
 			defer wg.Done()
-			
-			// Acquire semaphore
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
+
+			// Each goroutine owns its stats slot
+			stats[i] = types.QueryStat{Query: q.Query, Intent: q.Intent}
+
+			if err := slots.acquire(ctx, priority); err != nil {
+				stats[i].Error = err.Error()
 				return
 			}
-			
-			evidence, err := e.executeQuery(ctx, q, location)
+			defer slots.release()
+
+			found, cached, err := e.executeQuery(ctx, q, location, bypassCache)
 			if err != nil {
-				// Log error but continue
+				// Record error but continue
+				stats[i].Error = err.Error()
 				return
 			}
-			
+			stats[i].Results = len(found)
+			stats[i].Cached = cached
+
 			mu.Lock()
-			allEvidence = append(allEvidence, evidence...)
+			allEvidence = append(allEvidence, evidence.TagIntent(found, q.Intent)...)
 			mu.Unlock()
-		}(query)
+		}(i, query)
 	}
-	
+
 	wg.Wait()
-	return allEvidence, nil
+	return allEvidence, stats
 }
 
-// executeQuery executes a single search query with caching
-func (e *Executor) executeQuery(ctx context.Context, query types.SearchQuery, location *types.ApproxLocation) ([]types.Evidence, error) {
+// executeQuery executes a single search query with caching, reporting whether
+// the results came from the cache. bypassCache searches even when results are
+// cached, replacing them.
+func (e *Executor) executeQuery(ctx context.Context, query types.SearchQuery, location *types.ApproxLocation, bypassCache bool) ([]types.Evidence, bool, error) {
 	// Create cache key that includes location context
 	cacheKey := e.createCacheKey(query.Query, location)
 	
 	// Check cache first
-	if cached, found, err := e.cache.GetEvidence(ctx, cacheKey); err == nil && found {
-		return cached, nil
+	if !bypassCache {
+		if cached, found, err := e.cache.GetEvidence(ctx, cacheKey); err == nil && found {
+			return cached, true, nil
+		}
 	}
 	
 	// Execute search via LLM client
-	evidence, err := e.llmClient.Search(ctx, []string{query.Query}, location)
+	evidence, err := e.searcher.Search(ctx, []string{query.Query}, location)
 	if err != nil {
-		return nil, fmt.Errorf("search failed for query '%s': %w", query.Query, err)
+		return nil, false, fmt.Errorf("search failed for query '%s': %w", query.Query, err)
 	}
 	
-	// Store in cache
-	if err := e.cache.SetEvidence(ctx, cacheKey, evidence); err != nil {
-		// Log cache error but don't fail the request
+	// Store in cache on a detached context so a result that already arrived is
+	// kept even if the analysis is being cancelled. The write is a single
+	// upsert, so the entry is either stored whole or not at all.
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheWriteTimeout)
+	defer cancel()
+	if err := e.cache.SetEvidence(writeCtx, cacheKey, query.Intent, evidence); err != nil {
+		// Don't fail the request over a cache error
+		log.Printf("Search: failed to cache results for query '%s': %v", query.Query, err)
 	}
 	
-	return evidence, nil
+	return evidence, false, nil
 }
 
 // groupQueriesByPriority groups queries by their priority level
@@ -138,35 +184,48 @@ func (e *Executor) groupQueriesByPriority(queries []types.SearchQuery) map[int][
 	return batches
 }
 
+// ValidateClient reports whether the LLM client can make search calls
+func (e *Executor) ValidateClient() error {
+	return e.searcher.Validate()
+}
+
+// CacheCollisions is the number of evidence cache lookups that hit an entry
+// stored for a different key
+func (e *Executor) CacheCollisions() int64 {
+	return e.cache.Collisions()
+}
+
+// CleanupExpiredCache removes expired cache entries from the database
+func (e *Executor) CleanupExpiredCache(ctx context.Context) (int, error) {
+	return e.cache.CleanupExpired(ctx)
+}
+
+// ExpiredCacheEntries counts the cache entries CleanupExpiredCache would remove
+func (e *Executor) ExpiredCacheEntries(ctx context.Context) (int, error) {
+	return e.cache.CountExpired(ctx)
+}
+
 // createCacheKey creates a cache key that includes location context
 func (e *Executor) createCacheKey(query string, location *types.ApproxLocation) string {
-	key := query
-	
-	if location != nil {
-		if location.Country != "" {
-			key += "|country:" + location.Country
-		}
-		if location.Region != "" {
-			key += "|region:" + location.Region
-		}
-	}
-	
-	return key
+	return cache.SearchKey(query, location)
 }
 
 // deduplicateEvidence removes duplicate evidence entries
-func (e *Executor) deduplicateEvidence(evidence []types.Evidence) []types.Evidence {
-	seen := make(map[string]bool)
+func (e *Executor) deduplicateEvidence(items []types.Evidence) []types.Evidence {
+	seen := make(map[string]int)
 	var unique []types.Evidence
 	
-	for _, ev := range evidence {
+	for _, ev := range items {
 		// Use URL + title as deduplication key
 		key := ev.URL + "|" + ev.Title
 		
-		if !seen[key] {
-			seen[key] = true
-			unique = append(unique, ev)
+		if i, ok := seen[key]; ok {
+			// Found by several queries, so relevant to each of their intents
+			unique[i].Intents = evidence.MergeIntents(unique[i].Intents, ev.Intents)
+			continue
 		}
+		seen[key] = len(unique)
+		unique = append(unique, ev)
 	}
 	
 	return unique
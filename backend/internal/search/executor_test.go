@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"rectaify/internal/cache"
+	"rectaify/pkg/types"
+)
+
+// fakeSearcher answers each query with perQuery evidence items whose URLs are
+// unique to the query
+type fakeSearcher struct {
+	perQuery int
+	delay    time.Duration
+	// onSearch, if set, runs before the search answers
+	onSearch func(query string)
+
+	calls atomic.Int64
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, queries []string, location *types.ApproxLocation) ([]types.Evidence, error) {
+	f.calls.Add(1)
+	if f.onSearch != nil {
+		f.onSearch(queries[0])
+	}
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+
+	var found []types.Evidence
+	for _, query := range queries {
+		for i := 0; i < f.perQuery; i++ {
+			found = append(found, types.Evidence{
+				ID:    fmt.Sprintf("%s-%d", query, i),
+				Title: fmt.Sprintf("%s result %d", query, i),
+				URL:   fmt.Sprintf("https://example.com/%s/%d", query, i),
+			})
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeSearcher) Validate() error { return nil }
+
+func newTestCache(t *testing.T) *cache.EvidenceCache {
+	t.Helper()
+	evidenceCache, err := cache.NewEvidenceCache(nil, 1024, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewEvidenceCache: %v", err)
+	}
+	return evidenceCache
+}
+
+// TestRunAccumulatesConcurrently runs many queries over every priority, with
+// and without overlapping batches; run it with -race
+func TestRunAccumulatesConcurrently(t *testing.T) {
+	const queriesPerPriority, perQuery = 20, 3
+
+	var queries []types.SearchQuery
+	for priority := 1; priority <= 3; priority++ {
+		for i := 0; i < queriesPerPriority; i++ {
+			queries = append(queries, types.SearchQuery{
+				Query:    fmt.Sprintf("p%d-q%d", priority, i),
+				Intent:   "market",
+				Priority: priority,
+			})
+		}
+	}
+
+	for _, overlap := range []bool{false, true} {
+		t.Run(fmt.Sprintf("overlap=%v", overlap), func(t *testing.T) {
+			searcher := &fakeSearcher{perQuery: perQuery, delay: time.Millisecond}
+			executor := NewExecutor(searcher, newTestCache(t), 4, overlap)
+
+			found, stats, err := executor.Run(context.Background(), queries, nil, false)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			if want := len(queries) * perQuery; len(found) != want {
+				t.Errorf("got %d evidence items, want %d", len(found), want)
+			}
+			seen := make(map[string]bool, len(found))
+			for _, ev := range found {
+				if seen[ev.ID] {
+					t.Errorf("evidence %s returned twice", ev.ID)
+				}
+				seen[ev.ID] = true
+			}
+
+			if len(stats) != len(queries) {
+				t.Fatalf("got %d query stats, want %d", len(stats), len(queries))
+			}
+			for i, stat := range stats {
+				if stat.Query != queries[i].Query {
+					t.Errorf("stat %d is for %q, want %q", i, stat.Query, queries[i].Query)
+				}
+				if stat.Error != "" || stat.Results != perQuery {
+					t.Errorf("stat for %q: results %d, error %q", stat.Query, stat.Results, stat.Error)
+				}
+			}
+			if calls := searcher.calls.Load(); calls != int64(len(queries)) {
+				t.Errorf("searched %d times, want %d", calls, len(queries))
+			}
+		})
+	}
+}
+
+// TestCacheWriteOutlivesCancellation cancels the analysis while a search is in
+// flight; the results that still arrive are cached whole
+func TestCacheWriteOutlivesCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const perQuery = 4
+	searcher := &fakeSearcher{perQuery: perQuery, onSearch: func(string) { cancel() }}
+	evidenceCache := newTestCache(t)
+	executor := NewExecutor(searcher, evidenceCache, 1, false)
+
+	query := types.SearchQuery{Query: "ai tutoring", Intent: "market", Priority: 1}
+	found, cached, err := executor.executeQuery(ctx, query, nil, false)
+	if err != nil {
+		t.Fatalf("executeQuery: %v", err)
+	}
+	if cached || len(found) != perQuery {
+		t.Fatalf("got %d results (cached %v), want %d searched", len(found), cached, perQuery)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("context was not cancelled during the search")
+	}
+
+	stored, ok, err := evidenceCache.GetEvidence(context.Background(), cache.SearchKey(query.Query, nil))
+	if err != nil || !ok {
+		t.Fatalf("results were not cached after cancellation (found %v, err %v)", ok, err)
+	}
+	if len(stored) != perQuery {
+		t.Errorf("cached %d results, want all %d", len(stored), perQuery)
+	}
+}
@@ -0,0 +1,198 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"rectaify/pkg/types"
+)
+
+// defaultStopWords never become key terms and break phrases. Configured stop
+// words are added to them.
+var defaultStopWords = []string{
+	"the", "a", "an", "and", "or", "but", "in", "on", "at", "to", "for", "of",
+	"with", "by", "is", "are", "was", "were", "be", "been", "have", "has",
+	"had", "do", "does", "did", "will", "would", "could", "should", "can",
+	"that", "this", "these", "those", "from", "into", "onto", "via", "as",
+	"it", "its", "you", "your", "we", "our", "they", "their", "them", "who",
+	"what", "which", "when", "where", "how", "why", "so", "not", "no", "all",
+	"any", "more", "most", "than", "then", "also", "just", "like", "about",
+	"over", "each", "every", "without", "while", "using", "use", "uses", "go",
+}
+
+// genericTerms describe almost any idea ("an app that helps people...") and
+// so make weak queries. Like stop words they break phrases, so "AI-powered
+// tutoring app" yields "tutoring" rather than "powered tutoring".
+var genericTerms = map[string]bool{
+	"app": true, "apps": true, "application": true, "platform": true,
+	"tool": true, "tools": true, "service": true, "services": true,
+	"solution": true, "solutions": true, "system": true, "software": true,
+	"product": true, "company": true, "startup": true, "business": true,
+	"new": true, "simple": true, "easy": true, "easier": true, "better": true,
+	"help": true, "helps": true, "make": true, "makes": true, "people": true,
+	"user": true, "users": true, "based": true, "powered": true, "way": true,
+	"ways": true, "everyone": true, "anyone": true, "connect": true,
+	"connects": true, "connecting": true, "allows": true, "enables": true,
+	"lets": true, "provides": true, "offers": true,
+}
+
+// minKeyTermLength is the shortest word that is a key term on its own. Words
+// in phrases may be shorter, so "AI tutor" is kept whole.
+const minKeyTermLength = 4
+
+// keyTerm is a candidate key term and how salient it is
+type keyTerm struct {
+	term   string
+	words  []string
+	score  int
+	first  int // position of its first occurrence, which breaks score ties
+	phrase bool
+}
+
+// extractKeyTerms returns the terms an idea is searched by, most salient
+// first and at most maxTerms of them. Adjacent content words are also taken
+// as two-word phrases, so "machine learning" is searched together rather
+// than as two weak single terms.
+//
+// Terms score for each occurrence, twice over in the title. Phrases score
+// above their words, and the last phrase of a run of content words, where
+// English puts the head of a compound ("personalized machine learning"),
+// scores higher still. A word is left out once a phrase containing it is in,
+// and so is a phrase overlapping one that is.
+func extractKeyTerms(idea types.IdeaInput, stopWords map[string]bool, maxTerms int) []string {
+	candidates := make(map[string]*keyTerm)
+	position := 0
+	add := func(words []string, score int) {
+		term := strings.Join(words, " ")
+		candidate, ok := candidates[term]
+		if !ok {
+			candidate = &keyTerm{term: term, words: words, first: position, phrase: len(words) > 1}
+			candidates[term] = candidate
+		}
+		candidate.score += score
+	}
+
+	for i, text := range []string{idea.Title, idea.OneLiner} {
+		weight := 1
+		if i == 0 {
+			weight = 2
+		}
+		for _, run := range contentRuns(text, stopWords) {
+			for j, word := range run {
+				position++
+				if len(word) >= minKeyTermLength {
+					add(run[j:j+1], weight)
+				}
+				if j > 0 {
+					score := weight + 1
+					if j == len(run)-1 {
+						score++
+					}
+					add(run[j-1:j+1], score)
+				}
+			}
+		}
+	}
+
+	ranked := make([]*keyTerm, 0, len(candidates))
+	for _, candidate := range candidates {
+		ranked = append(ranked, candidate)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		if ranked[i].phrase != ranked[j].phrase {
+			return ranked[i].phrase
+		}
+		return ranked[i].first < ranked[j].first
+	})
+
+	var terms []string
+	inPhrase := make(map[string]bool)
+	for _, candidate := range ranked {
+		if len(terms) >= maxTerms {
+			break
+		}
+		covered := false
+		for _, word := range candidate.words {
+			covered = covered || inPhrase[word]
+		}
+		if covered {
+			continue
+		}
+		if candidate.phrase {
+			for _, word := range candidate.words {
+				inPhrase[word] = true
+			}
+		}
+		terms = append(terms, candidate.term)
+	}
+	return terms
+}
+
+// contentRuns splits text into runs of adjacent lowercase content words.
+// Punctuation, stop words, generic terms and single letters end a run;
+// hyphens and spaces don't.
+func contentRuns(text string, stopWords map[string]bool) [][]string {
+	var runs [][]string
+	var run []string
+	var word strings.Builder
+	endWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := word.String()
+		word.Reset()
+		if len(w) < 2 || stopWords[w] || genericTerms[w] {
+			endRun(&runs, &run)
+			return
+		}
+		run = append(run, w)
+	}
+
+	skipping := false // the rest of a possessive or contraction
+	for _, c := range strings.ToLower(text) {
+		isWordRune := unicode.IsLetter(c) || unicode.IsDigit(c)
+		if skipping && isWordRune {
+			continue
+		}
+		skipping = false
+
+		switch {
+		case isWordRune:
+			word.WriteRune(c)
+		case c == '\'' || c == '’':
+			endWord()
+			skipping = true
+		case unicode.IsSpace(c) || c == '-':
+			endWord()
+		default:
+			endWord()
+			endRun(&runs, &run)
+		}
+	}
+	endWord()
+	endRun(&runs, &run)
+	return runs
+}
+
+// endRun closes the run being built, if it has any words
+func endRun(runs *[][]string, run *[]string) {
+	if len(*run) > 0 {
+		*runs = append(*runs, *run)
+	}
+	*run = nil
+}
+
+// stopWordSet returns the default stop words together with extra ones
+func stopWordSet(extra []string) map[string]bool {
+	stopWords := make(map[string]bool, len(defaultStopWords)+len(extra))
+	for _, word := range append(append([]string(nil), defaultStopWords...), extra...) {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			stopWords[word] = true
+		}
+	}
+	return stopWords
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"slices"
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func TestExtractKeyTerms(t *testing.T) {
+	tests := []struct {
+		name      string
+		idea      types.IdeaInput
+		stopWords []string
+		maxTerms  int
+		want      []string
+	}{
+		{
+			name: "compound kept as a phrase",
+			idea: types.IdeaInput{
+				Title:    "Personalized machine learning for farmers",
+				OneLiner: "Machine learning models that predict crop yields",
+			},
+			maxTerms: 8,
+			want:     []string{"machine learning", "crop yields", "personalized", "farmers", "models", "predict"},
+		},
+		{
+			name: "generic and stop words dropped",
+			idea: types.IdeaInput{
+				Title:    "AI-powered tutoring app",
+				OneLiner: "An app that helps students find an AI tutor for exam prep",
+			},
+			maxTerms: 8,
+			want:     []string{"students find", "ai tutor", "exam prep", "tutoring"},
+		},
+		{
+			name: "possessive and short words",
+			idea: types.IdeaInput{
+				Title:    "Dog walker's marketplace",
+				OneLiner: "Connects dog owners with vetted local walkers",
+			},
+			maxTerms: 8,
+			want:     []string{"walker marketplace", "dog owners", "local walkers", "vetted"},
+		},
+		{
+			name: "configured stop words break phrases",
+			idea: types.IdeaInput{
+				Title:    "AI-powered tutoring app",
+				OneLiner: "An app that helps students find an AI tutor for exam prep",
+			},
+			stopWords: []string{"Find", " exam "},
+			maxTerms:  8,
+			want:      []string{"ai tutor", "tutoring", "students", "prep"},
+		},
+		{
+			name: "capped at maxTerms",
+			idea: types.IdeaInput{
+				Title:    "Personalized machine learning for farmers",
+				OneLiner: "Machine learning models that predict crop yields",
+			},
+			maxTerms: 2,
+			want:     []string{"machine learning", "crop yields"},
+		},
+		{
+			name:     "nothing but generic words",
+			idea:     types.IdeaInput{Title: "The app", OneLiner: "A simple platform for everyone"},
+			maxTerms: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractKeyTerms(tt.idea, stopWordSet(tt.stopWords), tt.maxTerms)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
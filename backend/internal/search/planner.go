@@ -5,18 +5,26 @@ import (
 	"fmt"
 	"strings"
 
+	"rectaify/internal/taxonomy"
 	"rectaify/pkg/types"
 )
 
 // Planner generates search queries from startup ideas
 type Planner struct {
-	maxQueries int
+	maxQueries          int
+	similarityThreshold float64         // queries of one intent more alike than this are merged
+	stopWords           map[string]bool // never key terms, and break phrases
+	maxKeyTerms         int             // key terms queries are generated from
 }
 
-// NewPlanner creates a new query planner
-func NewPlanner(maxQueries int) *Planner {
+// NewPlanner creates a new query planner. stopWords are left out of key terms
+// in addition to the built-in ones.
+func NewPlanner(maxQueries int, similarityThreshold float64, stopWords []string, maxKeyTerms int) *Planner {
 	return &Planner{
-		maxQueries: maxQueries,
+		maxQueries:          maxQueries,
+		similarityThreshold: similarityThreshold,
+		stopWords:           stopWordSet(stopWords),
+		maxKeyTerms:         maxKeyTerms,
 	}
 }
 
@@ -24,12 +32,8 @@ func NewPlanner(maxQueries int) *Planner {
 func (p *Planner) Plan(ctx context.Context, idea types.IdeaInput) ([]types.SearchQuery, error) {
 	var queries []types.SearchQuery
 	
-	// Normalize the idea text
-	normalizedTitle := normalizeText(idea.Title)
-	normalizedOneLiner := normalizeText(idea.OneLiner)
-	
-	// Extract key terms
-	keyTerms := extractKeyTerms(normalizedTitle, normalizedOneLiner)
+	// Extract key terms and phrases
+	keyTerms := extractKeyTerms(idea, p.stopWords, p.maxKeyTerms)
 	
 	// Generate queries by intent
 	queries = append(queries, p.generateCompetitorQueries(keyTerms, idea)...)
@@ -81,6 +85,13 @@ func (p *Planner) generateCompetitorQueries(keyTerms []string, idea types.IdeaIn
 		Intent:   "competitors",
 		Priority: 2,
 	})
+	if category, ok := queryCategory(idea); ok && len(keyTerms) > 0 {
+		queries = append(queries, types.SearchQuery{
+			Query:    fmt.Sprintf("%s %s startups", category, keyTerms[0]),
+			Intent:   "competitors",
+			Priority: 2,
+		})
+	}
 	
 	return queries
 }
@@ -163,7 +174,7 @@ func (p *Planner) generatePostmortemQueries(keyTerms []string, idea types.IdeaIn
 			query := fmt.Sprintf(template, term)
 			queries = append(queries, types.SearchQuery{
 				Query:    query,
-				Intent:   "postmortems",
+				Intent:   intentPostmortems,
 				Priority: 3,
 			})
 		}
@@ -197,6 +208,13 @@ func (p *Planner) generateMarketQueries(keyTerms []string, idea types.IdeaInput)
 			})
 		}
 	}
+	if category, ok := queryCategory(idea); ok {
+		queries = append(queries, types.SearchQuery{
+			Query:    fmt.Sprintf("%s market size", category),
+			Intent:   "market",
+			Priority: 2,
+		})
+	}
 	
 	return queries
 }
@@ -230,117 +248,74 @@ func (p *Planner) generateProblemQueries(keyTerms []string, idea types.IdeaInput
 	return queries
 }
 
-// deduplicateQueries removes similar queries using token set similarity
+// specificKeyTerms is how many key terms an idea needs before its queries are
+// specific enough without expanding it first
+const specificKeyTerms = 4
+
+// IsVague reports whether an idea has too few key terms to plan specific
+// queries from. Under a lower key term cap, filling it is specific enough.
+func (p *Planner) IsVague(idea types.IdeaInput) bool {
+	return len(extractKeyTerms(idea, p.stopWords, p.maxKeyTerms)) < min(specificKeyTerms, p.maxKeyTerms)
+}
+
+// queryCategory returns the idea category to scope queries by. Categories
+// arrive canonical, so "other" and a missing one say nothing to search for.
+func queryCategory(idea types.IdeaInput) (string, bool) {
+	if idea.Category == "" || idea.Category == taxonomy.Other {
+		return "", false
+	}
+	return idea.Category, true
+}
+
+// deduplicateQueries removes similar queries using token set similarity. A
+// query is only compared with the kept queries of its own intent: "x funding"
+// and "x acquisition" look for different evidence however many terms they
+// share. Of similar queries the first, which generation puts in priority
+// order, is kept.
 func (p *Planner) deduplicateQueries(queries []types.SearchQuery) []types.SearchQuery {
 	if len(queries) <= 1 {
 		return queries
 	}
-	
+
 	var unique []types.SearchQuery
-	seen := make(map[string]bool)
-	
+	kept := make(map[string][]map[string]bool) // token sets of the kept queries, by intent
+
 	for _, query := range queries {
-		// Normalize query for comparison
-		normalized := normalizeQuery(query.Query)
-		
-		// Check for duplicates
+		tokens := queryTokens(query.Query)
+
 		isDuplicate := false
-		for existing := range seen {
-			if jaccardSimilarity(normalized, existing) > 0.8 {
+		for _, existing := range kept[query.Intent] {
+			if jaccardSimilarity(tokens, existing) > p.similarityThreshold {
 				isDuplicate = true
 				break
 			}
 		}
-		
+
 		if !isDuplicate {
-			seen[normalized] = true
+			kept[query.Intent] = append(kept[query.Intent], tokens)
 			unique = append(unique, query)
 		}
 	}
-	
+
 	return unique
 }
 
-// normalizeText cleans and normalizes text
-func normalizeText(text string) string {
-	// Convert to lowercase
-	text = strings.ToLower(text)
-	
-	// Remove common stop words and punctuation
-	stopWords := map[string]bool{
-		"the": true, "a": true, "an": true, "and": true, "or": true,
-		"but": true, "in": true, "on": true, "at": true, "to": true,
-		"for": true, "of": true, "with": true, "by": true, "is": true,
-		"are": true, "was": true, "were": true, "be": true, "been": true,
-		"have": true, "has": true, "had": true, "do": true, "does": true,
-		"did": true, "will": true, "would": true, "could": true, "should": true,
-	}
-	
-	words := strings.FieldsFunc(text, func(c rune) bool {
+// queryTokens returns the set of lowercase words of a query, which queries
+// are compared by regardless of word order, case and punctuation
+func queryTokens(query string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(query), func(c rune) bool {
 		return !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'))
 	})
-	
-	var filtered []string
-	for _, word := range words {
-		if len(word) > 2 && !stopWords[word] {
-			filtered = append(filtered, word)
-		}
-	}
-	
-	return strings.Join(filtered, " ")
-}
 
-// extractKeyTerms extracts important terms from the idea
-func extractKeyTerms(title, oneLiner string) []string {
-	allText := title + " " + oneLiner
-	words := strings.Fields(allText)
-	
-	// Simple term extraction - take longer words and capitalize words
-	var keyTerms []string
-	seen := make(map[string]bool)
-	
+	tokens := make(map[string]bool, len(words))
 	for _, word := range words {
-		word = strings.ToLower(word)
-		
-		// Skip if already seen, too short, or common
-		if seen[word] || len(word) < 3 {
-			continue
-		}
-		
-		// Add significant terms
-		if len(word) >= 5 || strings.Title(word) == word {
-			keyTerms = append(keyTerms, word)
-			seen[word] = true
-		}
+		tokens[word] = true
 	}
-	
-	return keyTerms
+	return tokens
 }
 
-// normalizeQuery normalizes a query for comparison
-func normalizeQuery(query string) string {
-	// Convert to lowercase and extract words
-	words := strings.FieldsFunc(strings.ToLower(query), func(c rune) bool {
-		return !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'))
-	})
-	
-	// Sort words for consistent comparison
-	return strings.Join(words, " ")
-}
-
-// jaccardSimilarity calculates Jaccard similarity between two queries
-func jaccardSimilarity(query1, query2 string) float64 {
-	set1 := make(map[string]bool)
-	set2 := make(map[string]bool)
-	
-	for _, word := range strings.Fields(query1) {
-		set1[word] = true
-	}
-	
-	for _, word := range strings.Fields(query2) {
-		set2[word] = true
-	}
-	
+// jaccardSimilarity calculates Jaccard similarity between two token sets
+func jaccardSimilarity(set1, set2 map[string]bool) float64 {
 	intersection := 0
 	for word := range set1 {
 		if set2[word] {
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func TestDeduplicateQueries(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		queries   []types.SearchQuery
+		want      []string
+	}{
+		{
+			name:      "reordered words and case merge",
+			threshold: 0.8,
+			queries: []types.SearchQuery{
+				{Query: "tutoring marketplace competitors", Intent: "competitors"},
+				{Query: "Competitors, Tutoring Marketplace", Intent: "competitors"},
+			},
+			want: []string{"tutoring marketplace competitors"},
+		},
+		{
+			name:      "different subject of one intent is kept",
+			threshold: 0.8,
+			queries: []types.SearchQuery{
+				{Query: "tutoring startup funding", Intent: "funding"},
+				{Query: "tutoring startup acquisition", Intent: "funding"},
+			},
+			want: []string{"tutoring startup funding", "tutoring startup acquisition"},
+		},
+		{
+			name:      "lower threshold merges the same pair",
+			threshold: 0.4,
+			queries: []types.SearchQuery{
+				{Query: "tutoring startup funding", Intent: "funding"},
+				{Query: "tutoring startup acquisition", Intent: "funding"},
+			},
+			want: []string{"tutoring startup funding"},
+		},
+		{
+			name:      "same text of different intents is kept for each",
+			threshold: 0.8,
+			queries: []types.SearchQuery{
+				{Query: "online tutoring market", Intent: "market"},
+				{Query: "online tutoring market", Intent: "competitors"},
+				{Query: "online tutoring market", Intent: "market"},
+			},
+			want: []string{"online tutoring market", "online tutoring market"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planner := NewPlanner(10, tt.threshold, nil, 8)
+			got := planner.deduplicateQueries(tt.queries)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d queries %+v, want %v", len(got), got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i].Query != tt.want[i] {
+					t.Errorf("query %d: got %q, want %q", i, got[i].Query, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDeduplicateQueriesKeepsIntents(t *testing.T) {
+	queries := []types.SearchQuery{
+		{Query: "online tutoring market", Intent: "market"},
+		{Query: "online tutoring market", Intent: "competitors"},
+	}
+	got := NewPlanner(10, 0.8, nil, 8).deduplicateQueries(queries)
+	if len(got) != 2 || got[0].Intent != "market" || got[1].Intent != "competitors" {
+		t.Errorf("got %+v, want the query once for each intent", got)
+	}
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"sync"
+)
+
+// searchSlots bounds the number of in-flight searches. When every slot is
+// taken, a freed slot goes to the waiting search with the highest priority
+// (lowest number), so overlapping batches never hold up more important queries.
+type searchSlots struct {
+	mu      sync.Mutex
+	free    int
+	waiting [4][]chan struct{} // by priority 1-3; a closed channel grants the slot
+}
+
+func newSearchSlots(n int) *searchSlots {
+	return &searchSlots{free: max(n, 1)}
+}
+
+// acquire waits for a slot, returning ctx's error if it ends first
+func (s *searchSlots) acquire(ctx context.Context, priority int) error {
+	s.mu.Lock()
+	if s.free > 0 {
+		s.free--
+		s.mu.Unlock()
+		return nil
+	}
+	granted := make(chan struct{})
+	s.waiting[priority] = append(s.waiting[priority], granted)
+	s.mu.Unlock()
+
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	select {
+	case <-granted:
+		// Granted while giving up; pass the slot on
+		s.mu.Unlock()
+		s.release()
+	default:
+		queue := s.waiting[priority]
+		for i, ch := range queue {
+			if ch == granted {
+				s.waiting[priority] = append(queue[:i], queue[i+1:]...)
+				break
+			}
+		}
+		s.mu.Unlock()
+	}
+	return ctx.Err()
+}
+
+// release hands the slot to the highest-priority waiter, or frees it
+func (s *searchSlots) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for priority := range s.waiting {
+		if queue := s.waiting[priority]; len(queue) > 0 {
+			s.waiting[priority] = queue[1:]
+			close(queue[0])
+			return
+		}
+	}
+	s.free++
+}
@@ -0,0 +1,22 @@
+package search
+
+import "rectaify/pkg/types"
+
+// intentPostmortems is the intent of queries that look for failed companies
+const intentPostmortems = "postmortems"
+
+// PostmortemCoverage summarizes how the postmortem queries of a search fared
+func PostmortemCoverage(stats []types.QueryStat) *types.PostmortemSearch {
+	coverage := &types.PostmortemSearch{}
+	for _, stat := range stats {
+		if stat.Intent != intentPostmortems {
+			continue
+		}
+		coverage.Queries++
+		if stat.Error == "" && stat.Results > 0 {
+			coverage.Answered++
+		}
+		coverage.Results += stat.Results
+	}
+	return coverage
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"rectaify/pkg/types"
+)
+
+// auditLockID is the Postgres advisory lock held while appending to the audit
+// log, so concurrent appends each link to the entry before them
+const auditLockID int64 = 0x41554449544c4f47 // "AUDITLOG"
+
+// auditColumns are the audit_log columns in AuditEntry order
+const auditColumns = `id, created_at, caller, action, COALESCE(analysis_id, ''), idea_title, COALESCE(category, ''),
+	outcome, COALESCE(verdict, ''), overall_score, COALESCE(error, ''), prev_hash, hash`
+
+// AuditFilter narrows an audit log listing; zero fields match every entry
+type AuditFilter struct {
+	From       *time.Time
+	To         *time.Time
+	Caller     string
+	AnalysisID string
+}
+
+// AppendAudit adds an entry to the end of the audit log, setting its time and
+// chaining it to the entry before it
+func (r *Repository) AppendAudit(ctx context.Context, entry types.AuditEntry) (types.AuditEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return entry, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", auditLockID); err != nil {
+		return entry, fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	entry.PrevHash = ""
+	err = tx.QueryRow(ctx, "SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1").Scan(&entry.PrevHash)
+	if err != nil && err != pgx.ErrNoRows {
+		return entry, fmt.Errorf("failed to read audit log head: %w", err)
+	}
+
+	// Postgres keeps microseconds, and the hash must match what is read back
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.Hash = AuditHash(entry)
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO audit_log (created_at, caller, action, analysis_id, idea_title, category,
+		 outcome, verdict, overall_score, error, prev_hash, hash)
+		 VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), $7, NULLIF($8, ''), $9, NULLIF($10, ''), $11, $12)
+		 RETURNING id`,
+		entry.CreatedAt, entry.Caller, entry.Action, entry.AnalysisID, entry.IdeaTitle, entry.Category,
+		entry.Outcome, entry.Verdict, entry.OverallScore, entry.Error, entry.PrevHash, entry.Hash).Scan(&entry.ID)
+	if err != nil {
+		return entry, fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return entry, tx.Commit(ctx)
+}
+
+// ListAudit returns audit log entries matching the filter, newest first
+func (r *Repository) ListAudit(ctx context.Context, filter AuditFilter, limit, offset int) ([]types.AuditEntry, error) {
+	var where strings.Builder
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		fmt.Fprintf(&where, " AND "+condition, len(args))
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at <= $%d", *filter.To)
+	}
+	if filter.Caller != "" {
+		addCondition("caller = $%d", filter.Caller)
+	}
+	if filter.AnalysisID != "" {
+		addCondition("analysis_id = $%d", filter.AnalysisID)
+	}
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(ctx,
+		fmt.Sprintf(`SELECT %s FROM audit_log WHERE TRUE%s ORDER BY id DESC LIMIT $%d OFFSET $%d`,
+			auditColumns, where.String(), len(args)-1, len(args)),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []types.AuditEntry{}
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// VerifyAudit walks the audit log's hash chain from the first entry, stopping
+// at the first entry that was altered or no longer follows the one before it
+func (r *Repository) VerifyAudit(ctx context.Context) (types.AuditVerification, error) {
+	verification := types.AuditVerification{Valid: true}
+
+	rows, err := r.db.Query(ctx, "SELECT "+auditColumns+" FROM audit_log ORDER BY id")
+	if err != nil {
+		return verification, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	prevHash := ""
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return verification, err
+		}
+		verification.Entries++
+
+		switch {
+		case entry.PrevHash != prevHash:
+			verification.Reason = "entry does not follow the one before it; an entry was removed or reordered"
+		case AuditHash(entry) != entry.Hash:
+			verification.Reason = "entry does not match its hash; it was altered"
+		}
+		if verification.Reason != "" {
+			verification.Valid = false
+			verification.BrokenAt = entry.ID
+			return verification, nil
+		}
+		prevHash = entry.Hash
+	}
+	verification.HeadHash = prevHash
+	return verification, rows.Err()
+}
+
+// scanAuditEntry scans a row of auditColumns
+func scanAuditEntry(rows pgx.Rows) (types.AuditEntry, error) {
+	var entry types.AuditEntry
+	err := rows.Scan(&entry.ID, &entry.CreatedAt, &entry.Caller, &entry.Action, &entry.AnalysisID,
+		&entry.IdeaTitle, &entry.Category, &entry.Outcome, &entry.Verdict, &entry.OverallScore,
+		&entry.Error, &entry.PrevHash, &entry.Hash)
+	if err != nil {
+		return entry, fmt.Errorf("failed to scan audit entry: %w", err)
+	}
+	entry.CreatedAt = entry.CreatedAt.UTC()
+	return entry, nil
+}
+
+// AuditHash is the hash chaining an audit entry: SHA-256 over the entry's
+// fields and the previous entry's hash. The ID is left out, as it is assigned
+// on insert.
+func AuditHash(entry types.AuditEntry) string {
+	entry.ID = 0
+	entry.Hash = ""
+	entry.CreatedAt = entry.CreatedAt.UTC()
+	encoded, _ := json.Marshal(entry)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
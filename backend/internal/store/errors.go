@@ -1,8 +1,22 @@
 package store
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 var (
-	ErrAnalysisNotFound = errors.New("analysis not found")
-	ErrEvidenceNotFound = errors.New("evidence not found")
+	ErrAnalysisNotFound    = errors.New("analysis not found")
+	ErrEvidenceNotFound    = errors.New("evidence not found")
+	ErrDuplicateAnalysisID = errors.New("an analysis with this ID already exists")
 )
+
+// uniqueViolation is the Postgres error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// violatesConstraint reports whether err is a unique violation of constraint
+func violatesConstraint(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == constraint
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -43,36 +44,386 @@ func (r *Repository) SaveAnalysis(ctx context.Context, analysis types.Analysis)
 
 	// Insert analysis
 	_, err = tx.Exec(ctx,
-		"INSERT INTO analyses (id, idea, result, created_at) VALUES ($1, $2, $3, $4)",
-		analysis.ID, ideaJSON, resultJSON, analysis.CreatedAt)
+		`INSERT INTO analyses (id, idea, result, created_at, category,
+		 overall_score, market_score, problem_score, barrier_score, execution_score, risk_score, graveyard_score, tags, flagged, pinned)
+		 VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, COALESCE($13::TEXT[], '{}'), $14, $15)`,
+		analysis.ID, ideaJSON, resultJSON, analysis.CreatedAt, analysis.Idea.Category,
+		analysis.Verdict.OverallScore, analysis.Verdict.MarketScore, analysis.Verdict.ProblemScore,
+		analysis.Verdict.BarrierScore, analysis.Verdict.ExecutionScore, analysis.Verdict.RiskScore,
+		analysis.Verdict.GraveyardScore, analysis.Tags, analysis.Flagged, analysis.Pinned)
+	// Saving never replaces an analysis; UpsertAnalysis is for that
+	if violatesConstraint(err, "analyses_pkey") {
+		return fmt.Errorf("%w: %s", ErrDuplicateAnalysisID, analysis.ID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert analysis: %w", err)
 	}
 
-	// Insert evidence if not already exists and link to analysis
-	for _, ev := range analysis.Evidence {
-		// Insert evidence (ignore if exists)
-		_, err = tx.Exec(ctx,
-			`INSERT INTO evidence (id, url, title, snippet, published_at, retrieved_at, source_type) 
-			 VALUES ($1, $2, $3, $4, $5, $6, $7)
-			 ON CONFLICT (id) DO NOTHING`,
-			ev.ID, ev.URL, ev.Title, ev.Snippet, ev.PublishedAt, ev.RetrievedAt, ev.SourceType)
-		if err != nil {
-			return fmt.Errorf("failed to insert evidence %s: %w", ev.ID, err)
+	if err := r.linkEvidence(ctx, tx, analysis); err != nil {
+		return err
+	}
+
+	if err := r.saveCompetitors(ctx, tx, analysis); err != nil {
+		return err
+	}
+
+	if err := r.saveFindings(ctx, tx, analysis); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpsertAnalysis stores an analysis, replacing any existing row with the same ID
+func (r *Repository) UpsertAnalysis(ctx context.Context, analysis types.Analysis) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ideaJSON, err := json.Marshal(analysis.Idea)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idea: %w", err)
+	}
+
+	resultJSON, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO analyses (id, idea, result, created_at, category,
+		 overall_score, market_score, problem_score, barrier_score, execution_score, risk_score, graveyard_score, tags, flagged, pinned)
+		 VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, COALESCE($13::TEXT[], '{}'), $14, $15)
+		 ON CONFLICT (id) DO UPDATE SET
+		 idea = EXCLUDED.idea,
+		 result = EXCLUDED.result,
+		 created_at = EXCLUDED.created_at,
+		 category = EXCLUDED.category,
+		 overall_score = EXCLUDED.overall_score,
+		 market_score = EXCLUDED.market_score,
+		 problem_score = EXCLUDED.problem_score,
+		 barrier_score = EXCLUDED.barrier_score,
+		 execution_score = EXCLUDED.execution_score,
+		 risk_score = EXCLUDED.risk_score,
+		 graveyard_score = EXCLUDED.graveyard_score,
+		 tags = EXCLUDED.tags,
+		 flagged = EXCLUDED.flagged,
+		 pinned = EXCLUDED.pinned,
+		 deleted_at = NULL`,
+		analysis.ID, ideaJSON, resultJSON, analysis.CreatedAt, analysis.Idea.Category,
+		analysis.Verdict.OverallScore, analysis.Verdict.MarketScore, analysis.Verdict.ProblemScore,
+		analysis.Verdict.BarrierScore, analysis.Verdict.ExecutionScore, analysis.Verdict.RiskScore,
+		analysis.Verdict.GraveyardScore, analysis.Tags, analysis.Flagged, analysis.Pinned)
+	if err != nil {
+		return fmt.Errorf("failed to upsert analysis: %w", err)
+	}
+
+	if err := r.linkEvidence(ctx, tx, analysis); err != nil {
+		return err
+	}
+
+	if err := r.saveCompetitors(ctx, tx, analysis); err != nil {
+		return err
+	}
+
+	if err := r.saveFindings(ctx, tx, analysis); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpdateVerdict stores a regenerated verdict, leaving evidence links,
+// competitors and findings untouched
+func (r *Repository) UpdateVerdict(ctx context.Context, analysis types.Analysis) error {
+	resultJSON, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx,
+		`UPDATE analyses SET result = $2,
+		 overall_score = $3, market_score = $4, problem_score = $5, barrier_score = $6,
+		 execution_score = $7, risk_score = $8, graveyard_score = $9, flagged = $10
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		analysis.ID, resultJSON,
+		analysis.Verdict.OverallScore, analysis.Verdict.MarketScore, analysis.Verdict.ProblemScore,
+		analysis.Verdict.BarrierScore, analysis.Verdict.ExecutionScore, analysis.Verdict.RiskScore,
+		analysis.Verdict.GraveyardScore, analysis.Flagged)
+	if err != nil {
+		return fmt.Errorf("failed to update verdict: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAnalysisNotFound
+	}
+	return nil
+}
+
+// UpdateTags replaces the tags of an analysis, in its result and in the
+// column listings filter on
+func (r *Repository) UpdateTags(ctx context.Context, analysisID string, tags []string) error {
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx,
+		`UPDATE analyses SET tags = COALESCE($2::TEXT[], '{}'), result = jsonb_set(result, '{tags}', $3::JSONB)
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		analysisID, tags, tagsJSON)
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAnalysisNotFound
+	}
+	return nil
+}
+
+// UpdatePinned pins or unpins an analysis, in its result and in the column
+// listings and the retention job filter on
+func (r *Repository) UpdatePinned(ctx context.Context, analysisID string, pinned bool) error {
+	result, err := r.db.Exec(ctx,
+		`UPDATE analyses SET pinned = $2, result = jsonb_set(result, '{pinned}', to_jsonb($2::BOOLEAN))
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		analysisID, pinned)
+	if err != nil {
+		return fmt.Errorf("failed to update pinned: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAnalysisNotFound
+	}
+	return nil
+}
+
+// saveCompetitors replaces the extracted competitor rows for an analysis
+func (r *Repository) saveCompetitors(ctx context.Context, tx pgx.Tx, analysis types.Analysis) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM analysis_competitors WHERE analysis_id = $1", analysis.ID); err != nil {
+		return fmt.Errorf("failed to clear competitors: %w", err)
+	}
+
+	for _, competitor := range analysis.Market.Competitors {
+		name := strings.TrimSpace(competitor.Name)
+		if name == "" {
+			continue
 		}
 
-		// Link evidence to analysis
-		_, err = tx.Exec(ctx,
-			`INSERT INTO analysis_evidence (analysis_id, evidence_id) 
-			 VALUES ($1, $2)
+		var fundingUSD *float64
+		if funding := competitor.FundingNormalized; funding != nil && funding.Parsed {
+			fundingUSD = &funding.USD
+		}
+
+		_, err := tx.Exec(ctx,
+			`INSERT INTO analysis_competitors (analysis_id, normalized_name, name, category, funding_usd)
+			 VALUES ($1, $2, $3, NULLIF($4, ''), $5)
 			 ON CONFLICT DO NOTHING`,
-			analysis.ID, ev.ID)
+			analysis.ID, strings.ToLower(name), name, analysis.Idea.Category, fundingUSD)
 		if err != nil {
-			return fmt.Errorf("failed to link evidence %s to analysis %s: %w", ev.ID, analysis.ID, err)
+			return fmt.Errorf("failed to insert competitor %s: %w", name, err)
 		}
 	}
 
-	return tx.Commit(ctx)
+	return nil
+}
+
+// saveFindings replaces the extracted risk, barrier and failure cause rows for
+// an analysis, which category insights aggregate
+func (r *Repository) saveFindings(ctx context.Context, tx pgx.Tx, analysis types.Analysis) error {
+	for _, table := range []string{"analysis_risks", "analysis_barriers", "analysis_failure_causes"} {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE analysis_id = $1", analysis.ID); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+	}
+
+	for _, risk := range analysis.Risks.Risks {
+		category := findingLabel(risk.Category)
+		if category == "" {
+			continue
+		}
+		_, err := tx.Exec(ctx,
+			"INSERT INTO analysis_risks (analysis_id, category, severity, likelihood) VALUES ($1, $2, $3, $4)",
+			analysis.ID, category, risk.Severity, risk.Likelihood)
+		if err != nil {
+			return fmt.Errorf("failed to insert risk %s: %w", category, err)
+		}
+	}
+
+	for _, barrier := range analysis.Barriers.Barriers {
+		barrierType := findingLabel(barrier.Type)
+		if barrierType == "" {
+			continue
+		}
+		_, err := tx.Exec(ctx,
+			"INSERT INTO analysis_barriers (analysis_id, type, weight) VALUES ($1, $2, $3)",
+			analysis.ID, barrierType, barrier.Weight)
+		if err != nil {
+			return fmt.Errorf("failed to insert barrier %s: %w", barrierType, err)
+		}
+	}
+
+	for _, graveyardCase := range analysis.Graveyard.Cases {
+		cause := findingLabel(graveyardCase.FailureCause)
+		if cause == "" {
+			continue
+		}
+		_, err := tx.Exec(ctx,
+			"INSERT INTO analysis_failure_causes (analysis_id, cause, company_name) VALUES ($1, $2, NULLIF($3, ''))",
+			analysis.ID, cause, strings.TrimSpace(graveyardCase.CompanyName))
+		if err != nil {
+			return fmt.Errorf("failed to insert failure cause of %s: %w", graveyardCase.CompanyName, err)
+		}
+	}
+
+	return nil
+}
+
+// findingLabel is the form findings are grouped by across analyses
+func findingLabel(label string) string {
+	return strings.ToLower(strings.TrimSpace(label))
+}
+
+// evidenceFailure records an evidence row that could not be persisted
+type evidenceFailure struct {
+	EvidenceID string `json:"evidence_id"`
+	Error      string `json:"error"`
+}
+
+// linkEvidence inserts evidence if not already present and links it to the analysis.
+// Each evidence row is written under its own savepoint so a single bad row is
+// rolled back and reported instead of aborting the whole transaction. When any
+// rows fail, the stored result's meta is updated to list them.
+func (r *Repository) linkEvidence(ctx context.Context, tx pgx.Tx, analysis types.Analysis) error {
+	if len(analysis.Evidence) == 0 {
+		return nil
+	}
+
+	// Fast path: bulk-load everything in a few round-trips
+	if err := r.bulkLinkEvidence(ctx, tx, analysis.ID, analysis.Evidence); err == nil {
+		return nil
+	} else if ctx.Err() != nil {
+		return fmt.Errorf("failed to link evidence to analysis %s: %w", analysis.ID, err)
+	}
+
+	// Slow path: fall back to row-by-row inserts to isolate the bad rows
+	var failures []evidenceFailure
+
+	for _, ev := range analysis.Evidence {
+		if err := r.linkSingleEvidence(ctx, tx, analysis.ID, ev); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("failed to link evidence %s to analysis %s: %w", ev.ID, analysis.ID, err)
+			}
+			failures = append(failures, evidenceFailure{EvidenceID: ev.ID, Error: err.Error()})
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	if err := analysis.SetMeta("unpersisted_evidence", failures); err != nil {
+		return fmt.Errorf("failed to record unpersisted evidence: %w", err)
+	}
+
+	resultJSON, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE analyses SET result = $2 WHERE id = $1", analysis.ID, resultJSON)
+	if err != nil {
+		return fmt.Errorf("failed to record unpersisted evidence: %w", err)
+	}
+
+	return nil
+}
+
+// evidenceColumns lists the evidence columns in COPY order
+var evidenceColumns = []string{"id", "url", "title", "snippet", "published_at", "retrieved_at", "source_type", "content_type"}
+
+// bulkLinkEvidence stages evidence with COPY into a temporary table, then moves it
+// into evidence and analysis_evidence with INSERT ... SELECT so existing rows keep
+// their ON CONFLICT DO NOTHING semantics. Runs inside a savepoint so a failure
+// leaves the outer transaction usable for the row-by-row fallback.
+func (r *Repository) bulkLinkEvidence(ctx context.Context, tx pgx.Tx, analysisID string, evidence []types.Evidence) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	defer sp.Rollback(ctx)
+
+	_, err = sp.Exec(ctx,
+		`CREATE TEMP TABLE IF NOT EXISTS evidence_staging
+		 (LIKE evidence INCLUDING DEFAULTS) ON COMMIT DROP`)
+	if err != nil {
+		return fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	if _, err = sp.Exec(ctx, "TRUNCATE evidence_staging"); err != nil {
+		return fmt.Errorf("failed to truncate staging table: %w", err)
+	}
+
+	rows := make([][]interface{}, len(evidence))
+	for i, ev := range evidence {
+		rows[i] = []interface{}{ev.ID, ev.URL, ev.Title, ev.Snippet, ev.PublishedAt, ev.RetrievedAt, ev.SourceType, ev.ContentType}
+	}
+
+	_, err = sp.CopyFrom(ctx, pgx.Identifier{"evidence_staging"}, evidenceColumns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return fmt.Errorf("failed to copy evidence: %w", err)
+	}
+
+	_, err = sp.Exec(ctx,
+		`INSERT INTO evidence (id, url, title, snippet, published_at, retrieved_at, source_type, content_type)
+		 SELECT DISTINCT ON (id) id, url, title, snippet, published_at, retrieved_at, source_type, content_type
+		 FROM evidence_staging
+		 ON CONFLICT (id) DO NOTHING`)
+	if err != nil {
+		return fmt.Errorf("failed to insert staged evidence: %w", err)
+	}
+
+	_, err = sp.Exec(ctx,
+		`INSERT INTO analysis_evidence (analysis_id, evidence_id)
+		 SELECT DISTINCT $1::text, id FROM evidence_staging
+		 ON CONFLICT DO NOTHING`,
+		analysisID)
+	if err != nil {
+		return fmt.Errorf("failed to link staged evidence: %w", err)
+	}
+
+	return sp.Commit(ctx)
+}
+
+// linkSingleEvidence inserts and links one evidence row inside a savepoint
+func (r *Repository) linkSingleEvidence(ctx context.Context, tx pgx.Tx, analysisID string, ev types.Evidence) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	defer sp.Rollback(ctx)
+
+	// Insert evidence (ignore if exists)
+	_, err = sp.Exec(ctx,
+		`INSERT INTO evidence (id, url, title, snippet, published_at, retrieved_at, source_type, content_type) 
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (id) DO NOTHING`,
+		ev.ID, ev.URL, ev.Title, ev.Snippet, ev.PublishedAt, ev.RetrievedAt, ev.SourceType, ev.ContentType)
+	if err != nil {
+		return fmt.Errorf("failed to insert evidence: %w", err)
+	}
+
+	// Link evidence to analysis
+	_, err = sp.Exec(ctx,
+		`INSERT INTO analysis_evidence (analysis_id, evidence_id) 
+		 VALUES ($1, $2)
+		 ON CONFLICT DO NOTHING`,
+		analysisID, ev.ID)
+	if err != nil {
+		return fmt.Errorf("failed to link evidence: %w", err)
+	}
+
+	return sp.Commit(ctx)
 }
 
 // GetAnalysis retrieves an analysis by ID
@@ -81,7 +432,7 @@ func (r *Repository) GetAnalysis(ctx context.Context, analysisID string) (types.
 	var createdAt time.Time
 
 	err := r.db.QueryRow(ctx,
-		"SELECT result, created_at FROM analyses WHERE id = $1",
+		"SELECT result, created_at FROM analyses WHERE id = $1 AND deleted_at IS NULL",
 		analysisID).Scan(&resultJSON, &createdAt)
 
 	if err != nil {
@@ -122,7 +473,7 @@ func (r *Repository) GetAnalysisWithEvidence(ctx context.Context, analysisID str
 // GetAnalysisEvidence retrieves all evidence linked to an analysis
 func (r *Repository) GetAnalysisEvidence(ctx context.Context, analysisID string) ([]types.Evidence, error) {
 	rows, err := r.db.Query(ctx,
-		`SELECT e.id, e.url, e.title, e.snippet, e.published_at, e.retrieved_at, e.source_type
+		`SELECT e.id, e.url, e.title, e.snippet, e.published_at, e.retrieved_at, e.source_type, e.content_type
 		 FROM evidence e
 		 JOIN analysis_evidence ae ON e.id = ae.evidence_id
 		 WHERE ae.analysis_id = $1
@@ -136,7 +487,7 @@ func (r *Repository) GetAnalysisEvidence(ctx context.Context, analysisID string)
 	var evidence []types.Evidence
 	for rows.Next() {
 		var ev types.Evidence
-		err := rows.Scan(&ev.ID, &ev.URL, &ev.Title, &ev.Snippet, &ev.PublishedAt, &ev.RetrievedAt, &ev.SourceType)
+		err := rows.Scan(&ev.ID, &ev.URL, &ev.Title, &ev.Snippet, &ev.PublishedAt, &ev.RetrievedAt, &ev.SourceType, &ev.ContentType)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan evidence: %w", err)
 		}
@@ -146,14 +497,50 @@ func (r *Repository) GetAnalysisEvidence(ctx context.Context, analysisID string)
 	return evidence, nil
 }
 
+// AnalysisFilter narrows analysis listings and counts; the zero value matches
+// every analysis
+type AnalysisFilter struct {
+	Tag     string // only analyses carrying this tag
+	Flagged *bool  // only analyses flagged for review, or only unflagged ones
+	Pinned  *bool  // only pinned analyses, or only unpinned ones
+}
+
+// conditions returns the filter as SQL to AND onto a WHERE clause, numbering
+// its parameters after args, which it returns extended
+func (f AnalysisFilter) conditions(args []interface{}) (string, []interface{}) {
+	var sql strings.Builder
+	if f.Tag != "" {
+		args = append(args, f.Tag)
+		fmt.Fprintf(&sql, " AND tags @> ARRAY[$%d]::TEXT[]", len(args))
+	}
+	if f.Flagged != nil {
+		args = append(args, *f.Flagged)
+		fmt.Fprintf(&sql, " AND flagged = $%d", len(args))
+	}
+	if f.Pinned != nil {
+		args = append(args, *f.Pinned)
+		fmt.Fprintf(&sql, " AND pinned = $%d", len(args))
+	}
+	return sql.String(), args
+}
+
+// page returns LIMIT and OFFSET clauses numbered after args, which it returns
+// extended
+func page(args []interface{}, limit, offset int) (string, []interface{}) {
+	args = append(args, limit, offset)
+	return fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args)), args
+}
+
 // ListAnalyses retrieves a paginated list of analyses
-func (r *Repository) ListAnalyses(ctx context.Context, limit, offset int) ([]types.Analysis, error) {
+func (r *Repository) ListAnalyses(ctx context.Context, filter AnalysisFilter, limit, offset int) ([]types.Analysis, error) {
+	where, args := filter.conditions(nil)
+	pagination, args := page(args, limit, offset)
 	rows, err := r.db.Query(ctx,
 		`SELECT id, idea, result, created_at 
 		 FROM analyses 
-		 ORDER BY created_at DESC 
-		 LIMIT $1 OFFSET $2`,
-		limit, offset)
+		 WHERE deleted_at IS NULL`+where+`
+		 ORDER BY created_at DESC`+pagination,
+		args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query analyses: %w", err)
 	}
@@ -218,16 +605,17 @@ func (r *Repository) SaveEvidence(ctx context.Context, evidence []types.Evidence
 
 	for _, ev := range evidence {
 		_, err = tx.Exec(ctx,
-			`INSERT INTO evidence (id, url, title, snippet, published_at, retrieved_at, source_type) 
-			 VALUES ($1, $2, $3, $4, $5, $6, $7)
+			`INSERT INTO evidence (id, url, title, snippet, published_at, retrieved_at, source_type, content_type) 
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 			 ON CONFLICT (id) DO UPDATE SET 
 			 url = EXCLUDED.url,
 			 title = EXCLUDED.title,
 			 snippet = EXCLUDED.snippet,
 			 published_at = EXCLUDED.published_at,
 			 retrieved_at = EXCLUDED.retrieved_at,
-			 source_type = EXCLUDED.source_type`,
-			ev.ID, ev.URL, ev.Title, ev.Snippet, ev.PublishedAt, ev.RetrievedAt, ev.SourceType)
+			 source_type = EXCLUDED.source_type,
+			 content_type = EXCLUDED.content_type`,
+			ev.ID, ev.URL, ev.Title, ev.Snippet, ev.PublishedAt, ev.RetrievedAt, ev.SourceType, ev.ContentType)
 		if err != nil {
 			return fmt.Errorf("failed to insert evidence %s: %w", ev.ID, err)
 		}
@@ -240,8 +628,8 @@ func (r *Repository) SaveEvidence(ctx context.Context, evidence []types.Evidence
 func (r *Repository) GetEvidence(ctx context.Context, evidenceID string) (types.Evidence, error) {
 	var ev types.Evidence
 	err := r.db.QueryRow(ctx,
-		"SELECT id, url, title, snippet, published_at, retrieved_at, source_type FROM evidence WHERE id = $1",
-		evidenceID).Scan(&ev.ID, &ev.URL, &ev.Title, &ev.Snippet, &ev.PublishedAt, &ev.RetrievedAt, &ev.SourceType)
+		"SELECT id, url, title, snippet, published_at, retrieved_at, source_type, content_type FROM evidence WHERE id = $1",
+		evidenceID).Scan(&ev.ID, &ev.URL, &ev.Title, &ev.Snippet, &ev.PublishedAt, &ev.RetrievedAt, &ev.SourceType, &ev.ContentType)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -253,15 +641,40 @@ func (r *Repository) GetEvidence(ctx context.Context, evidenceID string) (types.
 	return ev, nil
 }
 
+// GetEvidenceAnalysisIDs lists the analyses linked to an evidence item, newest first
+func (r *Repository) GetEvidenceAnalysisIDs(ctx context.Context, evidenceID string) ([]string, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT a.id FROM analysis_evidence ae
+		 JOIN analyses a ON a.id = ae.analysis_id
+		 WHERE ae.evidence_id = $1 AND a.deleted_at IS NULL
+		 ORDER BY a.created_at DESC`,
+		evidenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query evidence analyses: %w", err)
+	}
+	defer rows.Close()
+
+	analysisIDs := []string{}
+	for rows.Next() {
+		var analysisID string
+		if err := rows.Scan(&analysisID); err != nil {
+			return nil, fmt.Errorf("failed to scan analysis ID: %w", err)
+		}
+		analysisIDs = append(analysisIDs, analysisID)
+	}
+	return analysisIDs, rows.Err()
+}
+
 // SearchAnalyses searches analyses by idea content
-func (r *Repository) SearchAnalyses(ctx context.Context, query string, limit, offset int) ([]types.Analysis, error) {
+func (r *Repository) SearchAnalyses(ctx context.Context, query string, filter AnalysisFilter, limit, offset int) ([]types.Analysis, error) {
+	where, args := filter.conditions([]interface{}{"%" + query + "%"})
+	pagination, args := page(args, limit, offset)
 	rows, err := r.db.Query(ctx,
 		`SELECT id, idea, result, created_at 
 		 FROM analyses 
-		 WHERE idea::text ILIKE $1 OR result::text ILIKE $1
-		 ORDER BY created_at DESC 
-		 LIMIT $2 OFFSET $3`,
-		"%"+query+"%", limit, offset)
+		 WHERE deleted_at IS NULL AND (idea::text ILIKE $1 OR result::text ILIKE $1)`+where+`
+		 ORDER BY created_at DESC`+pagination,
+		args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search analyses: %w", err)
 	}
@@ -290,17 +703,320 @@ func (r *Repository) SearchAnalyses(ctx context.Context, query string, limit, of
 	return analyses, nil
 }
 
+// analysisScoresColumns selects the scores-only projection out of the result
+// blob in the database, so evidence, dimension details and meta are never sent
+const analysisScoresColumns = `id, idea, result->'verdict', COALESCE(result->>'summary', ''),
+	COALESCE((result->>'verdict_version')::INT, 0), COALESCE((result->>'partial')::BOOLEAN, FALSE), created_at, tags, flagged, pinned`
+
+// scanAnalysisScores scans a row selected with analysisScoresColumns
+func scanAnalysisScores(row pgx.Row) (types.AnalysisScores, error) {
+	var scores types.AnalysisScores
+	var ideaJSON, verdictJSON []byte
+
+	err := row.Scan(&scores.ID, &ideaJSON, &verdictJSON, &scores.Summary, &scores.VerdictVersion, &scores.Partial, &scores.CreatedAt, &scores.Tags, &scores.Flagged, &scores.Pinned)
+	if err != nil {
+		return types.AnalysisScores{}, err
+	}
+
+	if err := json.Unmarshal(ideaJSON, &scores.Idea); err != nil {
+		return types.AnalysisScores{}, fmt.Errorf("failed to unmarshal idea of analysis %s: %w", scores.ID, err)
+	}
+	if len(verdictJSON) > 0 {
+		if err := json.Unmarshal(verdictJSON, &scores.Verdict); err != nil {
+			return types.AnalysisScores{}, fmt.Errorf("failed to unmarshal verdict of analysis %s: %w", scores.ID, err)
+		}
+	}
+
+	return scores, nil
+}
+
+// GetAnalysisScores retrieves the scores-only projection of an analysis
+func (r *Repository) GetAnalysisScores(ctx context.Context, analysisID string) (types.AnalysisScores, error) {
+	row := r.db.QueryRow(ctx,
+		`SELECT `+analysisScoresColumns+`
+		 FROM analyses
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		analysisID)
+
+	scores, err := scanAnalysisScores(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return types.AnalysisScores{}, ErrAnalysisNotFound
+		}
+		return types.AnalysisScores{}, fmt.Errorf("failed to query analysis: %w", err)
+	}
+
+	return scores, nil
+}
+
+// ListAnalysisScores returns the scores-only projection of a page of analyses
+func (r *Repository) ListAnalysisScores(ctx context.Context, filter AnalysisFilter, limit, offset int) ([]types.AnalysisScores, error) {
+	where, args := filter.conditions(nil)
+	pagination, args := page(args, limit, offset)
+	rows, err := r.db.Query(ctx,
+		`SELECT `+analysisScoresColumns+`
+		 FROM analyses
+		 WHERE deleted_at IS NULL`+where+`
+		 ORDER BY created_at DESC`+pagination,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analyses: %w", err)
+	}
+	defer rows.Close()
+
+	return collectAnalysisScores(rows)
+}
+
+// SearchAnalysisScores searches analyses like SearchAnalyses, returning the scores-only projection
+func (r *Repository) SearchAnalysisScores(ctx context.Context, query string, filter AnalysisFilter, limit, offset int) ([]types.AnalysisScores, error) {
+	where, args := filter.conditions([]interface{}{"%" + query + "%"})
+	pagination, args := page(args, limit, offset)
+	rows, err := r.db.Query(ctx,
+		`SELECT `+analysisScoresColumns+`
+		 FROM analyses
+		 WHERE deleted_at IS NULL AND (idea::text ILIKE $1 OR result::text ILIKE $1)`+where+`
+		 ORDER BY created_at DESC`+pagination,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search analyses: %w", err)
+	}
+	defer rows.Close()
+
+	return collectAnalysisScores(rows)
+}
+
+// collectAnalysisScores scans every row selected with analysisScoresColumns
+func collectAnalysisScores(rows pgx.Rows) ([]types.AnalysisScores, error) {
+	var list []types.AnalysisScores
+	for rows.Next() {
+		scores, err := scanAnalysisScores(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan analysis: %w", err)
+		}
+		list = append(list, scores)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read analyses: %w", err)
+	}
+
+	return list, nil
+}
+
 // GetAnalysisCount returns the total number of analyses
 func (r *Repository) GetAnalysisCount(ctx context.Context) (int, error) {
+	return r.CountAnalyses(ctx, AnalysisFilter{})
+}
+
+// CountAnalyses returns the number of analyses matching a filter
+func (r *Repository) CountAnalyses(ctx context.Context, filter AnalysisFilter) (int, error) {
+	where, args := filter.conditions(nil)
 	var count int
-	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM analyses").Scan(&count)
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM analyses WHERE deleted_at IS NULL"+where, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count analyses: %w", err)
 	}
 	return count, nil
 }
 
-// CleanupOldEvidence removes evidence older than the specified duration that's not linked to any analysis
+// ListCompetitors aggregates competitors across all analyses by case-insensitive
+// name, most frequent first. An empty category matches every analysis.
+func (r *Repository) ListCompetitors(ctx context.Context, category string, limit, offset int) ([]types.CompetitorAggregate, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT MIN(ac.name), COUNT(DISTINCT ac.analysis_id), ARRAY_AGG(DISTINCT ac.analysis_id), MAX(ac.funding_usd)
+		 FROM analysis_competitors ac
+		 JOIN analyses a ON a.id = ac.analysis_id AND a.deleted_at IS NULL
+		 WHERE $1 = '' OR LOWER(ac.category) = LOWER($1)
+		 GROUP BY ac.normalized_name
+		 ORDER BY COUNT(DISTINCT ac.analysis_id) DESC, ac.normalized_name
+		 LIMIT $2 OFFSET $3`,
+		category, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query competitors: %w", err)
+	}
+	defer rows.Close()
+
+	competitors := []types.CompetitorAggregate{}
+	for rows.Next() {
+		var competitor types.CompetitorAggregate
+		if err := rows.Scan(&competitor.Name, &competitor.Occurrences, &competitor.AnalysisIDs, &competitor.MaxFundingUSD); err != nil {
+			return nil, fmt.Errorf("failed to scan competitor: %w", err)
+		}
+		competitors = append(competitors, competitor)
+	}
+
+	return competitors, rows.Err()
+}
+
+// GetCategoryInsights aggregates the live analyses of a category: their
+// average scores and, most common first, up to limit each of the risk
+// categories, barrier types and failure causes they share
+func (r *Repository) GetCategoryInsights(ctx context.Context, category string, limit int) (types.CategoryInsights, error) {
+	insights := types.CategoryInsights{
+		Risks:         []types.InsightCount{},
+		BarrierTypes:  []types.InsightCount{},
+		FailureCauses: []types.InsightCount{},
+	}
+
+	c := &insights.CategoryAnalytics
+	err := r.db.QueryRow(ctx,
+		`SELECT LOWER($1), COUNT(*),
+		 COALESCE(AVG(overall_score), 0), COALESCE(AVG(market_score), 0), COALESCE(AVG(problem_score), 0),
+		 COALESCE(AVG(barrier_score), 0), COALESCE(AVG(execution_score), 0), COALESCE(AVG(risk_score), 0),
+		 COALESCE(AVG(graveyard_score), 0)
+		 FROM analyses
+		 WHERE deleted_at IS NULL AND LOWER(category) = LOWER($1)`,
+		category).Scan(&c.Category, &c.Count,
+		&c.AvgOverallScore, &c.AvgMarketScore, &c.AvgProblemScore, &c.AvgBarrierScore,
+		&c.AvgExecutionScore, &c.AvgRiskScore, &c.AvgGraveyardScore)
+	if err != nil {
+		return insights, fmt.Errorf("failed to query category scores: %w", err)
+	}
+	if c.Count == 0 {
+		return insights, nil
+	}
+
+	findings := []struct {
+		query string
+		into  *[]types.InsightCount
+	}{
+		{`SELECT f.category, COUNT(DISTINCT f.analysis_id), COALESCE(AVG(f.severity), 0)::DOUBLE PRECISION
+		  FROM analysis_risks f`, &insights.Risks},
+		{`SELECT f.type, COUNT(DISTINCT f.analysis_id), COALESCE(AVG(f.weight), 0)
+		  FROM analysis_barriers f`, &insights.BarrierTypes},
+		{`SELECT f.cause, COUNT(DISTINCT f.analysis_id), 0::DOUBLE PRECISION
+		  FROM analysis_failure_causes f`, &insights.FailureCauses},
+	}
+	for _, finding := range findings {
+		rows, err := r.db.Query(ctx, finding.query+`
+			 JOIN analyses a ON a.id = f.analysis_id AND a.deleted_at IS NULL
+			 WHERE LOWER(a.category) = LOWER($1)
+			 GROUP BY 1
+			 ORDER BY 2 DESC, 1
+			 LIMIT $2`,
+			category, limit)
+		if err != nil {
+			return insights, fmt.Errorf("failed to query category findings: %w", err)
+		}
+		for rows.Next() {
+			var count types.InsightCount
+			if err := rows.Scan(&count.Value, &count.Analyses, &count.Average); err != nil {
+				rows.Close()
+				return insights, fmt.Errorf("failed to scan category finding: %w", err)
+			}
+			count.Share = float64(count.Analyses) / float64(c.Count)
+			*finding.into = append(*finding.into, count)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return insights, err
+		}
+	}
+
+	return insights, nil
+}
+
+// GetAnalytics aggregates the denormalized score columns across live analyses:
+// average scores per category and a 10-point histogram of overall scores
+func (r *Repository) GetAnalytics(ctx context.Context) (types.Analytics, error) {
+	analytics := types.Analytics{
+		ByCategory:   []types.CategoryAnalytics{},
+		Distribution: []types.ScoreBucket{},
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT COALESCE(LOWER(category), 'uncategorized'), COUNT(*),
+		 AVG(overall_score), AVG(market_score), AVG(problem_score), AVG(barrier_score),
+		 AVG(execution_score), AVG(risk_score), AVG(graveyard_score)
+		 FROM analyses
+		 WHERE deleted_at IS NULL AND overall_score IS NOT NULL
+		 GROUP BY 1
+		 ORDER BY 2 DESC, 1`)
+	if err != nil {
+		return analytics, fmt.Errorf("failed to query category analytics: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c types.CategoryAnalytics
+		if err := rows.Scan(&c.Category, &c.Count,
+			&c.AvgOverallScore, &c.AvgMarketScore, &c.AvgProblemScore, &c.AvgBarrierScore,
+			&c.AvgExecutionScore, &c.AvgRiskScore, &c.AvgGraveyardScore); err != nil {
+			return analytics, fmt.Errorf("failed to scan category analytics: %w", err)
+		}
+		analytics.TotalAnalyses += c.Count
+		analytics.ByCategory = append(analytics.ByCategory, c)
+	}
+	if err := rows.Err(); err != nil {
+		return analytics, err
+	}
+
+	bucketRows, err := r.db.Query(ctx,
+		`SELECT LEAST(FLOOR(overall_score / 10), 9)::INT AS bucket, COUNT(*)
+		 FROM analyses
+		 WHERE deleted_at IS NULL AND overall_score IS NOT NULL
+		 GROUP BY bucket
+		 ORDER BY bucket`)
+	if err != nil {
+		return analytics, fmt.Errorf("failed to query score distribution: %w", err)
+	}
+	defer bucketRows.Close()
+
+	counts := make(map[int]int)
+	for bucketRows.Next() {
+		var bucket, count int
+		if err := bucketRows.Scan(&bucket, &count); err != nil {
+			return analytics, fmt.Errorf("failed to scan score distribution: %w", err)
+		}
+		counts[bucket] = count
+	}
+	if err := bucketRows.Err(); err != nil {
+		return analytics, err
+	}
+
+	for bucket := 0; bucket < 10; bucket++ {
+		analytics.Distribution = append(analytics.Distribution, types.ScoreBucket{
+			Min:   float64(bucket * 10),
+			Max:   float64(bucket*10 + 10),
+			Count: counts[bucket],
+		})
+	}
+
+	return analytics, nil
+}
+
+// GetSearchCount returns the number of analyses matching a search query
+func (r *Repository) GetSearchCount(ctx context.Context, query string, filter AnalysisFilter) (int, error) {
+	where, args := filter.conditions([]interface{}{"%" + query + "%"})
+	var count int
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM analyses
+		 WHERE deleted_at IS NULL AND (idea::text ILIKE $1 OR result::text ILIKE $1)`+where,
+		args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+	return count, nil
+}
+
+// SaveWebhookDeadLetter records a webhook delivery that failed on every attempt
+func (r *Repository) SaveWebhookDeadLetter(ctx context.Context, letter types.WebhookDeadLetter) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO webhook_dead_letters (event_id, url, event_type, payload, attempts, last_error, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (event_id, url) DO UPDATE SET
+		 attempts = EXCLUDED.attempts,
+		 last_error = EXCLUDED.last_error,
+		 created_at = EXCLUDED.created_at`,
+		letter.EventID, letter.URL, letter.EventType, letter.Payload, letter.Attempts, letter.LastError, letter.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook dead letter: %w", err)
+	}
+	return nil
+}
+
+// CleanupOldEvidence removes evidence older than the specified duration that's
+// not linked to any analysis. Links outlive soft deletion, so the evidence of
+// pinned analyses, which are never deleted by retention, is always kept.
 func (r *Repository) CleanupOldEvidence(ctx context.Context, olderThan time.Duration) (int, error) {
 	cutoff := time.Now().Add(-olderThan)
 	
@@ -315,3 +1031,76 @@ func (r *Repository) CleanupOldEvidence(ctx context.Context, olderThan time.Dura
 
 	return int(result.RowsAffected()), nil
 }
+
+// CountOldEvidence counts the evidence CleanupOldEvidence would remove
+func (r *Repository) CountOldEvidence(ctx context.Context, olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
+
+	var count int
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM evidence
+		 WHERE retrieved_at < $1
+		 AND id NOT IN (SELECT DISTINCT evidence_id FROM analysis_evidence)`,
+		cutoff).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count old evidence: %w", err)
+	}
+
+	return count, nil
+}
+
+// SoftDeleteAnalysesOlderThan marks analyses created before the retention
+// window as deleted, other than pinned ones
+func (r *Repository) SoftDeleteAnalysesOlderThan(ctx context.Context, olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
+
+	result, err := r.db.Exec(ctx,
+		`UPDATE analyses SET deleted_at = NOW()
+		 WHERE created_at < $1 AND deleted_at IS NULL AND NOT pinned`,
+		cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to soft-delete old analyses: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// StreamAnalyses iterates over all non-deleted analyses in creation order, optionally
+// bounded by a created_at range, calling fn for each one. Rows are read from a
+// server-side cursor so the full result set is never held in memory.
+func (r *Repository) StreamAnalyses(ctx context.Context, from, to *time.Time, fn func(types.Analysis) error) error {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, result, created_at
+		 FROM analyses
+		 WHERE deleted_at IS NULL
+		 AND ($1::timestamptz IS NULL OR created_at >= $1)
+		 AND ($2::timestamptz IS NULL OR created_at < $2)
+		 ORDER BY created_at ASC, id ASC`,
+		from, to)
+	if err != nil {
+		return fmt.Errorf("failed to query analyses: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var resultJSON []byte
+		var createdAt time.Time
+
+		if err := rows.Scan(&id, &resultJSON, &createdAt); err != nil {
+			return fmt.Errorf("failed to scan analysis: %w", err)
+		}
+
+		var analysis types.Analysis
+		if err := json.Unmarshal(resultJSON, &analysis); err != nil {
+			return fmt.Errorf("failed to unmarshal analysis %s: %w", id, err)
+		}
+		analysis.CreatedAt = createdAt
+
+		if err := fn(analysis); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"rectaify/internal/schema"
+	"rectaify/pkg/types"
+)
+
+// testRepository connects to the disposable Postgres database given by
+// TEST_DB_DSN, skipping when it isn't set
+func testRepository(tb testing.TB) *Repository {
+	tb.Helper()
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		tb.Skip("TEST_DB_DSN not set")
+	}
+
+	ctx := context.Background()
+	db, err := schema.InitDatabase(ctx, dsn)
+	if err != nil {
+		tb.Fatalf("connecting: %v", err)
+	}
+	tb.Cleanup(db.Close)
+	if err := schema.Migrate(ctx, db); err != nil {
+		tb.Fatalf("migrating: %v", err)
+	}
+	return NewRepository(db)
+}
+
+func TestViolatesConstraint(t *testing.T) {
+	duplicate := &pgconn.PgError{Code: uniqueViolation, ConstraintName: "analyses_pkey"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"duplicate ID", duplicate, true},
+		{"wrapped duplicate ID", fmt.Errorf("insert: %w", duplicate), true},
+		{"other constraint", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "evidence_pkey"}, false},
+		{"other error code", &pgconn.PgError{Code: "23503", ConstraintName: "analyses_pkey"}, false},
+		{"not a Postgres error", errors.New("connection reset"), false},
+		{"no error", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := violatesConstraint(tt.err, "analyses_pkey"); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestSaveAnalysisDuplicateID inserts one analysis ID twice
+func TestSaveAnalysisDuplicateID(t *testing.T) {
+	repository := testRepository(t)
+	ctx := context.Background()
+	analysis := types.Analysis{
+		ID:        fmt.Sprintf("test-duplicate-%d", time.Now().UnixNano()),
+		Idea:      types.IdeaInput{Title: "Tutor match", OneLiner: "Matches students with tutors"},
+		CreatedAt: time.Now(),
+	}
+	t.Cleanup(func() { repository.DeleteAnalysis(context.Background(), analysis.ID) })
+
+	if err := repository.SaveAnalysis(ctx, analysis); err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+	err := repository.SaveAnalysis(ctx, analysis)
+	if !errors.Is(err, ErrDuplicateAnalysisID) {
+		t.Fatalf("got %v saving the ID again, want ErrDuplicateAnalysisID", err)
+	}
+
+	// The first analysis is left as it was
+	if _, err := repository.GetAnalysis(ctx, analysis.ID); err != nil {
+		t.Errorf("original analysis lost: %v", err)
+	}
+}
+
+// BenchmarkSaveAnalysis saves analyses with 100 new evidence items each, the
+// load bulkLinkEvidence copies in
+func BenchmarkSaveAnalysis(b *testing.B) {
+	repository := testRepository(b)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("bench-%d", time.Now().UnixNano())
+	b.Cleanup(func() {
+		repository.db.Exec(context.Background(), "DELETE FROM analyses WHERE id LIKE $1", prefix+"%")
+		repository.db.Exec(context.Background(), "DELETE FROM evidence WHERE id LIKE $1", prefix+"%")
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analysis := types.Analysis{
+			ID:        fmt.Sprintf("%s-%d", prefix, i),
+			Idea:      types.IdeaInput{Title: "Tutor match", OneLiner: "Matches students with tutors"},
+			CreatedAt: time.Now(),
+		}
+		for j := 0; j < 100; j++ {
+			analysis.Evidence = append(analysis.Evidence, types.Evidence{
+				ID:          fmt.Sprintf("%s-%d-%d", prefix, i, j),
+				URL:         fmt.Sprintf("https://example.com/%s/%d/%d", prefix, i, j),
+				Title:       "Online tutoring market report",
+				Snippet:     "The online tutoring market is growing as schools adopt remote learning.",
+				RetrievedAt: time.Now(),
+				SourceType:  "news",
+			})
+		}
+
+		if err := repository.SaveAnalysis(ctx, analysis); err != nil {
+			b.Fatalf("SaveAnalysis: %v", err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package taxonomy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Other is the canonical category of ideas whose category matches none
+const Other = "other"
+
+// Categories maps each canonical category to the other names it goes by
+type Categories map[string][]string
+
+// DefaultCategories returns the categories used when the configuration sets
+// none. Synonyms only need to differ in words; case, spacing and punctuation
+// are ignored when matching.
+func DefaultCategories() Categories {
+	return Categories{
+		"saas":        {"b2b saas", "software as a service", "b2b software", "enterprise software", "software"},
+		"fintech":     {"finance", "financial services", "payments", "banking", "insurtech", "insurance", "crypto", "web3", "defi"},
+		"healthtech":  {"health", "healthcare", "digital health", "medtech", "medical", "biotech", "wellness"},
+		"edtech":      {"education", "learning", "e learning"},
+		"ecommerce":   {"e commerce", "retail", "d2c", "dtc", "direct to consumer"},
+		"marketplace": {"marketplaces", "two sided marketplace", "gig economy"},
+		"consumer":    {"consumer apps", "b2c", "social", "social media", "mobile apps", "lifestyle"},
+		"devtools":    {"developer tools", "developer platform", "infrastructure", "devops", "open source"},
+		"ai":          {"artificial intelligence", "machine learning", "ml", "generative ai", "genai", "llm", "ai ml"},
+		"hardware":    {"iot", "robotics", "devices", "deep tech"},
+		"climate":     {"climate tech", "cleantech", "clean energy", "energy", "sustainability"},
+		"proptech":    {"real estate", "property", "construction"},
+		"logistics":   {"supply chain", "mobility", "transportation", "delivery"},
+		"media":       {"entertainment", "gaming", "content", "creator economy"},
+		"foodtech":    {"food", "agtech", "agriculture", "restaurants"},
+		"hrtech":      {"hr", "recruiting", "future of work", "productivity"},
+		"legaltech":   {"legal", "govtech", "regtech", "compliance"},
+		"security":    {"cybersecurity", "infosec", "identity", "privacy"},
+		Other:         nil,
+	}
+}
+
+// Taxonomy resolves free-form category names to canonical categories
+type Taxonomy struct {
+	canonical map[string]string // matching key of every name to its category
+}
+
+// New creates a taxonomy of the given categories. A name listed under two
+// categories resolves to the alphabetically first.
+func New(categories Categories) *Taxonomy {
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := &Taxonomy{canonical: map[string]string{Key(Other): Other}}
+	for _, name := range names {
+		category := Key(name)
+		for _, synonym := range append([]string{name}, categories[name]...) {
+			if key := Key(synonym); key != "" {
+				if _, taken := t.canonical[key]; !taken {
+					t.canonical[key] = category
+				}
+			}
+		}
+	}
+	return t
+}
+
+// Canonical returns the canonical category for name. An empty name stays
+// empty; one matching no category becomes Other, with known false.
+func (t *Taxonomy) Canonical(name string) (category string, known bool) {
+	key := Key(name)
+	if key == "" {
+		return "", true
+	}
+	if category, ok := t.canonical[key]; ok {
+		return category, true
+	}
+	return Other, false
+}
+
+// Key reduces a category name to the words it is matched by, so "B2B SaaS",
+// "b2b-saas" and " b2b  saas " all match
+func Key(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(c rune) bool {
+		return !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'))
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,285 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"rectaify/internal/app"
+	"rectaify/internal/egress"
+	"rectaify/internal/store"
+	"rectaify/pkg/types"
+)
+
+// Request headers sent with every delivery
+const (
+	HeaderEvent     = "X-RectAIfy-Event"
+	HeaderDelivery  = "X-RectAIfy-Delivery"
+	HeaderTimestamp = "X-RectAIfy-Timestamp"
+	HeaderSignature = "X-RectAIfy-Signature"
+)
+
+const (
+	queueSize      = 256
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
+// Subscriber is a webhook endpoint and the events it wants. An empty event
+// list subscribes to every event.
+type Subscriber struct {
+	URL    string
+	Events []string
+}
+
+// wants reports whether the subscriber asked for the event type
+func (s Subscriber) wants(eventType app.EventType) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, event := range s.Events {
+		if event == "*" || event == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// delivery is one event bound for one subscriber
+type delivery struct {
+	url     string
+	event   app.Event
+	payload []byte
+}
+
+// Dispatcher delivers lifecycle events to webhook subscribers in the
+// background, retrying with exponential backoff and dead-lettering deliveries
+// that never succeed
+type Dispatcher struct {
+	subscribers []Subscriber
+	secret      []byte
+	client      *http.Client
+	maxAttempts int
+	repository  *store.Repository // dead-letter storage; nil only logs
+	queue       chan delivery
+
+	mu     sync.RWMutex // guards closed and sends on queue
+	closed bool
+	cancel context.CancelFunc // stops the workers' deliveries
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher. Deliveries are signed with secret when
+// it is set, and rootCAs adds to the system roots when set.
+func NewDispatcher(subscribers []Subscriber, secret string, timeout time.Duration, maxAttempts int, repository *store.Repository, rootCAs *x509.CertPool) *Dispatcher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &Dispatcher{
+		subscribers: subscribers,
+		secret:      []byte(secret),
+		client:      &http.Client{Timeout: timeout, Transport: egress.NewTransport(rootCAs)},
+		maxAttempts: maxAttempts,
+		repository:  repository,
+		queue:       make(chan delivery, queueSize),
+	}
+}
+
+// Start runs delivery workers until Shutdown
+func (d *Dispatcher) Start(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.mu.Lock()
+	d.cancel = cancel
+	d.mu.Unlock()
+
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.worker(ctx)
+	}
+}
+
+// Shutdown stops accepting events and waits for the queued deliveries to be
+// made. If ctx ends first, deliveries in flight are cancelled and the ones
+// still queued are dead-lettered without being sent.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	cancel := d.cancel
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		if cancel != nil {
+			cancel()
+		}
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Enqueue queues an event for every interested subscriber without blocking.
+// It is meant to be subscribed to the orchestrator's event bus. Events raised
+// after Shutdown are dead-lettered.
+func (d *Dispatcher) Enqueue(event app.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Webhook: failed to marshal event %s: %v", event.ID, err)
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, subscriber := range d.subscribers {
+		if !subscriber.wants(event.Type) {
+			continue
+		}
+
+		job := delivery{url: subscriber.URL, event: event, payload: payload}
+		if d.closed {
+			d.deadLetter(context.Background(), job, 0, fmt.Errorf("dispatcher is shut down"))
+			continue
+		}
+		select {
+		case d.queue <- job:
+		default:
+			d.deadLetter(context.Background(), job, 0, fmt.Errorf("delivery queue is full"))
+		}
+	}
+}
+
+// worker delivers queued events one at a time until the queue is closed and
+// drained. Once ctx is cancelled the rest are dead-lettered unsent.
+func (d *Dispatcher) worker(ctx context.Context) {
+	defer d.wg.Done()
+	for job := range d.queue {
+		if err := ctx.Err(); err != nil {
+			d.deadLetter(ctx, job, 0, err)
+			continue
+		}
+		d.deliver(ctx, job)
+	}
+}
+
+// deliver posts a delivery until it succeeds or runs out of attempts
+func (d *Dispatcher) deliver(ctx context.Context, job delivery) {
+	backoff := initialBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		if lastErr = d.post(ctx, job); lastErr == nil {
+			return
+		}
+
+		if attempt == d.maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			d.deadLetter(context.Background(), job, attempt, ctx.Err())
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	d.deadLetter(ctx, job, d.maxAttempts, lastErr)
+}
+
+// post sends a single delivery attempt; any non-2xx status is a failure
+func (d *Dispatcher) post(ctx context.Context, job delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.url, bytes.NewReader(job.payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "RectAIfy-Webhook/1.0")
+	req.Header.Set(HeaderEvent, string(job.event.Type))
+	req.Header.Set(HeaderDelivery, job.event.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if len(d.secret) > 0 {
+		req.Header.Set(HeaderSignature, "sha256="+Sign(d.secret, timestamp, job.payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("subscriber returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<payload>". Receivers
+// recompute it with the shared secret to verify a delivery, and reject stale
+// timestamps to prevent replays.
+func Sign(secret []byte, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deadLetter records a delivery that will not be retried
+func (d *Dispatcher) deadLetter(ctx context.Context, job delivery, attempts int, cause error) {
+	log.Printf("Webhook: giving up on %s for %s after %d attempts: %v", job.event.Type, job.url, attempts, cause)
+
+	if d.repository == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	letter := types.WebhookDeadLetter{
+		EventID:   job.event.ID,
+		EventType: string(job.event.Type),
+		URL:       job.url,
+		Payload:   job.payload,
+		Attempts:  attempts,
+		LastError: cause.Error(),
+		CreatedAt: time.Now(),
+	}
+	if err := d.repository.SaveWebhookDeadLetter(ctx, letter); err != nil {
+		log.Printf("Webhook: %v", err)
+	}
+}
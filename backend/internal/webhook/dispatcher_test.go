@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"rectaify/internal/app"
+)
+
+// testEvent returns a distinct completed event
+func testEvent(i int) app.Event {
+	return app.Event{
+		ID:         fmt.Sprintf("event-%d", i),
+		Type:       app.EventAnalysisCompleted,
+		AnalysisID: fmt.Sprintf("analysis-%d", i),
+		CreatedAt:  time.Now(),
+	}
+}
+
+func TestShutdownDeliversQueuedEvents(t *testing.T) {
+	var received atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		received.Add(1)
+	}))
+	defer server.Close()
+
+	dispatcher := NewDispatcher([]Subscriber{{URL: server.URL}}, "secret", time.Second, 3, nil, nil)
+	dispatcher.Start(2)
+	const events = 10
+	for i := 0; i < events; i++ {
+		dispatcher.Enqueue(testEvent(i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := dispatcher.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := received.Load(); got != events {
+		t.Errorf("delivered %d events before shutdown returned, want %d", got, events)
+	}
+
+	// Events raised after shutdown are dead-lettered, not sent
+	dispatcher.Enqueue(testEvent(events))
+	if got := received.Load(); got != events {
+		t.Errorf("delivered %d events, want %d", got, events)
+	}
+}
+
+func TestShutdownGivesUpWhenContextEnds(t *testing.T) {
+	var received atomic.Int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		<-release // a subscriber that doesn't answer until the test ends
+	}))
+	defer server.Close()
+	defer close(release)
+
+	dispatcher := NewDispatcher([]Subscriber{{URL: server.URL}}, "", time.Minute, 3, nil, nil)
+	dispatcher.Start(1)
+	for i := 0; i < 5; i++ {
+		dispatcher.Enqueue(testEvent(i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := dispatcher.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	// Only the delivery in flight was attempted; the queued ones were dead-lettered
+	if got := received.Load(); got != 1 {
+		t.Errorf("subscriber saw %d requests, want 1", got)
+	}
+}
@@ -0,0 +1,65 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// errTrailingData reports content after the JSON value of a request body
+var errTrailingData = errors.New("unexpected data after the JSON value")
+
+// decodeJSONBody decodes a request body holding exactly one JSON value into v,
+// rejecting fields v does not define and anything after the value
+func decodeJSONBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	offset := decoder.InputOffset()
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w at offset %d", errTrailingData, offset)
+	}
+	return nil
+}
+
+// writeDecodeError answers a body decodeJSONBody rejected: 413 when it was
+// cut off by http.MaxBytesReader, otherwise 400 with details saying where and
+// why parsing failed
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		WriteError(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	WriteErrorDetails(w, "Invalid JSON", describeDecodeError(err), http.StatusBadRequest)
+}
+
+// describeDecodeError explains a JSON decoding failure in terms a client can act on
+func describeDecodeError(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("syntax error at offset %d: %s", syntaxErr.Offset, syntaxErr.Error())
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return fmt.Sprintf("expected %s but got JSON %s at offset %d", typeErr.Type, typeErr.Value, typeErr.Offset)
+		}
+		return fmt.Sprintf("field %q: expected %s but got JSON %s at offset %d", typeErr.Field, typeErr.Type, typeErr.Value, typeErr.Offset)
+	case errors.Is(err, io.EOF):
+		return "request body is empty"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "request body ended before the JSON value was complete"
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return strings.TrimPrefix(err.Error(), "json: ")
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,124 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+func TestDecodeJSONBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantDetails []string // substrings of the error details
+	}{
+		{
+			name:       "valid",
+			body:       `{"idea": {"title": "Tutor match", "one_liner": "Matches students with tutors"}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "trailing JSON value",
+			body:        `{"idea": {"title": "A"}} {"idea": {"title": "B"}}`,
+			wantStatus:  http.StatusBadRequest,
+			wantDetails: []string{"unexpected data after the JSON value", "offset 24"},
+		},
+		{
+			name:        "trailing garbage",
+			body:        `{"idea": {"title": "A"}}garbage`,
+			wantStatus:  http.StatusBadRequest,
+			wantDetails: []string{"unexpected data after the JSON value", "offset 24"},
+		},
+		{
+			name:        "wrong type field",
+			body:        `{"idea": {"title": 42}}`,
+			wantStatus:  http.StatusBadRequest,
+			wantDetails: []string{`field "idea.title"`, "expected string but got JSON number", "offset 21"},
+		},
+		{
+			name:        "wrong type object",
+			body:        `{"idea": {"title": "A"}, "options": "fast"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantDetails: []string{`field "options"`, "expected types.AnalysisOptions but got JSON string"},
+		},
+		{
+			name:        "syntax error",
+			body:        `{"idea": {"title": "A",}}`,
+			wantStatus:  http.StatusBadRequest,
+			wantDetails: []string{"syntax error at offset 24"},
+		},
+		{
+			name:        "unknown field",
+			body:        `{"idea": {"title": "A"}, "priority": 1}`,
+			wantStatus:  http.StatusBadRequest,
+			wantDetails: []string{`unknown field "priority"`},
+		},
+		{
+			name:        "empty body",
+			body:        ``,
+			wantStatus:  http.StatusBadRequest,
+			wantDetails: []string{"request body is empty"},
+		},
+		{
+			name:        "truncated body",
+			body:        `{"idea": {"title": "A"`,
+			wantStatus:  http.StatusBadRequest,
+			wantDetails: []string{"ended before the JSON value was complete"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/analyze", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			var request types.AnalysisRequest
+			if err := decodeJSONBody(req, &request); err != nil {
+				writeDecodeError(rec, err)
+			} else {
+				rec.WriteHeader(http.StatusOK)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+
+			var body types.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("error body is not JSON: %v", err)
+			}
+			if body.Error != "Invalid JSON" || body.Code != CodeBadRequest {
+				t.Errorf("got %+v, want Invalid JSON with code %s", body, CodeBadRequest)
+			}
+			for _, want := range tt.wantDetails {
+				if !strings.Contains(body.Details, want) {
+					t.Errorf("details %q don't mention %q", body.Details, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeJSONBodyTooLarge(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/analyze", strings.NewReader(`{"idea": {"title": "`+strings.Repeat("x", 64)+`"}}`))
+	req.Body = http.MaxBytesReader(rec, req.Body, 16)
+
+	var request types.AnalysisRequest
+	err := decodeJSONBody(req, &request)
+	if err == nil {
+		t.Fatal("oversized body was accepted")
+	}
+	writeDecodeError(rec, err)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d, want 413", rec.Code)
+	}
+}
@@ -0,0 +1,71 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"rectaify/pkg/types"
+)
+
+// Machine-readable error codes sent in ErrorResponse.Code
+const (
+	CodeBadRequest          = "BAD_REQUEST"
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeForbidden           = "FORBIDDEN"
+	CodeNotFound            = "NOT_FOUND"
+	CodeMethodNotAllowed    = "METHOD_NOT_ALLOWED"
+	CodeConflict            = "CONFLICT"
+	CodePayloadTooLarge     = "PAYLOAD_TOO_LARGE"
+	CodeUnprocessableEntity = "UNPROCESSABLE_ENTITY"
+	CodeTooManyRequests     = "TOO_MANY_REQUESTS"
+	CodeInternal            = "INTERNAL_ERROR"
+	CodeServiceUnavailable  = "SERVICE_UNAVAILABLE"
+)
+
+// errorCodes maps HTTP statuses to their default error code
+var errorCodes = map[int]string{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnprocessableEntity:   CodeUnprocessableEntity,
+	http.StatusTooManyRequests:       CodeTooManyRequests,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusServiceUnavailable:    CodeServiceUnavailable,
+}
+
+// WriteError writes the JSON ErrorResponse envelope used for every 4xx and
+// 5xx response, whether it comes from a handler or a middleware
+func WriteError(w http.ResponseWriter, message string, statusCode int) {
+	WriteErrorDetails(w, message, "", statusCode)
+}
+
+// WriteErrorDetails writes an ErrorResponse with details explaining the error
+func WriteErrorDetails(w http.ResponseWriter, message, details string, statusCode int) {
+	code, ok := errorCodes[statusCode]
+	if !ok {
+		code = CodeInternal
+		if statusCode < http.StatusInternalServerError {
+			code = CodeBadRequest
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(types.ErrorResponse{
+		Error:   message,
+		Code:    code,
+		Details: details,
+	})
+}
+
+// WriteMethodNotAllowed rejects a request whose method the route does not
+// support, listing the methods it does in the Allow header
+func WriteMethodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	WriteError(w, "Method not allowed", http.StatusMethodNotAllowed)
+}
@@ -1,76 +1,398 @@
 package httpx
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"rectaify/internal/analyzers"
 	"rectaify/internal/app"
+	"rectaify/internal/landing"
+	"rectaify/internal/llm"
 	"rectaify/internal/report"
+	"rectaify/internal/score"
+	"rectaify/internal/store"
 	"rectaify/pkg/types"
 )
 
 // APIHandlers contains all HTTP handlers for the API
 type APIHandlers struct {
 	orchestrator    *app.Orchestrator
+	queue           *app.AnalysisQueue
 	markdownBuilder *report.MarkdownBuilder
 	htmlBuilder     *report.HTMLBuilder
+	diffBuilder     *report.DiffBuilder
+	evidenceMaxAge  time.Duration // orphaned evidence older than this is cleaned up
+	maxBatchSize    int           // ideas accepted by one batch analyze request
+	adminToken      string        // unlocks the debug meta of analyses; empty serves it to no one
 }
 
 // NewAPIHandlers creates new API handlers
-func NewAPIHandlers(orchestrator *app.Orchestrator) *APIHandlers {
+func NewAPIHandlers(orchestrator *app.Orchestrator, queue *app.AnalysisQueue, evidenceMaxAge time.Duration, evidenceOrder report.EvidenceOrder, scorer report.EvidenceScorer, caps report.SectionCaps, theme report.Theme, maxBatchSize int, adminToken string) *APIHandlers {
 	return &APIHandlers{
 		orchestrator:    orchestrator,
-		markdownBuilder: report.NewMarkdownBuilder(),
-		htmlBuilder:     report.NewHTMLBuilder(),
+		queue:           queue,
+		markdownBuilder: report.NewMarkdownBuilder(evidenceOrder, scorer, caps),
+		htmlBuilder:     report.NewHTMLBuilder(evidenceOrder, scorer, caps, theme),
+		diffBuilder:     report.NewDiffBuilder(),
+		evidenceMaxAge:  evidenceMaxAge,
+		maxBatchSize:    maxBatchSize,
+		adminToken:      adminToken,
 	}
 }
 
 // HandleAnalyze handles POST /v1/analyze
 func (h *APIHandlers) HandleAnalyze(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	request, ok := h.readAnalyzeRequest(w, r)
+	if !ok {
 		return
 	}
 
-	var request types.AnalysisRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.writeErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+	if isValidateOnly(r) {
+		validation, err := h.orchestrator.ValidateRequest(r.Context(), request)
+		if err != nil {
+			h.writeAnalyzeError(w, err)
+			return
+		}
+		h.writeJSONResponse(w, validation, http.StatusOK)
 		return
 	}
 
-	// Validate required fields
-	if request.Idea.Title == "" || request.Idea.OneLiner == "" {
-		h.writeErrorResponse(w, "Title and OneLiner are required", http.StatusBadRequest)
+	timeout, ok := h.prepareAnalysis(w, r, &request)
+	if !ok {
 		return
 	}
 
-	// Start analysis
-	analysisID, err := h.orchestrator.AnalyzeIdea(r.Context(), request)
+	// Run the analysis on the worker pool
+	analysisID, cached, err := h.queue.Analyze(r.Context(), request)
 	if err != nil {
-		h.writeErrorResponse(w, fmt.Sprintf("Analysis failed: %v", err), http.StatusInternalServerError)
+		h.writeAnalyzeError(w, err)
 		return
 	}
 
 	response := types.AnalysisResponse{
-		AnalysisID: analysisID,
-		Status:     "completed",
+		AnalysisID:       analysisID,
+		Status:           "completed",
+		EffectiveTimeout: timeout.String(),
+		Cached:           cached,
 	}
 
+	if cached {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
+	}
 	h.writeJSONResponse(w, response, http.StatusOK)
 }
 
-// HandleGetAnalysis handles GET /v1/analyses/{id}
-func (h *APIHandlers) HandleGetAnalysis(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// readAnalyzeRequest decodes an analyze request body and checks its required
+// fields, answering the request itself when they are missing
+func (h *APIHandlers) readAnalyzeRequest(w http.ResponseWriter, r *http.Request) (types.AnalysisRequest, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxAnalyzeRequestBytes)
+
+	var request types.AnalysisRequest
+	if err := decodeJSONBody(r, &request); err != nil {
+		writeDecodeError(w, err)
+		return types.AnalysisRequest{}, false
+	}
+
+	if !hasRequiredFields(request) {
+		h.writeErrorResponse(w, errMissingIdeaFields, http.StatusBadRequest)
+		return types.AnalysisRequest{}, false
+	}
+	return request, true
+}
+
+// errMissingIdeaFields answers a request without the idea fields it needs
+const errMissingIdeaFields = "Title and OneLiner are required"
+
+// hasRequiredFields reports whether a request has a title and one-liner, or a
+// source URL that can supply missing ones
+func hasRequiredFields(request types.AnalysisRequest) bool {
+	return request.SourceURL != "" || (strings.TrimSpace(request.Idea.Title) != "" && strings.TrimSpace(request.Idea.OneLiner) != "")
+}
+
+// maxBatchRequestBytes caps the size of a batch analyze request body
+const maxBatchRequestBytes = 4 << 20
+
+// HandleAnalyzeBatch handles POST /v1/analyze/batch. It runs the ideas of the
+// batch on the worker pool, each like a single analyze request with the
+// shared options under its own, and answers once all have finished. Ideas
+// that fail are reported alongside the rest instead of failing the batch.
+func (h *APIHandlers) HandleAnalyzeBatch(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchRequestBytes)
+
+	var batch types.BatchAnalysisRequest
+	if err := decodeJSONBody(r, &batch); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if len(batch.Ideas) == 0 || len(batch.Ideas) > h.maxBatchSize {
+		h.writeErrorResponse(w, fmt.Sprintf("A batch must contain between 1 and %d ideas", h.maxBatchSize), http.StatusBadRequest)
+		return
+	}
+
+	results := make([]types.BatchItemResult, len(batch.Ideas))
+	var requests []types.AnalysisRequest
+	var indexes []int // place in the batch of each of requests
+	fresh := wantsFreshAnalysis(r)
+	for i, request := range batch.Ideas {
+		if !hasRequiredFields(request) {
+			results[i] = types.BatchItemResult{Index: i, Status: "failed", Error: errMissingIdeaFields}
+			continue
+		}
+		request.Options = batch.Options.Merge(request.Options)
+		if fresh {
+			if request.Options == nil {
+				request.Options = &types.AnalysisOptions{}
+			}
+			request.Options.ForceRefresh = true
+		}
+		requests = append(requests, request)
+		indexes = append(indexes, i)
+	}
+
+	for i, result := range h.queue.AnalyzeBatch(r.Context(), requests) {
+		result.Index = indexes[i]
+		results[indexes[i]] = result
+	}
+
+	response := types.BatchAnalysisResponse{Results: results}
+	for _, result := range results {
+		if result.Error != "" {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// prepareAnalysis checks the requested timeouts and applies "Cache-Control:
+// no-cache", returning the effective analysis timeout
+func (h *APIHandlers) prepareAnalysis(w http.ResponseWriter, r *http.Request, request *types.AnalysisRequest) (time.Duration, bool) {
+	timeout, err := h.orchestrator.ResolveTimeout(request.Options)
+	if err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+	if _, err := h.orchestrator.ResolveSearchTimeout(request.Options, timeout); err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+	if _, err := h.orchestrator.ResolveEvidenceMaxAge(request.Options); err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+
+	if wantsFreshAnalysis(r) {
+		if request.Options == nil {
+			request.Options = &types.AnalysisOptions{}
+		}
+		request.Options.ForceRefresh = true
+	}
+	return timeout, true
+}
+
+// HandleAnalyzeStream handles POST /v1/analyze/stream. It runs an analysis
+// like HandleAnalyze but answers with server-sent events: a "started" event
+// with the analysis ID, by which it can be cancelled, a "dimension" event as
+// each dimension analyzer finishes, then a "complete" event carrying the
+// stored analysis with its verdict, or an "error" event. Dimension events are
+// preliminary; the evidence policy and scoring apply to the complete analysis.
+// With ?stream_verdict=true, a "scores" event carries the computed verdict
+// scores as soon as they are known, followed by "recommendation" events with
+// the recommendation as the LLM writes it.
+func (h *APIHandlers) HandleAnalyzeStream(w http.ResponseWriter, r *http.Request) {
+	request, ok := h.readAnalyzeRequest(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.prepareAnalysis(w, r, &request); !ok {
 		return
 	}
 
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeErrorResponse(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Buffered for every dimension so the analyzers never wait on the client
+	progress := make(chan types.DimensionEvent, len(analyzers.Dimensions))
+	ctx := analyzers.WithProgress(r.Context(), progress)
+	started := make(chan string, 1)
+	ctx = app.WithStarted(ctx, started)
+
+	// Left nil, and never ready, unless the verdict is streamed
+	var verdict *analyzers.VerdictStream
+	var verdictReady <-chan struct{}
+	if streamVerdict, err := strconv.ParseBool(r.URL.Query().Get("stream_verdict")); err == nil && streamVerdict {
+		verdict = analyzers.NewVerdictStream()
+		verdictReady = verdict.Ready()
+		ctx = analyzers.WithVerdictStream(ctx, verdict)
+	}
+
+	type outcome struct {
+		analysisID string
+		err        error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		analysisID, _, err := h.queue.Analyze(ctx, request)
+		done <- outcome{analysisID, err}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(event string, data interface{}) {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			log.Printf("Stream: failed to marshal %s event: %v", event, err)
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+		flusher.Flush()
+	}
+
+	// Forwards dimensions that finished while something else was sent
+	sendDimensions := func() {
+		for {
+			select {
+			case event := <-progress:
+				send("dimension", event)
+			default:
+				return
+			}
+		}
+	}
+	// The verdict starts once every dimension is in, so they go first
+	sendVerdict := func() {
+		sendDimensions()
+		scores, recommendation := verdict.Take()
+		if scores != nil {
+			send("scores", scores)
+		}
+		if recommendation != "" {
+			send("recommendation", types.RecommendationEvent{Text: recommendation})
+		}
+	}
+
+	for {
+		select {
+		case analysisID := <-started:
+			send("started", types.AnalysisResponse{AnalysisID: analysisID, Status: "running"})
+		case event := <-progress:
+			send("dimension", event)
+		case <-verdictReady:
+			sendVerdict()
+		case result := <-done:
+			// Forward what finished alongside the analysis
+			sendDimensions()
+			if verdict != nil {
+				sendVerdict()
+			}
+
+			if result.err != nil {
+				send("error", types.ErrorResponse{Error: result.err.Error()})
+				return
+			}
+			analysis, err := h.orchestrator.GetAnalysis(r.Context(), result.analysisID)
+			if err != nil {
+				send("error", types.ErrorResponse{Error: fmt.Sprintf("Failed to get analysis: %v", err)})
+				return
+			}
+			analysis.DeleteMeta(app.DebugMetaKey)
+			send("complete", analysis)
+			return
+		}
+	}
+}
+
+// wantsFreshAnalysis reports whether the client sent "Cache-Control: no-cache"
+// to bypass the analysis cache
+func wantsFreshAnalysis(r *http.Request) bool {
+	for _, header := range r.Header.Values("Cache-Control") {
+		for _, directive := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maxAnalyzeRequestBytes caps the size of an analyze request body
+const maxAnalyzeRequestBytes = 1 << 20
+
+// isValidateOnly reports whether the client asked to validate the request
+// without running it, via ?validate=true or a "Prefer: validate" header
+func isValidateOnly(r *http.Request) bool {
+	if validate, err := strconv.ParseBool(r.URL.Query().Get("validate")); err == nil && validate {
+		return true
+	}
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), "validate") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// queueRetryAfter is the Retry-After seconds sent when the analysis queue is full
+const queueRetryAfter = 30
+
+// writeAnalyzeError maps an analyze or validate failure to an HTTP status
+func (h *APIHandlers) writeAnalyzeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, landing.ErrInsufficientContent), errors.Is(err, landing.ErrUnsupportedContent):
+		h.writeErrorResponse(w, fmt.Sprintf("%v; please provide title and one_liner manually", err), http.StatusUnprocessableEntity)
+	case errors.Is(err, app.ErrInsufficientEvidence):
+		h.writeErrorResponse(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, landing.ErrInvalidURL), errors.Is(err, landing.ErrBlockedAddress),
+		errors.Is(err, app.ErrInvalidTimeout), errors.Is(err, app.ErrInvalidSearchTimeout),
+		errors.Is(err, app.ErrInvalidEvidenceAge), errors.Is(err, app.ErrInvalidTags),
+		errors.Is(err, app.ErrInvalidReviewThreshold),
+		errors.Is(err, app.ErrSourceURLDisabled):
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, app.ErrQueueFull), errors.Is(err, app.ErrQueueClosed):
+		w.Header().Set("Retry-After", strconv.Itoa(queueRetryAfter))
+		h.writeErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, app.ErrMaintenance), errors.Is(err, llm.ErrDisabled):
+		h.writeErrorResponse(w, app.ErrMaintenance.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, store.ErrDuplicateAnalysisID), errors.Is(err, app.ErrCancelled):
+		h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeErrorResponse(w, fmt.Sprintf("Analysis failed: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// HandleGetAnalysis handles GET /v1/analyses/{id}
+func (h *APIHandlers) HandleGetAnalysis(w http.ResponseWriter, r *http.Request) {
 	// Extract analysis ID from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/v1/analyses/")
+
+	if id, ok := strings.CutSuffix(path, "/diff"); ok {
+		h.handleDiff(w, r, id)
+		return
+	}
+	if id, ok := strings.CutSuffix(path, "/sensitivity"); ok {
+		h.handleSensitivity(w, r, id)
+		return
+	}
+
 	analysisID := strings.Split(path, ".")[0] // Remove file extension if present
 
 	if analysisID == "" {
@@ -78,6 +400,35 @@ func (h *APIHandlers) HandleGetAnalysis(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	scoresOnly, err := wantsScoresOnly(r)
+	if err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if scoresOnly {
+		if strings.Contains(path, ".") {
+			h.writeErrorResponse(w, "fields=scores is only supported for JSON responses", http.StatusBadRequest)
+			return
+		}
+		scores, err := h.orchestrator.GetAnalysisScores(r.Context(), analysisID)
+		if err != nil {
+			h.writeAnalysisLookupError(w, err)
+			return
+		}
+		h.writeJSONResponse(w, scores, http.StatusOK)
+		return
+	}
+
+	includeDebug, err := wantsDebugMeta(r)
+	if err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if includeDebug && !isAdmin(r, h.adminToken) {
+		h.writeErrorResponse(w, "include=debug requires the admin token", http.StatusForbidden)
+		return
+	}
+
 	analysis, err := h.orchestrator.GetAnalysis(r.Context(), analysisID)
 	if err != nil {
 		if err.Error() == "analysis not found" {
@@ -99,21 +450,254 @@ func (h *APIHandlers) HandleGetAnalysis(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Default to JSON
+	// Default to JSON, with the debug meta only for admins who asked for it
+	if !includeDebug {
+		analysis.DeleteMeta(app.DebugMetaKey)
+	}
 	h.writeJSONResponse(w, analysis, http.StatusOK)
 }
 
-// HandleListAnalyses handles GET /v1/analyses
-func (h *APIHandlers) HandleListAnalyses(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// wantsDebugMeta reports whether the client asked for the raw analyzer output
+// recorded in debug mode with ?include=debug
+func wantsDebugMeta(r *http.Request) (bool, error) {
+	switch include := r.URL.Query().Get("include"); include {
+	case "":
+		return false, nil
+	case "debug":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported include %q: must be debug", include)
+	}
+}
+
+// wantsScoresOnly reports whether the client asked for the scores-only
+// projection with ?fields=scores
+func wantsScoresOnly(r *http.Request) (bool, error) {
+	switch fields := r.URL.Query().Get("fields"); fields {
+	case "":
+		return false, nil
+	case "scores":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported fields %q: must be scores", fields)
+	}
+}
+
+// boolFilter reads a true/false listing filter such as ?flagged= or
+// ?pinned=; nil lists analyses either way
+func boolFilter(r *http.Request, name string) (*bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported %s %q: must be true or false", name, value)
+	}
+	return &parsed, nil
+}
+
+// handleDiff handles GET /v1/analyses/{id}/diff?against={otherId}
+func (h *APIHandlers) handleDiff(w http.ResponseWriter, r *http.Request, analysisID string) {
+	againstID := r.URL.Query().Get("against")
+	if analysisID == "" || againstID == "" {
+		h.writeErrorResponse(w, "Analysis ID and 'against' parameter are required", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.orchestrator.GetAnalysis(r.Context(), analysisID)
+	if err != nil {
+		h.writeAnalysisLookupError(w, err)
+		return
+	}
+
+	base, err := h.orchestrator.GetAnalysis(r.Context(), againstID)
+	if err != nil {
+		h.writeAnalysisLookupError(w, err)
+		return
+	}
+
+	h.writeJSONResponse(w, h.diffBuilder.Build(base, current), http.StatusOK)
+}
+
+// maxSensitivityDelta bounds how far each dimension may be moved
+const maxSensitivityDelta = 50
+
+// handleSensitivity handles GET /v1/analyses/{id}/sensitivity, with an
+// optional delta query parameter of the points each dimension is moved
+func (h *APIHandlers) handleSensitivity(w http.ResponseWriter, r *http.Request, analysisID string) {
+	if analysisID == "" {
+		h.writeErrorResponse(w, "Analysis ID is required", http.StatusBadRequest)
+		return
+	}
+
+	delta := score.DefaultSensitivityDelta
+	if raw := r.URL.Query().Get("delta"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed > maxSensitivityDelta {
+			h.writeErrorResponse(w, fmt.Sprintf("delta must be a number of points between 0 and %d", maxSensitivityDelta), http.StatusBadRequest)
+			return
+		}
+		delta = parsed
+	}
+
+	sensitivity, err := h.orchestrator.Sensitivity(r.Context(), analysisID, delta)
+	if err != nil {
+		h.writeAnalysisLookupError(w, err)
+		return
+	}
+
+	h.writeJSONResponse(w, sensitivity, http.StatusOK)
+}
+
+// HandleGetEvidence handles GET /v1/evidence/{id}
+func (h *APIHandlers) HandleGetEvidence(w http.ResponseWriter, r *http.Request) {
+	evidenceID := strings.TrimPrefix(r.URL.Path, "/v1/evidence/")
+	if evidenceID == "" || strings.Contains(evidenceID, "/") {
+		h.writeErrorResponse(w, "Evidence ID is required", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := h.orchestrator.GetEvidenceDetail(r.Context(), evidenceID)
+	if err != nil {
+		if errors.Is(err, store.ErrEvidenceNotFound) {
+			h.writeErrorResponse(w, "Evidence not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to get evidence: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, detail, http.StatusOK)
+}
+
+// writeAnalysisLookupError maps an analysis lookup error to a response
+func (h *APIHandlers) writeAnalysisLookupError(w http.ResponseWriter, err error) {
+	if errors.Is(err, store.ErrAnalysisNotFound) {
+		h.writeErrorResponse(w, "Analysis not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, app.ErrMaintenance) {
+		h.writeErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	h.writeErrorResponse(w, fmt.Sprintf("Failed to get analysis: %v", err), http.StatusInternalServerError)
+}
+
+// maxReverdictBatch bounds how many analyses one batch reverdict may regenerate
+const maxReverdictBatch = 50
+
+// HandleReverdict handles POST /v1/analyses/{id}/reverdict and the batch
+// POST /v1/analyses/reverdict with a body of {"ids": [...]}
+func (h *APIHandlers) HandleReverdict(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/analyses/")
+	if path == "reverdict" {
+		h.handleBatchReverdict(w, r)
 		return
 	}
 
+	analysisID := strings.TrimSuffix(path, "/reverdict")
+	if analysisID == "" {
+		h.writeErrorResponse(w, "Analysis ID is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.reverdict(r, analysisID)
+	if err != nil {
+		h.writeAnalysisLookupError(w, err)
+		return
+	}
+
+	h.writeJSONResponse(w, result, http.StatusOK)
+}
+
+// handleBatchReverdict regenerates the verdicts of several analyses, reporting
+// failures per analysis instead of aborting the batch
+func (h *APIHandlers) handleBatchReverdict(w http.ResponseWriter, r *http.Request) {
+	var request types.ReverdictRequest
+	if err := decodeJSONBody(r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if len(request.IDs) == 0 || len(request.IDs) > maxReverdictBatch {
+		h.writeErrorResponse(w, fmt.Sprintf("ids must list between 1 and %d analysis IDs", maxReverdictBatch), http.StatusBadRequest)
+		return
+	}
+
+	results := make([]types.ReverdictResult, 0, len(request.IDs))
+	failed := 0
+	for _, analysisID := range request.IDs {
+		result, err := h.reverdict(r, analysisID)
+		if err != nil {
+			result = types.ReverdictResult{AnalysisID: analysisID, Error: err.Error()}
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	response := map[string]interface{}{
+		"results":     results,
+		"regenerated": len(results) - failed,
+		"failed":      failed,
+	}
+
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// reverdict regenerates one verdict and compares it with the stored one
+func (h *APIHandlers) reverdict(r *http.Request, analysisID string) (types.ReverdictResult, error) {
+	before, after, err := h.orchestrator.Reverdict(r.Context(), analysisID)
+	if err != nil {
+		return types.ReverdictResult{}, err
+	}
+
+	return types.ReverdictResult{
+		AnalysisID:     analysisID,
+		VerdictVersion: after.VerdictVersion,
+		VerdictBefore:  before.Verdict.Verdict,
+		VerdictAfter:   after.Verdict.Verdict,
+		ScoreDeltas:    h.diffBuilder.Build(before, after).ScoreDeltas,
+	}, nil
+}
+
+// HandleRefineIdea handles POST /v1/ideas/refine with a body of
+// {"analysis_id": "..."}, suggesting changes that address the idea's weakest dimensions
+func (h *APIHandlers) HandleRefineIdea(w http.ResponseWriter, r *http.Request) {
+	var request types.RefineRequest
+	if err := decodeJSONBody(r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if strings.TrimSpace(request.AnalysisID) == "" {
+		h.writeErrorResponse(w, "analysis_id is required", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.orchestrator.RefineIdea(r.Context(), request.AnalysisID)
+	if err != nil {
+		if errors.Is(err, store.ErrAnalysisNotFound) {
+			h.writeErrorResponse(w, "Analysis not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, app.ErrMaintenance) {
+			h.writeErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		h.writeErrorResponse(w, fmt.Sprintf("Refinement failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// HandleListAnalyses handles GET /v1/analyses. ?fields=scores returns the
+// scores-only projection of each analysis.
+func (h *APIHandlers) HandleListAnalyses(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
 	searchQuery := r.URL.Query().Get("q")
+	filter := store.AnalysisFilter{Tag: strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag")))}
 
 	limit := 10 // default
 	if limitStr != "" {
@@ -129,13 +713,33 @@ func (h *APIHandlers) HandleListAnalyses(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	var analyses []types.Analysis
-	var err error
+	scoresOnly, err := wantsScoresOnly(r)
+	if err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if searchQuery != "" {
-		analyses, err = h.orchestrator.SearchAnalyses(r.Context(), searchQuery, limit, offset)
-	} else {
-		analyses, err = h.orchestrator.ListAnalyses(r.Context(), limit, offset)
+	if filter.Flagged, err = boolFilter(r, "flagged"); err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.Pinned, err = boolFilter(r, "pinned"); err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The scores-only projection is selected in the database, not trimmed here
+	var analyses interface{}
+
+	switch {
+	case scoresOnly && searchQuery != "":
+		analyses, err = h.orchestrator.SearchAnalysisScores(r.Context(), searchQuery, filter, limit, offset)
+	case scoresOnly:
+		analyses, err = h.orchestrator.ListAnalysisScores(r.Context(), filter, limit, offset)
+	case searchQuery != "":
+		analyses, err = h.orchestrator.SearchAnalyses(r.Context(), searchQuery, filter, limit, offset)
+	default:
+		analyses, err = h.orchestrator.ListAnalyses(r.Context(), filter, limit, offset)
 	}
 
 	if err != nil {
@@ -144,27 +748,115 @@ func (h *APIHandlers) HandleListAnalyses(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Create response with pagination info
-	totalCount, _ := h.orchestrator.GetAnalysisCount(r.Context())
-	
+	var totalCount int
+	if searchQuery != "" {
+		totalCount, _ = h.orchestrator.GetSearchCount(r.Context(), searchQuery, filter)
+	} else {
+		totalCount, _ = h.orchestrator.CountAnalyses(r.Context(), filter)
+	}
+
 	response := map[string]interface{}{
-		"analyses": analyses,
-		"pagination": map[string]interface{}{
-			"limit":  limit,
-			"offset": offset,
-			"total":  totalCount,
-		},
+		"analyses":   analyses,
+		"pagination": buildPagination(r, limit, offset, totalCount),
 	}
 
 	h.writeJSONResponse(w, response, http.StatusOK)
 }
 
-// HandleDeleteAnalysis handles DELETE /v1/analyses/{id}
-func (h *APIHandlers) HandleDeleteAnalysis(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// HandleListCompetitors handles GET /v1/competitors
+func (h *APIHandlers) HandleListCompetitors(w http.ResponseWriter, r *http.Request) {
+	limit, offset := parseLimitOffset(r, 50)
+	category := r.URL.Query().Get("category")
+
+	competitors, err := h.orchestrator.ListCompetitors(r.Context(), category, limit, offset)
+	if err != nil {
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to list competitors: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"competitors": competitors,
+		"limit":       limit,
+		"offset":      offset,
+	}
+
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// HandleRefreshEvidence handles POST /v1/analyses/{id}/refresh. It gathers
+// fresh evidence for the stored idea and runs the analyzers again, saving a new
+// analysis; ?fresh_search=true also bypasses cached search results.
+func (h *APIHandlers) HandleRefreshEvidence(w http.ResponseWriter, r *http.Request) {
+	analysisID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/analyses/"), "/refresh")
+	if analysisID == "" {
+		h.writeErrorResponse(w, "Analysis ID is required", http.StatusBadRequest)
+		return
+	}
+
+	freshSearch := false
+	if value := r.URL.Query().Get("fresh_search"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			h.writeErrorResponse(w, "fresh_search must be true or false", http.StatusBadRequest)
+			return
+		}
+		freshSearch = parsed
+	}
+
+	refreshedID, err := h.queue.Refresh(r.Context(), analysisID, freshSearch)
+	if err != nil {
+		if errors.Is(err, store.ErrAnalysisNotFound) {
+			h.writeErrorResponse(w, "Analysis not found", http.StatusNotFound)
+			return
+		}
+		h.writeAnalyzeError(w, err)
 		return
 	}
 
+	h.writeJSONResponse(w, types.AnalysisResponse{AnalysisID: refreshedID, Status: "completed"}, http.StatusOK)
+}
+
+// HandleCancelAnalysis handles DELETE /v1/analyses/{id}/cancel, which stops
+// a running analysis; ?save_partial=true saves what it gathered by then
+func (h *APIHandlers) HandleCancelAnalysis(w http.ResponseWriter, r *http.Request) {
+	analysisID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/analyses/"), "/cancel")
+	if analysisID == "" {
+		h.writeErrorResponse(w, "Analysis ID is required", http.StatusBadRequest)
+		return
+	}
+
+	savePartial := false
+	if value := r.URL.Query().Get("save_partial"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			h.writeErrorResponse(w, "save_partial must be true or false", http.StatusBadRequest)
+			return
+		}
+		savePartial = parsed
+	}
+
+	if err := h.orchestrator.CancelAnalysis(r.Context(), analysisID, savePartial); err != nil {
+		h.writeCancelError(w, err)
+		return
+	}
+
+	h.writeJSONResponse(w, types.AnalysisResponse{AnalysisID: analysisID, Status: "cancelled"}, http.StatusOK)
+}
+
+// writeCancelError maps a CancelAnalysis failure to its HTTP status
+func (h *APIHandlers) writeCancelError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, store.ErrAnalysisNotFound):
+		h.writeErrorResponse(w, "Analysis not found", http.StatusNotFound)
+	case errors.Is(err, app.ErrAnalysisFinished):
+		h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to cancel analysis: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// HandleDeleteAnalysis handles DELETE /v1/analyses/{id}
+func (h *APIHandlers) HandleDeleteAnalysis(w http.ResponseWriter, r *http.Request) {
 	// Extract analysis ID from URL path
 	analysisID := strings.TrimPrefix(r.URL.Path, "/v1/analyses/")
 
@@ -175,7 +867,7 @@ func (h *APIHandlers) HandleDeleteAnalysis(w http.ResponseWriter, r *http.Reques
 
 	err := h.orchestrator.DeleteAnalysis(r.Context(), analysisID)
 	if err != nil {
-		if err.Error() == "analysis not found" {
+		if errors.Is(err, store.ErrAnalysisNotFound) {
 			h.writeErrorResponse(w, "Analysis not found", http.StatusNotFound)
 			return
 		}
@@ -186,42 +878,332 @@ func (h *APIHandlers) HandleDeleteAnalysis(w http.ResponseWriter, r *http.Reques
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// HandleHealthCheck handles GET /health
-func (h *APIHandlers) HandleHealthCheck(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// maxUpdateRequestBytes bounds the body of an analysis update
+const maxUpdateRequestBytes = 16 << 10
+
+// HandleUpdateAnalysis handles PATCH /v1/analyses/{id}, which edits the tags
+// of a stored analysis and answers with the updated analysis
+func (h *APIHandlers) HandleUpdateAnalysis(w http.ResponseWriter, r *http.Request) {
+	analysisID := strings.TrimPrefix(r.URL.Path, "/v1/analyses/")
+	if analysisID == "" {
+		h.writeErrorResponse(w, "Analysis ID is required", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpdateRequestBytes)
+	var update types.AnalysisUpdate
+	if err := decodeJSONBody(r, &update); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if update.Tags == nil && update.Pinned == nil {
+		h.writeErrorResponse(w, "Nothing to update; tags and pinned are the editable fields", http.StatusBadRequest)
+		return
+	}
+
+	analysis, err := h.orchestrator.UpdateAnalysis(r.Context(), analysisID, update)
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidTags) {
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, store.ErrAnalysisNotFound) {
+			h.writeErrorResponse(w, "Analysis not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to update analysis: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	analysis.DeleteMeta(app.DebugMetaKey)
+	h.writeJSONResponse(w, analysis, http.StatusOK)
+}
+
+// HandleExport handles GET /v1/export
+func (h *APIHandlers) HandleExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "jsonl"
+	}
+	if format != "jsonl" && format != "json" {
+		h.writeErrorResponse(w, "format must be one of: jsonl, json", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseTimeParam(r.URL.Query().Get("from"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid 'from' date: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(r.URL.Query().Get("to"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid 'to' date: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	if format == "jsonl" {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"analyses-export.%s\"", format))
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	count := 0
+
+	if format == "json" {
+		w.Write([]byte("["))
+	}
+
+	err = h.orchestrator.ExportAnalyses(r.Context(), from, to, func(analysis types.Analysis) error {
+		if format == "json" && count > 0 {
+			w.Write([]byte(","))
+		}
+		if err := encoder.Encode(analysis); err != nil {
+			return err
+		}
+		count++
+
+		// Flush periodically so large exports stream to the client
+		if flusher != nil && count%50 == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+
+	if format == "json" {
+		w.Write([]byte("]\n"))
+	}
+
+	// Headers are already sent, so an error can only be reported by truncating the stream
+	if err != nil {
+		log.Printf("Export aborted after %d analyses: %v", count, err)
+	}
+}
+
+// HandleImport handles POST /v1/import
+func (h *APIHandlers) HandleImport(w http.ResponseWriter, r *http.Request) {
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) // Analyses can be large
+
+	imported := 0
+	var failures []string
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		var analysis types.Analysis
+		if err := json.Unmarshal([]byte(raw), &analysis); err != nil {
+			failures = append(failures, fmt.Sprintf("line %d: invalid JSON: %v", line, err))
+			continue
+		}
+
+		if err := h.orchestrator.ImportAnalysis(r.Context(), analysis); err != nil {
+			failures = append(failures, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		imported++
+	}
+
+	if err := scanner.Err(); err != nil {
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to read import body: %v", err), http.StatusBadRequest)
 		return
 	}
 
+	response := map[string]interface{}{
+		"imported": imported,
+		"failed":   len(failures),
+		"errors":   failures,
+	}
+
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// HandleMaintenanceCleanup handles GET and POST /v1/maintenance/cleanup. GET
+// reports how many rows a cleanup would remove; POST removes them.
+func (h *APIHandlers) HandleMaintenanceCleanup(w http.ResponseWriter, r *http.Request) {
+	var counts app.CleanupCounts
+	var err error
+	if r.Method == http.MethodPost {
+		counts, err = h.orchestrator.RunCleanup(r.Context(), h.evidenceMaxAge)
+	} else {
+		counts, err = h.orchestrator.CleanupEligible(r.Context(), h.evidenceMaxAge)
+	}
+	if err != nil {
+		h.writeErrorResponse(w, fmt.Sprintf("Cleanup failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"orphaned_evidence":     counts.OrphanedEvidence,
+		"expired_cache_entries": counts.ExpiredCacheEntries,
+		"evidence_max_age":      h.evidenceMaxAge.String(),
+		"removed":               r.Method == http.MethodPost,
+	}
+
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// maintenanceModeRequest switches maintenance mode on or off
+type maintenanceModeRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// HandleMaintenanceMode handles GET and POST /v1/maintenance/mode. POST with
+// {"enabled": true} switches off all LLM calls, rejecting new analyses while
+// stored ones stay readable; {"enabled": false} resumes them. The state is
+// held in memory, so a restart returns to MAINTENANCE_MODE.
+func (h *APIHandlers) HandleMaintenanceMode(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		var request maintenanceModeRequest
+		if err := decodeJSONBody(r, &request); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		if request.Enabled == nil {
+			h.writeErrorResponse(w, "enabled is required", http.StatusBadRequest)
+			return
+		}
+		if !h.orchestrator.SetMaintenance(*request.Enabled) {
+			h.writeErrorResponse(w, "Maintenance mode is not available", http.StatusConflict)
+			return
+		}
+	}
+
+	h.writeJSONResponse(w, map[string]bool{"enabled": h.orchestrator.Maintenance()}, http.StatusOK)
+}
+
+// HandleHealthCheck handles GET /health
+func (h *APIHandlers) HandleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	err := h.orchestrator.HealthCheck(r.Context())
 	if err != nil {
 		h.writeErrorResponse(w, fmt.Sprintf("Health check failed: %v", err), http.StatusServiceUnavailable)
 		return
 	}
 
-	response := map[string]string{
-		"status": "healthy",
+	response := map[string]interface{}{
+		"status":      "healthy",
+		"maintenance": h.orchestrator.Maintenance(),
 	}
 
 	h.writeJSONResponse(w, response, http.StatusOK)
 }
 
-// HandleStats handles GET /v1/stats
-func (h *APIHandlers) HandleStats(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// HandleReadiness handles GET /health/ready. Unlike /health it also checks the
+// LLM client configuration, so a missing API key fails readiness instead of
+// every analysis.
+func (h *APIHandlers) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	if err := h.orchestrator.Ready(r.Context()); err != nil {
+		h.writeErrorResponse(w, fmt.Sprintf("Readiness check failed: %v", err), http.StatusServiceUnavailable)
 		return
 	}
 
+	response := map[string]interface{}{
+		"status":      "ready",
+		"maintenance": h.orchestrator.Maintenance(),
+	}
+
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// HandleStats handles GET /v1/stats
+func (h *APIHandlers) HandleStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.orchestrator.GetStats(r.Context())
 	if err != nil {
 		h.writeErrorResponse(w, fmt.Sprintf("Failed to get stats: %v", err), http.StatusInternalServerError)
 		return
 	}
+	stats["analysis_queue"] = h.queue.Stats()
 
 	h.writeJSONResponse(w, stats, http.StatusOK)
 }
 
+// HandleInsights handles GET /v1/insights?category=, which aggregates the
+// findings of a category's analyses; ?limit= caps each list (default 10)
+func (h *APIHandlers) HandleInsights(w http.ResponseWriter, r *http.Request) {
+	category := strings.TrimSpace(r.URL.Query().Get("category"))
+	if category == "" {
+		h.writeErrorResponse(w, "category is required", http.StatusBadRequest)
+		return
+	}
+	limit, _ := parseLimitOffset(r, 10)
+
+	insights, err := h.orchestrator.GetCategoryInsights(r.Context(), category, limit)
+	if err != nil {
+		if errors.Is(err, app.ErrTooFewAnalyses) {
+			h.writeErrorResponse(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to get insights: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, insights, http.StatusOK)
+}
+
+// HandleAuditLog handles GET /v1/audit, listing audit log entries newest
+// first, optionally filtered by from, to, caller and analysis_id
+func (h *APIHandlers) HandleAuditLog(w http.ResponseWriter, r *http.Request) {
+	limit, offset := parseLimitOffset(r, 50)
+	query := r.URL.Query()
+
+	from, err := parseTimeParam(query.Get("from"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid 'from' date: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(query.Get("to"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid 'to' date: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	filter := store.AuditFilter{From: from, To: to, Caller: query.Get("caller"), AnalysisID: query.Get("analysis_id")}
+	entries, err := h.orchestrator.ListAudit(r.Context(), filter, limit, offset)
+	if err != nil {
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to list audit log: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"entries": entries,
+		"limit":   limit,
+		"offset":  offset,
+	}
+
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// HandleVerifyAuditLog handles GET /v1/audit/verify, which checks the audit
+// log's hash chain for altered or removed entries
+func (h *APIHandlers) HandleVerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	verification, err := h.orchestrator.VerifyAudit(r.Context())
+	if err != nil {
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to verify audit log: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, verification, http.StatusOK)
+}
+
+// HandleAnalytics handles GET /v1/analytics
+func (h *APIHandlers) HandleAnalytics(w http.ResponseWriter, r *http.Request) {
+	analytics, err := h.orchestrator.GetAnalytics(r.Context())
+	if err != nil {
+		h.writeErrorResponse(w, fmt.Sprintf("Failed to get analytics: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, analytics, http.StatusOK)
+}
+
 // handleMarkdownResponse sends analysis as markdown
 func (h *APIHandlers) handleMarkdownResponse(w http.ResponseWriter, analysis types.Analysis) {
 	markdown := h.markdownBuilder.Build(analysis)
@@ -241,6 +1223,69 @@ func (h *APIHandlers) handleHTMLResponse(w http.ResponseWriter, analysis types.A
 	w.Write([]byte(html))
 }
 
+// parseLimitOffset reads limit (1-100) and offset (>= 0) query parameters
+func parseLimitOffset(r *http.Request, defaultLimit int) (int, int) {
+	limit := defaultLimit
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsed > 0 && parsed <= 100 {
+		limit = parsed
+	}
+
+	offset := 0
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && parsed >= 0 {
+		offset = parsed
+	}
+
+	return limit, offset
+}
+
+// buildPagination computes pagination metadata with next/prev links that
+// preserve every other query parameter of the original request
+func buildPagination(r *http.Request, limit, offset, total int) types.Pagination {
+	pagination := types.Pagination{
+		Limit:   limit,
+		Offset:  offset,
+		Total:   total,
+		HasMore: offset+limit < total,
+	}
+
+	pageURL := func(newOffset int) string {
+		q := r.URL.Query()
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(newOffset))
+		u := *r.URL
+		u.RawQuery = q.Encode()
+		return u.RequestURI()
+	}
+
+	if pagination.HasMore {
+		pagination.Next = pageURL(offset + limit)
+	}
+	if offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		pagination.Prev = pageURL(prevOffset)
+	}
+
+	return pagination
+}
+
+// parseTimeParam parses an optional RFC3339 or YYYY-MM-DD query parameter
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 // writeJSONResponse writes a JSON response
 func (h *APIHandlers) writeJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -254,8 +1299,10 @@ func (h *APIHandlers) writeJSONResponse(w http.ResponseWriter, data interface{},
 
 // writeErrorResponse writes an error response
 func (h *APIHandlers) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
-	errorResponse := types.ErrorResponse{
-		Error: message,
-	}
-	h.writeJSONResponse(w, errorResponse, statusCode)
+	WriteError(w, message, statusCode)
+}
+
+// HandleNotFound handles requests that match no route
+func (h *APIHandlers) HandleNotFound(w http.ResponseWriter, r *http.Request) {
+	h.writeErrorResponse(w, "Not found", http.StatusNotFound)
 }
@@ -0,0 +1,68 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rectaify/internal/app"
+	"rectaify/internal/store"
+)
+
+func TestWriteAnalyzeErrorDuplicateID(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := fmt.Errorf("failed to save analysis: %w", fmt.Errorf("%w: abc123", store.ErrDuplicateAnalysisID))
+	(&APIHandlers{}).writeAnalyzeError(rec, err)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("got status %d, want 409", rec.Code)
+	}
+	body := decodeErrorEnvelope(t, rec)
+	if body.Error != err.Error() || body.Code != CodeConflict {
+		t.Errorf("got %+v, want error %q with code %s", body, err.Error(), CodeConflict)
+	}
+}
+
+func TestWriteCancelError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"unknown analysis", store.ErrAnalysisNotFound, http.StatusNotFound, CodeNotFound},
+		{"already finished", app.ErrAnalysisFinished, http.StatusConflict, CodeConflict},
+		{"storage failure", errors.New("connection reset"), http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			(&APIHandlers{}).writeCancelError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if body := decodeErrorEnvelope(t, rec); body.Code != tt.wantCode {
+				t.Errorf("got code %q, want %s", body.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestMaintenanceModeUnavailable(t *testing.T) {
+	handlers := &APIHandlers{orchestrator: app.NewOrchestrator(app.OrchestratorOptions{})}
+	req := httptest.NewRequest(http.MethodPost, "/v1/maintenance/mode", strings.NewReader(`{"enabled": true}`))
+	rec := httptest.NewRecorder()
+	handlers.HandleMaintenanceMode(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("got status %d, want 409", rec.Code)
+	}
+	if body := decodeErrorEnvelope(t, rec); body.Code != CodeConflict {
+		t.Errorf("got code %q, want %s", body.Code, CodeConflict)
+	}
+}
@@ -0,0 +1,46 @@
+package httpx
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Methods routes a request to the handler for its method, so each route
+// declares its verbs once. Other methods are answered 405 and OPTIONS 204,
+// both with an Allow header listing the route's methods. HEAD is served by
+// the GET handler unless one is given.
+type Methods map[string]http.HandlerFunc
+
+func (m Methods) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.Method
+	if _, ok := m[method]; !ok && method == http.MethodHead {
+		method = http.MethodGet
+	}
+	if handler, ok := m[method]; ok {
+		handler(w, r)
+		return
+	}
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Allow", m.Allow())
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	WriteMethodNotAllowed(w, m.Allow())
+}
+
+// Allow lists the methods the route answers, for the Allow header
+func (m Methods) Allow() string {
+	allowed := []string{http.MethodOptions}
+	for method := range m {
+		allowed = append(allowed, method)
+	}
+	if _, ok := m[http.MethodGet]; ok {
+		if _, ok := m[http.MethodHead]; !ok {
+			allowed = append(allowed, http.MethodHead)
+		}
+	}
+	sort.Strings(allowed)
+	return strings.Join(allowed, ", ")
+}
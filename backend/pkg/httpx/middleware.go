@@ -1,9 +1,17 @@
 package httpx
 
 import (
+	"compress/gzip"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
 	"net/http"
 	"strings"
 	"time"
+
+	"rectaify/internal/app"
 )
 
 // AuthMiddleware provides bearer token authentication
@@ -25,18 +33,45 @@ func AuthMiddleware(bearerToken string) func(http.Handler) http.Handler {
 
 			auth := r.Header.Get("Authorization")
 			if auth == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
+				WriteError(w, "Authorization header required", http.StatusUnauthorized)
 				return
 			}
 
 			if !strings.HasPrefix(auth, "Bearer ") {
-				http.Error(w, "Bearer token required", http.StatusUnauthorized)
+				WriteError(w, "Bearer token required", http.StatusUnauthorized)
 				return
 			}
 
 			token := strings.TrimPrefix(auth, "Bearer ")
 			if token != bearerToken {
-				http.Error(w, "Invalid bearer token", http.StatusUnauthorized)
+				WriteError(w, "Invalid bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			// Audited as the key's fingerprint; the key itself is never stored
+			next.ServeHTTP(w, r.WithContext(app.WithCaller(r.Context(), keyFingerprint(token))))
+		})
+	}
+}
+
+// keyFingerprint identifies an API key without revealing it
+func keyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "key:" + hex.EncodeToString(sum[:6])
+}
+
+// AdminMiddleware restricts a handler to callers presenting the admin token in
+// the X-Admin-Token header. Admin endpoints are disabled when no token is configured.
+func AdminMiddleware(adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if adminToken == "" {
+				WriteError(w, "Admin endpoints are disabled", http.StatusForbidden)
+				return
+			}
+
+			if !isAdmin(r, adminToken) {
+				WriteError(w, "Invalid admin token", http.StatusForbidden)
 				return
 			}
 
@@ -45,58 +80,237 @@ func AuthMiddleware(bearerToken string) func(http.Handler) http.Handler {
 	}
 }
 
-// CORSMiddleware adds CORS headers
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		origin := r.Header.Get("Origin")
-
-		// Allow specific origins for development
-		allowedOrigins := []string{
-			"http://localhost:5173", // Vite default
-			"http://localhost:5174", // Vite alternative
-			"http://localhost:5175", // Vite alternative
-			"http://localhost:5176", // User's frontend
-			"http://localhost:3000", // React default
-			"http://localhost:3001", // React alternative
-			"http://127.0.0.1:5173",
-			"http://127.0.0.1:5174",
-			"http://127.0.0.1:5175",
-			"http://127.0.0.1:5176",
-			"http://127.0.0.1:3000",
-			"http://127.0.0.1:3001",
+// isAdmin reports whether a request presents the admin token, for handlers
+// that serve admins more than other callers
+func isAdmin(r *http.Request, adminToken string) bool {
+	// Compared in constant time so response timing doesn't reveal the token
+	return adminToken != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(adminToken)) == 1
+}
+
+// CORSMiddleware adds CORS headers. Listed origins are echoed back with
+// credentials allowed; a "*" entry allows any other origin without credentials.
+func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowAny := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAny = true
+			continue
 		}
+		allowed[origin] = true
+	}
 
-		// Check if origin is allowed
-		isAllowed := false
-		for _, allowed := range allowedOrigins {
-			if origin == allowed {
-				isAllowed = true
-				break
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+
+			// Set CORS headers
+			if allowed[origin] {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+				w.Header().Add("Vary", "Origin")
+			} else if allowAny {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
 			}
-		}
 
-		// Set CORS headers
-		if isAllowed {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
-		} else {
-			// Fallback to wildcard for other origins (production, etc.)
-			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, Origin, X-Requested-With, X-Admin-Token, Cache-Control, Prefer, API-Version")
+			w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Range, X-Cache, API-Version, Deprecation, Sunset, Link")
+			w.Header().Set("Access-Control-Max-Age", "86400") // 24 hours
+
+			// Answer CORS preflights here; plain OPTIONS requests reach the
+			// route, which lists its methods in the Allow header
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SecurityHeadersMiddleware sets defensive response headers. Framing is denied
+// except for HTML report routes, which may be embedded by the same origin.
+// HSTS is only sent over TLS (directly or via a proxy) and when hstsMaxAge > 0.
+func SecurityHeadersMiddleware(hstsMaxAge time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("Referrer-Policy", "no-referrer")
+
+			if isReportRoute(r.URL.Path) {
+				h.Set("X-Frame-Options", "SAMEORIGIN")
+			} else {
+				h.Set("X-Frame-Options", "DENY")
+			}
+
+			if hstsMaxAge > 0 && (r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https") {
+				h.Set("Strict-Transport-Security", fmt.Sprintf("max-age=%d; includeSubDomains", int(hstsMaxAge.Seconds())))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isReportRoute reports whether the path serves a rendered HTML report
+func isReportRoute(path string) bool {
+	return strings.HasPrefix(path, "/v1/analyses/") && strings.HasSuffix(path, ".html")
+}
+
+// GzipMiddleware compresses responses of at least minSize bytes for clients
+// that accept gzip. Already-encoded, already-compressed and event-stream
+// responses are passed through untouched. It should sit inside
+// LoggingMiddleware so the logged status is the one actually sent.
+func GzipMiddleware(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize, statusCode: http.StatusOK}
+			defer gw.Close()
+
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
 		}
+		q := strings.ReplaceAll(strings.ToLower(params), " ", "")
+		if q == "q=0" || q == "q=0.0" || q == "q=0.00" || q == "q=0.000" {
+			return false
+		}
+		return true
+	}
+	return false
+}
 
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, Origin, X-Requested-With")
-		w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Range")
-		w.Header().Set("Access-Control-Max-Age", "86400") // 24 hours
+// gzipResponseWriter buffers the start of a response until it knows whether
+// the body is large enough to be worth compressing
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize     int
+	statusCode  int
+	buf         []byte
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+func (gw *gzipResponseWriter) WriteHeader(code int) {
+	if gw.gz == nil && !gw.passthrough {
+		gw.statusCode = code
+	}
+}
+
+func (gw *gzipResponseWriter) Write(p []byte) (int, error) {
+	switch {
+	case gw.gz != nil:
+		return gw.gz.Write(p)
+	case gw.passthrough:
+		return gw.ResponseWriter.Write(p)
+	}
 
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+	gw.buf = append(gw.buf, p...)
+	if len(gw.buf) >= gw.minSize {
+		if err := gw.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush commits to compression (if eligible) so streamed responses are not held back
+func (gw *gzipResponseWriter) Flush() {
+	if gw.gz == nil && !gw.passthrough {
+		if err := gw.start(true); err != nil {
 			return
 		}
+	}
+	if gw.gz != nil {
+		gw.gz.Flush()
+	}
+	if f, ok := gw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
 
-		next.ServeHTTP(w, r)
-	})
+// Unwrap exposes the underlying writer to http.ResponseController
+func (gw *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// Close finishes the response, sending small bodies uncompressed
+func (gw *gzipResponseWriter) Close() error {
+	if gw.gz != nil {
+		return gw.gz.Close()
+	}
+	if !gw.passthrough {
+		return gw.start(false)
+	}
+	return nil
+}
+
+// start writes the headers and buffered body, compressing when requested and
+// the content type allows it
+func (gw *gzipResponseWriter) start(compress bool) error {
+	h := gw.Header()
+	if h.Get("Content-Type") == "" && len(gw.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(gw.buf))
+	}
+
+	if compress && shouldCompress(h, gw.statusCode) {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		gw.ResponseWriter.WriteHeader(gw.statusCode)
+		gw.gz = gzip.NewWriter(gw.ResponseWriter)
+		_, err := gw.gz.Write(gw.buf)
+		gw.buf = nil
+		return err
+	}
+
+	gw.passthrough = true
+	gw.ResponseWriter.WriteHeader(gw.statusCode)
+	if len(gw.buf) == 0 {
+		return nil
+	}
+	_, err := gw.ResponseWriter.Write(gw.buf)
+	gw.buf = nil
+	return err
+}
+
+// shouldCompress reports whether a response with these headers benefits from gzip
+func shouldCompress(h http.Header, statusCode int) bool {
+	if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified || h.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := strings.ToLower(h.Get("Content-Type"))
+	switch {
+	case strings.HasPrefix(contentType, "text/event-stream"),
+		strings.HasPrefix(contentType, "image/") && !strings.HasPrefix(contentType, "image/svg"),
+		strings.HasPrefix(contentType, "video/"),
+		strings.HasPrefix(contentType, "audio/"),
+		strings.HasPrefix(contentType, "font/woff"),
+		strings.Contains(contentType, "zip"),
+		strings.Contains(contentType, "compressed"):
+		return false
+	}
+	return true
 }
 
 // LoggingMiddleware logs HTTP requests
@@ -130,3 +344,34 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Flush forwards to the underlying writer so streaming handlers work through the middleware
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// WriteTimeoutMiddleware replaces the server-wide write timeout for routes
+// that run whole analyses or stream their response, which can legitimately
+// outlast it. A zero timeout removes the write deadline altogether.
+func WriteTimeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var deadline time.Time
+			if timeout > 0 {
+				deadline = time.Now().Add(timeout)
+			}
+			if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil {
+				log.Printf("Failed to extend write deadline for %s: %v", r.URL.Path, err)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
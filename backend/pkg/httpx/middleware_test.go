@@ -0,0 +1,105 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rectaify/pkg/types"
+)
+
+// testServer chains the middleware the API wraps its routes in around a route
+// that answers GET and DELETE
+func testServer(bearerToken string) http.Handler {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+	mux := http.NewServeMux()
+	mux.Handle("/v1/analyses/abc", Methods{http.MethodGet: ok, http.MethodDelete: ok})
+
+	var handler http.Handler = mux
+	handler = AuthMiddleware(bearerToken)(handler)
+	return CORSMiddleware([]string{"*"})(handler)
+}
+
+// decodeErrorEnvelope checks a response is the JSON ErrorResponse envelope
+func decodeErrorEnvelope(t *testing.T, rec *httptest.ResponseRecorder) types.ErrorResponse {
+	t.Helper()
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("got Content-Type %q, want application/json", contentType)
+	}
+	var body types.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("error body is not JSON: %v\n%s", err, rec.Body.String())
+	}
+	if body.Error == "" {
+		t.Errorf("error envelope has no message: %s", rec.Body.String())
+	}
+	return body
+}
+
+func TestAuthFailureUsesErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantError     string
+	}{
+		{"missing header", "", "Authorization header required"},
+		{"not a bearer token", "Basic dXNlcjpwYXNz", "Bearer token required"},
+		{"wrong token", "Bearer nope", "Invalid bearer token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/analyses/abc", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			testServer("secret").ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("got status %d, want 401", rec.Code)
+			}
+			body := decodeErrorEnvelope(t, rec)
+			if body.Code != CodeUnauthorized || body.Error != tt.wantError {
+				t.Errorf("got %+v, want code %s and error %q", body, CodeUnauthorized, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestMethodNotAllowedUsesErrorEnvelope(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/v1/analyses/abc", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	testServer("secret").ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d, want 405", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "DELETE, GET, HEAD, OPTIONS" {
+		t.Errorf("got Allow %q, want DELETE, GET, HEAD, OPTIONS", allow)
+	}
+	body := decodeErrorEnvelope(t, rec)
+	if body.Code != CodeMethodNotAllowed {
+		t.Errorf("got code %q, want %s", body.Code, CodeMethodNotAllowed)
+	}
+}
+
+func TestAdminFailureUsesErrorEnvelope(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	for _, adminToken := range []string{"", "admin"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/admin/stats", nil)
+		req.Header.Set("X-Admin-Token", "wrong")
+		rec := httptest.NewRecorder()
+		AdminMiddleware(adminToken)(next).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("admin token %q: got status %d, want 403", adminToken, rec.Code)
+		}
+		if body := decodeErrorEnvelope(t, rec); body.Code != CodeForbidden {
+			t.Errorf("admin token %q: got code %q, want %s", adminToken, body.Code, CodeForbidden)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package httpx
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AnalysisRoutes dispatches requests under /v1/analyses/ by path: an analysis
+// itself, its read-only views (reports, diff, sensitivity), and the actions
+// that share the prefix. Each handler answers the methods it supports.
+type AnalysisRoutes struct {
+	Analysis  http.Handler // /v1/analyses/{id}
+	View      http.Handler // /v1/analyses/{id}.md, .html and /{id}/...
+	Reverdict http.Handler // /v1/analyses/{id}/reverdict
+	Refresh   http.Handler // /v1/analyses/{id}/refresh
+	Cancel    http.Handler // /v1/analyses/{id}/cancel
+}
+
+func (a AnalysisRoutes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/v1/analyses/")
+	switch {
+	case strings.HasSuffix(id, "/reverdict"):
+		a.Reverdict.ServeHTTP(w, r)
+	case strings.HasSuffix(id, "/refresh"):
+		a.Refresh.ServeHTTP(w, r)
+	case strings.HasSuffix(id, "/cancel"):
+		a.Cancel.ServeHTTP(w, r)
+	case strings.Contains(id, "/") || strings.HasSuffix(id, ".md") || strings.HasSuffix(id, ".html"):
+		a.View.ServeHTTP(w, r)
+	default:
+		a.Analysis.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,89 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// recordRoute answers with the route's name so tests can see where a request went
+func recordRoute(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Route", name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func testAnalysisRoutes() AnalysisRoutes {
+	return AnalysisRoutes{
+		Analysis: Methods{
+			http.MethodGet:    recordRoute("get"),
+			http.MethodPatch:  recordRoute("update"),
+			http.MethodDelete: recordRoute("delete"),
+		},
+		View:      Methods{http.MethodGet: recordRoute("view")},
+		Reverdict: Methods{http.MethodPost: recordRoute("reverdict")},
+		Refresh:   Methods{http.MethodPost: recordRoute("refresh")},
+		Cancel:    Methods{http.MethodDelete: recordRoute("cancel")},
+	}
+}
+
+func TestAnalysisRoutes(t *testing.T) {
+	tests := []struct {
+		method, path string
+		wantStatus   int
+		wantRoute    string
+	}{
+		{http.MethodGet, "/v1/analyses/abc123", http.StatusNoContent, "get"},
+		{http.MethodHead, "/v1/analyses/abc123", http.StatusNoContent, "get"},
+		{http.MethodDelete, "/v1/analyses/abc123", http.StatusNoContent, "delete"},
+		{http.MethodPatch, "/v1/analyses/abc123", http.StatusNoContent, "update"},
+		{http.MethodPut, "/v1/analyses/abc123", http.StatusMethodNotAllowed, ""},
+		{http.MethodPost, "/v1/analyses/abc123", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/v1/analyses/abc123.md", http.StatusNoContent, "view"},
+		{http.MethodGet, "/v1/analyses/abc123.html", http.StatusNoContent, "view"},
+		{http.MethodGet, "/v1/analyses/abc123/diff/def456", http.StatusNoContent, "view"},
+		{http.MethodDelete, "/v1/analyses/abc123.md", http.StatusMethodNotAllowed, ""},
+		{http.MethodPost, "/v1/analyses/abc123/reverdict", http.StatusNoContent, "reverdict"},
+		{http.MethodPost, "/v1/analyses/abc123/refresh", http.StatusNoContent, "refresh"},
+		{http.MethodDelete, "/v1/analyses/abc123/cancel", http.StatusNoContent, "cancel"},
+		{http.MethodGet, "/v1/analyses/abc123/cancel", http.StatusMethodNotAllowed, ""},
+	}
+
+	routes := testAnalysisRoutes()
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			routes.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if route := rec.Header().Get("X-Route"); route != tt.wantRoute {
+				t.Errorf("routed to %q, want %q", route, tt.wantRoute)
+			}
+		})
+	}
+}
+
+func TestAnalysisRouteMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	testAnalysisRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v1/analyses/abc123", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d, want 405", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "DELETE, GET, HEAD, OPTIONS, PATCH" {
+		t.Errorf("got Allow %q, want DELETE, GET, HEAD, OPTIONS, PATCH", allow)
+	}
+	if body := decodeErrorEnvelope(t, rec); body.Code != CodeMethodNotAllowed {
+		t.Errorf("got code %q, want %s", body.Code, CodeMethodNotAllowed)
+	}
+
+	// OPTIONS lists the same methods without an error
+	rec = httptest.NewRecorder()
+	testAnalysisRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/v1/analyses/abc123", nil))
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Allow") != "DELETE, GET, HEAD, OPTIONS, PATCH" {
+		t.Errorf("OPTIONS got status %d and Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
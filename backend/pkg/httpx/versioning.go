@@ -0,0 +1,115 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurrentAPIVersion is the newest response shape the API serves. Clients pick
+// an older one with the API-Version request header while it is still served.
+const CurrentAPIVersion = 1
+
+// HeaderAPIVersion names the request header choosing a response shape and the
+// response header reporting the one served
+const HeaderAPIVersion = "API-Version"
+
+// Deprecation marks a route, or the response shapes older than a version, as
+// going away
+type Deprecation struct {
+	Since         time.Time // when the route was deprecated; zero sends "Deprecation: true"
+	Sunset        time.Time // when it stops being served; zero when undecided
+	Link          string    // successor route or migration guide
+	BeforeVersion int       // only requests for older API versions are deprecated; 0 is every request
+}
+
+// routeDeprecation is a deprecation registered for a path
+type routeDeprecation struct {
+	route string
+	Deprecation
+}
+
+// RouteRegistry tags routes with deprecations. A route ending in "/" covers
+// every path under it; the longest matching route wins.
+type RouteRegistry struct {
+	deprecations []routeDeprecation
+}
+
+// NewRouteRegistry creates an empty route registry
+func NewRouteRegistry() *RouteRegistry {
+	return &RouteRegistry{}
+}
+
+// Deprecate marks a route as deprecated
+func (rr *RouteRegistry) Deprecate(route string, deprecation Deprecation) {
+	rr.deprecations = append(rr.deprecations, routeDeprecation{route: route, Deprecation: deprecation})
+}
+
+// lookup finds the deprecation covering a path
+func (rr *RouteRegistry) lookup(path string) (Deprecation, bool) {
+	var best *routeDeprecation
+	for i, d := range rr.deprecations {
+		matches := path == d.route || (strings.HasSuffix(d.route, "/") && strings.HasPrefix(path, d.route))
+		if matches && (best == nil || len(d.route) > len(best.route)) {
+			best = &rr.deprecations[i]
+		}
+	}
+	if best == nil {
+		return Deprecation{}, false
+	}
+	return best.Deprecation, true
+}
+
+// RequestedAPIVersion returns the API version a request asked for with the
+// API-Version header, or CurrentAPIVersion when it didn't
+func RequestedAPIVersion(r *http.Request) (int, error) {
+	header := strings.TrimPrefix(strings.TrimSpace(r.Header.Get(HeaderAPIVersion)), "v")
+	if header == "" {
+		return CurrentAPIVersion, nil
+	}
+	version, err := strconv.Atoi(header)
+	if err != nil || version < 1 || version > CurrentAPIVersion {
+		return 0, fmt.Errorf("unsupported %s %q; versions 1 to %d are served", HeaderAPIVersion, r.Header.Get(HeaderAPIVersion), CurrentAPIVersion)
+	}
+	return version, nil
+}
+
+// VersioningMiddleware rejects unsupported API versions, reports the version
+// served, and adds Deprecation, Sunset and Link headers to requests for
+// deprecated routes so clients are warned before anything breaks
+func VersioningMiddleware(registry *RouteRegistry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			version, err := RequestedAPIVersion(r)
+			if err != nil {
+				WriteError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set(HeaderAPIVersion, strconv.Itoa(version))
+
+			if deprecation, ok := registry.lookup(r.URL.Path); ok && (deprecation.BeforeVersion == 0 || version < deprecation.BeforeVersion) {
+				setDeprecationHeaders(w.Header(), deprecation)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// setDeprecationHeaders writes the headers of RFC 9745 (Deprecation) and
+// RFC 8594 (Sunset)
+func setDeprecationHeaders(header http.Header, deprecation Deprecation) {
+	if deprecation.Since.IsZero() {
+		header.Set("Deprecation", "true")
+	} else {
+		header.Set("Deprecation", "@"+strconv.FormatInt(deprecation.Since.Unix(), 10))
+	}
+	if !deprecation.Sunset.IsZero() {
+		header.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if deprecation.Link != "" {
+		header.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", deprecation.Link))
+	}
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -22,6 +23,24 @@ type Evidence struct {
 	PublishedAt *time.Time `json:"published_at,omitempty" db:"published_at"`
 	RetrievedAt time.Time  `json:"retrieved_at" db:"retrieved_at"`
 	SourceType  string     `json:"source_type,omitempty" db:"source_type"`
+	ContentType string     `json:"content_type,omitempty" db:"content_type"` // pdf, document, video or paywalled; empty for articles
+	// Intents of the queries that retrieved the evidence during this search,
+	// e.g. "funding"; not stored, as other analyses may find it for others
+	Intents []string `json:"intents,omitempty"`
+}
+
+// MissingContent reports evidence kept on its title alone: a document, video
+// or paywalled page whose content the search provider couldn't read
+func (e Evidence) MissingContent() bool {
+	return e.ContentType != "" && e.Snippet == ""
+}
+
+// EvidenceDetail is a stored evidence item with its quality score and the
+// analyses that cite it
+type EvidenceDetail struct {
+	Evidence
+	QualityScore float64  `json:"quality_score"`
+	AnalysisIDs  []string `json:"analysis_ids"` // newest first
 }
 
 // Competitor represents market competition analysis
@@ -31,6 +50,104 @@ type Competitor struct {
 	Funding     string   `json:"funding,omitempty"`
 	Stage       string   `json:"stage,omitempty"`
 	EvidenceIDs []string `json:"evidence_ids"`
+	Overlap     int      `json:"overlap,omitempty"`      // 1-5 LLM-assessed overlap with the idea
+	ThreatScore float64  `json:"threat_score,omitempty"` // 0-100, higher is a bigger threat
+
+	FundingNormalized *MoneyAmount `json:"funding_normalized,omitempty"` // Funding in US dollars
+	Unsupported       bool         `json:"unsupported,omitempty"`        // cites no valid evidence
+}
+
+// MoneyAmount is a monetary figure normalized to US dollars. A range keeps its
+// bounds in USDMin and USDMax with USD at the midpoint.
+type MoneyAmount struct {
+	Original        string  `json:"original"`
+	USD             float64 `json:"usd,omitempty"`
+	USDMin          float64 `json:"usd_min,omitempty"`
+	USDMax          float64 `json:"usd_max,omitempty"`
+	Currency        string  `json:"currency,omitempty"`         // ISO 4217 code of the original figure
+	CurrencyAssumed bool    `json:"currency_assumed,omitempty"` // no currency was stated, so USD was assumed
+	Parsed          bool    `json:"parsed"`                     // false when Original could not be read as an amount
+}
+
+// CompetitorAggregate represents a competitor seen across multiple analyses
+type CompetitorAggregate struct {
+	Name        string   `json:"name"`
+	Occurrences int      `json:"occurrences"`
+	AnalysisIDs []string `json:"analysis_ids"`
+
+	MaxFundingUSD *float64 `json:"max_funding_usd,omitempty"` // largest normalized funding reported
+}
+
+// CategoryAnalytics holds average scores for analyses in one category
+type CategoryAnalytics struct {
+	Category          string  `json:"category"`
+	Count             int     `json:"count"`
+	AvgOverallScore   float64 `json:"avg_overall_score"`
+	AvgMarketScore    float64 `json:"avg_market_score"`
+	AvgProblemScore   float64 `json:"avg_problem_score"`
+	AvgBarrierScore   float64 `json:"avg_barrier_score"`
+	AvgExecutionScore float64 `json:"avg_execution_score"`
+	AvgRiskScore      float64 `json:"avg_risk_score"`
+	AvgGraveyardScore float64 `json:"avg_graveyard_score"`
+}
+
+// AuditEntry records one analysis request and what it returned. Entries are
+// hash-chained: Hash covers the entry and PrevHash, the hash of the entry
+// before it.
+type AuditEntry struct {
+	ID           int64     `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Caller       string    `json:"caller"` // fingerprint of the API key used, or "anonymous"
+	Action       string    `json:"action"` // analyze or refresh
+	AnalysisID   string    `json:"analysis_id,omitempty"`
+	IdeaTitle    string    `json:"idea_title"` // scrubbed like stored ideas
+	Category     string    `json:"category,omitempty"`
+	Outcome      string    `json:"outcome"` // completed, cached or failed
+	Verdict      string    `json:"verdict,omitempty"`
+	OverallScore *float64  `json:"overall_score,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	PrevHash     string    `json:"prev_hash"`
+	Hash         string    `json:"hash"`
+}
+
+// AuditVerification is the result of checking the audit log's hash chain
+type AuditVerification struct {
+	Entries  int    `json:"entries"`
+	Valid    bool   `json:"valid"`
+	BrokenAt int64  `json:"broken_at,omitempty"` // ID of the first entry that fails the check
+	Reason   string `json:"reason,omitempty"`
+	HeadHash string `json:"head_hash,omitempty"` // hash of the last entry; keep it elsewhere to detect removal of the newest entries
+}
+
+// CategoryInsights aggregates the findings of the analyses in one category:
+// their average scores and the risks, barriers and failure causes they share
+type CategoryInsights struct {
+	CategoryAnalytics
+	Risks         []InsightCount `json:"risks"`          // risk categories; average is severity (1-5)
+	BarrierTypes  []InsightCount `json:"barrier_types"`  // average is weight (0-1)
+	FailureCauses []InsightCount `json:"failure_causes"` // of graveyard cases, grouped as written
+}
+
+// InsightCount is a finding shared by analyses of a category
+type InsightCount struct {
+	Value    string  `json:"value"`
+	Analyses int     `json:"analyses"` // analyses with the finding at least once
+	Share    float64 `json:"share"`    // of the category's analyses, 0-1
+	Average  float64 `json:"average,omitempty"`
+}
+
+// ScoreBucket counts analyses whose overall score falls in [Min, Max)
+type ScoreBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// Analytics aggregates scores across all analyses
+type Analytics struct {
+	TotalAnalyses int                 `json:"total_analyses"`
+	ByCategory    []CategoryAnalytics `json:"by_category"`
+	Distribution  []ScoreBucket       `json:"distribution"`
 }
 
 // Risk represents identified business risks
@@ -41,6 +158,7 @@ type Risk struct {
 	Likelihood  int      `json:"likelihood"` // 1-5 scale
 	Mitigation  string   `json:"mitigation,omitempty"`
 	EvidenceIDs []string `json:"evidence_ids"`
+	Unsupported bool     `json:"unsupported,omitempty"` // cites no valid evidence
 }
 
 // Barrier represents execution barriers
@@ -49,6 +167,7 @@ type Barrier struct {
 	Description string   `json:"description"`
 	Weight      float64  `json:"weight"` // 0.0-1.0
 	EvidenceIDs []string `json:"evidence_ids"`
+	Unsupported bool     `json:"unsupported,omitempty"` // cites no valid evidence
 }
 
 // GraveyardCase represents a failed similar startup
@@ -58,6 +177,7 @@ type GraveyardCase struct {
 	FailureCause string  `json:"failure_cause"`
 	Lessons     string   `json:"lessons"`
 	EvidenceIDs []string `json:"evidence_ids"`
+	Unsupported bool     `json:"unsupported,omitempty"` // cites no valid evidence
 }
 
 // MarketAnalysis represents market size and competition analysis
@@ -98,8 +218,33 @@ type RiskAnalysis struct {
 
 // GraveyardAnalysis represents analysis of failed similar companies
 type GraveyardAnalysis struct {
-	Cases       []GraveyardCase `json:"cases"`
-	EvidenceIDs []string        `json:"evidence_ids"`
+	Cases       []GraveyardCase   `json:"cases"`
+	EvidenceIDs []string          `json:"evidence_ids"`
+	Search      *PostmortemSearch `json:"search,omitempty"` // nil for analyses that predate search stats
+}
+
+// PostmortemSearch summarizes how thoroughly failures were searched for, so an
+// empty graveyard can be told apart from one that was never looked for
+type PostmortemSearch struct {
+	Queries  int `json:"queries"`  // postmortem queries planned
+	Answered int `json:"answered"` // postmortem queries that returned results
+	Results  int `json:"results"`  // raw results across all postmortem queries
+}
+
+// VerdictTier is the machine-readable verdict band derived from the overall score
+type VerdictTier string
+
+const (
+	VerdictStrongGo VerdictTier = "strong_go"
+	VerdictGo       VerdictTier = "go"
+	VerdictCaution  VerdictTier = "caution"
+	VerdictHighRisk VerdictTier = "high_risk"
+	VerdictNoGo     VerdictTier = "no_go"
+)
+
+// Label returns the human-readable verdict label, e.g. "STRONG GO"
+func (v VerdictTier) Label() string {
+	return strings.ToUpper(strings.ReplaceAll(string(v), "_", " "))
 }
 
 // Viability represents the final verdict
@@ -112,25 +257,297 @@ type Viability struct {
 	RiskScore       float64 `json:"risk_score"`
 	GraveyardScore  float64 `json:"graveyard_score"`
 	Recommendation  string  `json:"recommendation"`
-	KeyInsights     []string `json:"key_insights"`
+	Verdict         VerdictTier `json:"verdict,omitempty"` // machine-readable band of OverallScore
+	KeyInsights     []KeyInsight `json:"key_insights"`
 	EvidenceIDs     []string `json:"evidence_ids"`
+	Confidence      float64  `json:"confidence,omitempty"` // 0-1, reduced when evidence is weak or stale
+	ScoreRationale  map[string]string `json:"score_rationale,omitempty"` // dimension to how its score was reached
+	// Fallback marks a verdict computed without LLM insights because
+	// enhancing it failed; the recommendation is then the calculator's
+	Fallback bool `json:"fallback,omitempty"`
+}
+
+// SourceDiversity is how many distinct domains back a dimension's cited
+// evidence. Confidence is lowered in proportion when fewer domains than
+// required back it, which also sets Narrow.
+type SourceDiversity struct {
+	Domains    []string `json:"domains"`
+	Cited      int      `json:"cited"`      // evidence items the dimension cites
+	Confidence float64  `json:"confidence"` // 0-1
+	Narrow     bool     `json:"narrow,omitempty"`
+}
+
+// Coherence is how closely the findings of an analysis match the idea it was
+// asked about. Suspect marks a score below Minimum, which lowers the
+// verdict's confidence.
+type Coherence struct {
+	Score    float64  `json:"score"`               // 0-1
+	Minimum  float64  `json:"minimum"`             // score required when the analysis was checked
+	OffTopic []string `json:"off_topic,omitempty"` // dimensions whose findings drifted from the idea
+	Reason   string   `json:"reason,omitempty"`
+	Suspect  bool     `json:"suspect,omitempty"`
+}
+
+// KeyInsight is a verdict insight with the evidence that supports it
+type KeyInsight struct {
+	Text        string   `json:"text"`
+	EvidenceIDs []string `json:"evidence_ids"`
+}
+
+// UnmarshalJSON also accepts the plain-string insights stored by older analyses
+func (k *KeyInsight) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*k = KeyInsight{Text: text}
+		return nil
+	}
+
+	type keyInsight KeyInsight
+	var insight keyInsight
+	if err := json.Unmarshal(data, &insight); err != nil {
+		return err
+	}
+	*k = KeyInsight(insight)
+	return nil
+}
+
+// DimensionEvent reports one dimension analyzer finishing while an analysis
+// streams. Result is the dimension's analysis, before the evidence policy and
+// scoring are applied; Error is set instead when the analyzer failed.
+type DimensionEvent struct {
+	Dimension string      `json:"dimension"`
+	Result    interface{} `json:"result,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// RecommendationEvent carries the next part of the verdict recommendation
+// while an analysis streams it
+type RecommendationEvent struct {
+	Text string `json:"text"`
+}
+
+// DimensionResults holds the output of the six dimension analyzers, as cached
+// for reuse by analyses with identical inputs
+type DimensionResults struct {
+	Market    MarketAnalysis    `json:"market"`
+	Problem   ProblemAnalysis   `json:"problem"`
+	Barriers  BarrierAnalysis   `json:"barriers"`
+	Execution ExecutionAnalysis `json:"execution"`
+	Risks     RiskAnalysis      `json:"risks"`
+	Graveyard GraveyardAnalysis `json:"graveyard"`
 }
 
 // Analysis represents the complete analysis result
 type Analysis struct {
-	ID            string             `json:"id"`
-	Idea          IdeaInput          `json:"idea"`
-	Market        MarketAnalysis     `json:"market"`
-	Problem       ProblemAnalysis    `json:"problem"`
-	Barriers      BarrierAnalysis    `json:"barriers"`
-	Execution     ExecutionAnalysis  `json:"execution"`
-	Risks         RiskAnalysis       `json:"risks"`
-	Graveyard     GraveyardAnalysis  `json:"graveyard"`
-	Verdict       Viability          `json:"verdict"`
-	Evidence      []Evidence         `json:"evidence"`
-	CreatedAt     time.Time          `json:"created_at"`
-	Partial       bool               `json:"partial,omitempty"` // if analysis was incomplete
-	Meta          json.RawMessage    `json:"meta,omitempty"`    // analyzer raw outputs and validation
+	ID             string            `json:"id"`
+	Idea           IdeaInput         `json:"idea"`
+	Tags           []string          `json:"tags,omitempty"` // lowercase labels grouping analyses, e.g. "q1-screening"
+	Market         MarketAnalysis    `json:"market"`
+	Problem        ProblemAnalysis   `json:"problem"`
+	Barriers       BarrierAnalysis   `json:"barriers"`
+	Execution      ExecutionAnalysis `json:"execution"`
+	Risks          RiskAnalysis      `json:"risks"`
+	Graveyard      GraveyardAnalysis `json:"graveyard"`
+	Verdict        Viability         `json:"verdict"`
+	VerdictVersion int               `json:"verdict_version,omitempty"` // bumped each time the verdict is regenerated
+	// Flagged marks an overall score below ReviewThreshold, the review gate the
+	// analysis was screened against; 0 when there was none
+	Flagged         bool    `json:"flagged,omitempty"`
+	ReviewThreshold float64 `json:"review_threshold,omitempty"`
+	// Pinned keeps the analysis, and its evidence, from the retention job
+	Pinned          bool    `json:"pinned,omitempty"`
+	Summary        string            `json:"summary,omitempty"`         // 2-3 sentence TL;DR
+	NextSteps      []ValidationStep  `json:"next_steps,omitempty"`      // validation plan, most impactful first
+	Evidence       []Evidence        `json:"evidence"`
+	// EvidenceCitations maps each cited evidence ID to the dimensions citing it
+	EvidenceCitations map[string][]string `json:"evidence_citations,omitempty"`
+	// SourceDiversity counts the domains behind the evidence each dimension
+	// cites; dimensions citing no evidence have no entry
+	SourceDiversity map[string]SourceDiversity `json:"source_diversity,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	Partial        bool              `json:"partial,omitempty"` // if analysis was incomplete
+	Warnings       []string          `json:"warnings,omitempty"`
+	Meta           json.RawMessage   `json:"meta,omitempty"` // analyzer raw outputs and validation
+}
+
+// AnalysisScores is the scores-only projection of an analysis, for list views
+// that don't need evidence, dimension details or meta
+type AnalysisScores struct {
+	ID             string    `json:"id"`
+	Idea           IdeaInput `json:"idea"`
+	Tags           []string  `json:"tags,omitempty"`
+	Verdict        Viability `json:"verdict"`
+	VerdictVersion int       `json:"verdict_version,omitempty"`
+	Flagged        bool      `json:"flagged,omitempty"`
+	Pinned         bool      `json:"pinned,omitempty"`
+	Summary        string    `json:"summary,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	Partial        bool      `json:"partial,omitempty"`
+}
+
+// Sensitivity shows how robust an analysis's overall score and verdict band
+// are to small changes in its dimension scores and weighting
+type Sensitivity struct {
+	AnalysisID   string                 `json:"analysis_id"`
+	OverallScore float64                `json:"overall_score"`
+	Verdict      VerdictTier            `json:"verdict"`
+	Delta        float64                `json:"delta"`      // points each dimension is moved up and down
+	Dimensions   []DimensionSensitivity `json:"dimensions"` // closest to flipping the verdict first
+	Pivotal      []string               `json:"pivotal"`    // dimensions whose move by Delta changes the verdict band
+	Profiles     []ProfileScore         `json:"profiles"`
+	ScoreMin     float64                `json:"score_min"` // lowest overall score across moves and profiles
+	ScoreMax     float64                `json:"score_max"` // highest overall score across moves and profiles
+}
+
+// DimensionSensitivity is the overall score with one dimension moved by the delta
+type DimensionSensitivity struct {
+	Dimension    string      `json:"dimension"`
+	Score        float64     `json:"score"`
+	Weight       float64     `json:"weight"`
+	LowScore     float64     `json:"low_score"`  // overall score with the dimension lowered
+	HighScore    float64     `json:"high_score"` // overall score with the dimension raised
+	LowVerdict   VerdictTier `json:"low_verdict"`
+	HighVerdict  VerdictTier `json:"high_verdict"`
+	PointsToFlip float64     `json:"points_to_flip,omitempty"` // smallest move that changes the verdict band; unset when none can
+	Pivotal      bool        `json:"pivotal"`
+}
+
+// ProfileScore is the overall score under an alternative weight profile
+type ProfileScore struct {
+	Profile      string      `json:"profile"`
+	OverallScore float64     `json:"overall_score"`
+	Verdict      VerdictTier `json:"verdict"`
+}
+
+// SetMeta merges a key into the analysis meta object, preserving existing keys
+func (a *Analysis) SetMeta(key string, value interface{}) error {
+	meta := make(map[string]json.RawMessage)
+	if len(a.Meta) > 0 {
+		if err := json.Unmarshal(a.Meta, &meta); err != nil {
+			return err
+		}
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	meta[key] = raw
+
+	merged, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	a.Meta = merged
+	return nil
+}
+
+// DeleteMeta removes a key from the analysis meta object
+func (a *Analysis) DeleteMeta(key string) error {
+	if len(a.Meta) == 0 {
+		return nil
+	}
+
+	meta := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(a.Meta, &meta); err != nil {
+		return err
+	}
+	if _, ok := meta[key]; !ok {
+		return nil
+	}
+	delete(meta, key)
+
+	remaining, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	a.Meta = remaining
+	return nil
+}
+
+// ScoreDelta represents the change in one scoring dimension between two analyses
+type ScoreDelta struct {
+	Dimension string  `json:"dimension"`
+	Before    float64 `json:"before"`
+	After     float64 `json:"after"`
+	Delta     float64 `json:"delta"`
+}
+
+// AnalysisDiff represents what changed between two analyses of the same idea
+type AnalysisDiff struct {
+	BaseID                string       `json:"base_id"`
+	CompareID             string       `json:"compare_id"`
+	ScoreDeltas           []ScoreDelta `json:"score_deltas"`
+	AddedCompetitors      []string     `json:"added_competitors"`
+	DroppedCompetitors    []string     `json:"dropped_competitors"`
+	NewRisks              []Risk       `json:"new_risks"`
+	DroppedRisks          []Risk       `json:"dropped_risks"`
+	RecommendationBefore  string       `json:"recommendation_before"`
+	RecommendationAfter   string       `json:"recommendation_after"`
+	RecommendationChanged bool         `json:"recommendation_changed"`
+	Summary               string       `json:"summary"`
+	Notes                 []string     `json:"notes,omitempty"`
+}
+
+// ReverdictRequest selects stored analyses whose verdicts should be regenerated
+type ReverdictRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// ReverdictResult reports how a regenerated verdict differs from the stored one
+type ReverdictResult struct {
+	AnalysisID     string       `json:"analysis_id"`
+	VerdictVersion int          `json:"verdict_version,omitempty"`
+	VerdictBefore  VerdictTier  `json:"verdict_before,omitempty"`
+	VerdictAfter   VerdictTier  `json:"verdict_after,omitempty"`
+	ScoreDeltas    []ScoreDelta `json:"score_deltas,omitempty"`
+	Error          string       `json:"error,omitempty"` // set when this analysis could not be regenerated
+}
+
+// RefineRequest selects a completed analysis to suggest refinements for
+type RefineRequest struct {
+	AnalysisID string `json:"analysis_id"`
+}
+
+// DimensionScore is one scoring dimension and its score
+type DimensionScore struct {
+	Dimension string  `json:"dimension"`
+	Score     float64 `json:"score"`
+}
+
+// Refinement is a suggested change to an idea that addresses its weakest dimensions
+type Refinement struct {
+	Title       string   `json:"title"`
+	Change      string   `json:"change"`    // what to do differently
+	Addresses   []string `json:"addresses"` // dimensions the change should improve
+	Rationale   string   `json:"rationale"`
+	EvidenceIDs []string `json:"evidence_ids"`
+}
+
+// ValidationStep is an experiment or piece of research that would de-risk a
+// weak dimension of an idea
+type ValidationStep struct {
+	Action        string   `json:"action"`
+	Dimension     string   `json:"dimension"`      // weak dimension the step de-risks
+	SuccessSignal string   `json:"success_signal"` // result showing the weakness is less severe
+	EvidenceIDs   []string `json:"evidence_ids"`
+}
+
+// IdeaExpansion is the LLM's reading of a vague idea: who it is for, what
+// it does for them and where it fits. It enriches query planning only; the
+// idea itself is stored as submitted.
+type IdeaExpansion struct {
+	TargetUser    string `json:"target_user"`
+	CoreValue     string `json:"core_value"`
+	CategoryGuess string `json:"category_guess"`
+	Specific      bool   `json:"specific"` // the idea was specific enough to plan from as written
+}
+
+// RefineResponse lists refinements suggested for an analyzed idea
+type RefineResponse struct {
+	AnalysisID      string           `json:"analysis_id"`
+	FocusDimensions []DimensionScore `json:"focus_dimensions"` // lowest-scoring dimensions, lowest first
+	Suggestions     []Refinement     `json:"suggestions"`
 }
 
 // ApproxLocation represents geographic location for search context
@@ -139,6 +556,23 @@ type ApproxLocation struct {
 	Region  string `json:"region,omitempty"`
 }
 
+// Normalize trims the location and upper-cases the country code. A location
+// with neither field set is no location, so nil is returned and requests with
+// an empty location behave, and cache, like requests without one.
+func (l *ApproxLocation) Normalize() *ApproxLocation {
+	if l == nil {
+		return nil
+	}
+	normalized := ApproxLocation{
+		Country: strings.ToUpper(strings.TrimSpace(l.Country)),
+		Region:  strings.Join(strings.Fields(l.Region), " "),
+	}
+	if normalized.Country == "" && normalized.Region == "" {
+		return nil
+	}
+	return &normalized
+}
+
 // SearchQuery represents a web search query
 type SearchQuery struct {
 	Query    string `json:"query"`
@@ -146,6 +580,15 @@ type SearchQuery struct {
 	Priority int    `json:"priority"`
 }
 
+// QueryStat records the outcome of one executed search query
+type QueryStat struct {
+	Query   string `json:"query"`
+	Intent  string `json:"intent"`
+	Results int    `json:"results"`
+	Cached  bool   `json:"cached,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
 // CacheEntry represents a cached search result
 type CacheEntry struct {
 	Hash      string          `json:"hash" db:"hash"`
@@ -157,29 +600,171 @@ type CacheEntry struct {
 
 // AnalysisRequest represents an API request for analysis
 type AnalysisRequest struct {
-	Idea    IdeaInput       `json:"idea"`
-	Options *AnalysisOptions `json:"options,omitempty"`
+	Idea      IdeaInput        `json:"idea"`
+	SourceURL string           `json:"source_url,omitempty"` // landing page used to fill in missing idea fields
+	Tags      []string         `json:"tags,omitempty"`
+	Options   *AnalysisOptions `json:"options,omitempty"`
+}
+
+// BatchAnalysisRequest is the body of POST /v1/analyze/batch: several ideas
+// sharing one options block. Options an idea sets itself override the shared ones.
+type BatchAnalysisRequest struct {
+	Ideas   []AnalysisRequest `json:"ideas"`
+	Options *AnalysisOptions  `json:"options,omitempty"`
+}
+
+// AnalysisUpdate is the body of PATCH /v1/analyses/{id}; fields left out are
+// unchanged
+type AnalysisUpdate struct {
+	Tags   *[]string `json:"tags,omitempty"`   // replaces every tag; [] clears them
+	Pinned *bool     `json:"pinned,omitempty"` // pinned analyses are kept by the retention job
 }
 
 // AnalysisOptions represents optional parameters for analysis
 type AnalysisOptions struct {
-	MaxEvidence int            `json:"max_evidence,omitempty"`
-	Location    *ApproxLocation `json:"location,omitempty"`
-	Timeout     *time.Duration  `json:"timeout,omitempty"`
+	MaxEvidence  int             `json:"max_evidence,omitempty"`
+	Location     *ApproxLocation `json:"location,omitempty"`
+	Timeout      *time.Duration  `json:"timeout,omitempty"`
+	ForceRefresh bool            `json:"force_refresh,omitempty"` // bypass the analysis cache
+	FreshSearch  bool            `json:"fresh_search,omitempty"`  // bypass the evidence cache
+
+	SearchTimeout *time.Duration `json:"search_timeout,omitempty"` // evidence search phase, shorter than Timeout
+	// EvidenceMaxAgeDays drops evidence published longer ago; 0 uses the server default
+	EvidenceMaxAgeDays int `json:"evidence_max_age_days,omitempty"`
+	// NextSteps generates a validation plan, an extra LLM call; nil uses the server default
+	NextSteps *bool `json:"next_steps,omitempty"`
+	// ReviewThreshold flags the analysis for review below this overall score;
+	// nil uses the server default and 0 flags nothing
+	ReviewThreshold *float64 `json:"review_threshold,omitempty"`
+	// ExpandIdea has the LLM expand a vague idea before planning searches, an
+	// extra LLM call; nil uses the server default
+	ExpandIdea *bool `json:"expand_idea,omitempty"`
 }
 
-// GetLocation returns the location or nil if not set
+// Merge returns the options with the ones set in override taking their
+// place, leaving both unchanged. Unset means nil, zero or false, so override
+// can't switch off a flag ao turned on.
+func (ao *AnalysisOptions) Merge(override *AnalysisOptions) *AnalysisOptions {
+	if ao == nil {
+		return override
+	}
+	merged := *ao
+	if override == nil {
+		return &merged
+	}
+
+	if override.MaxEvidence != 0 {
+		merged.MaxEvidence = override.MaxEvidence
+	}
+	if override.Location != nil {
+		merged.Location = override.Location
+	}
+	if override.Timeout != nil {
+		merged.Timeout = override.Timeout
+	}
+	merged.ForceRefresh = merged.ForceRefresh || override.ForceRefresh
+	merged.FreshSearch = merged.FreshSearch || override.FreshSearch
+	if override.SearchTimeout != nil {
+		merged.SearchTimeout = override.SearchTimeout
+	}
+	if override.EvidenceMaxAgeDays != 0 {
+		merged.EvidenceMaxAgeDays = override.EvidenceMaxAgeDays
+	}
+	if override.NextSteps != nil {
+		merged.NextSteps = override.NextSteps
+	}
+	if override.ReviewThreshold != nil {
+		merged.ReviewThreshold = override.ReviewThreshold
+	}
+	if override.ExpandIdea != nil {
+		merged.ExpandIdea = override.ExpandIdea
+	}
+	return &merged
+}
+
+// GetLocation returns the normalized location or nil if not set
 func (ao *AnalysisOptions) GetLocation() *ApproxLocation {
 	if ao == nil {
 		return nil
 	}
-	return ao.Location
+	return ao.Location.Normalize()
+}
+
+// ShouldForceRefresh reports whether the analysis cache should be bypassed
+func (ao *AnalysisOptions) ShouldForceRefresh() bool {
+	return ao != nil && ao.ForceRefresh
+}
+
+// WantsNextSteps reports whether a validation plan should be generated, given
+// the server default
+func (ao *AnalysisOptions) WantsNextSteps(byDefault bool) bool {
+	if ao == nil || ao.NextSteps == nil {
+		return byDefault
+	}
+	return *ao.NextSteps
+}
+
+// WantsIdeaExpansion reports whether a vague idea should be expanded before
+// planning searches, given the server default
+func (ao *AnalysisOptions) WantsIdeaExpansion(byDefault bool) bool {
+	if ao == nil || ao.ExpandIdea == nil {
+		return byDefault
+	}
+	return *ao.ExpandIdea
+}
+
+// ShouldSearchFresh reports whether cached search results should be bypassed
+func (ao *AnalysisOptions) ShouldSearchFresh() bool {
+	return ao != nil && ao.FreshSearch
 }
 
 // AnalysisResponse represents the API response for analysis creation
 type AnalysisResponse struct {
-	AnalysisID string `json:"analysis_id"`
+	AnalysisID       string `json:"analysis_id"`
+	Status           string `json:"status"`
+	EffectiveTimeout string `json:"effective_timeout,omitempty"`
+	Cached           bool   `json:"cached"` // served from the analysis cache
+}
+
+// BatchAnalysisResponse reports the outcome of each idea of a batch, in the
+// order submitted
+type BatchAnalysisResponse struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// BatchItemResult is the outcome of one idea of a batch; Status is
+// "completed" or "failed", with Error set when it failed
+type BatchItemResult struct {
+	Index      int    `json:"index"`
+	AnalysisID string `json:"analysis_id,omitempty"`
 	Status     string `json:"status"`
+	Cached     bool   `json:"cached,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ValidationResponse is returned when an analysis request is validated without running it
+type ValidationResponse struct {
+	Valid            bool      `json:"valid"`
+	Idea             IdeaInput `json:"idea"`
+	SourceURL        string    `json:"source_url,omitempty"`
+	PlannedQueries   int       `json:"planned_queries"` // 0 when the idea will come from the source URL
+	EffectiveTimeout string    `json:"effective_timeout"`
+
+	EffectiveSearchTimeout string `json:"effective_search_timeout"`
+
+	Warnings []string `json:"warnings,omitempty"` // e.g. an unknown category analyzed as "other"
+}
+
+// Pagination describes a page of a list response with navigation links
+type Pagination struct {
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+	Total   int    `json:"total"`
+	HasMore bool   `json:"has_more"`
+	Next    string `json:"next,omitempty"`
+	Prev    string `json:"prev,omitempty"`
 }
 
 // ErrorResponse represents an API error response
@@ -188,3 +773,14 @@ type ErrorResponse struct {
 	Code    string `json:"code,omitempty"`
 	Details string `json:"details,omitempty"`
 }
+
+// WebhookDeadLetter is a webhook delivery that failed on every attempt
+type WebhookDeadLetter struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	URL       string          `json:"url"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	CreatedAt time.Time       `json:"created_at"`
+}
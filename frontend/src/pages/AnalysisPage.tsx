@@ -207,7 +207,10 @@ export default function AnalysisPage() {
                                 <List dense>
                                     {analysis.verdict.key_insights.map((insight, index) => (
                                         <ListItem key={index} sx={{ px: 0 }}>
-                                            <ListItemText primary={`• ${insight}`} />
+                                            <ListItemText
+                                                primary={`• ${insight.text}`}
+                                                secondary={insight.evidence_ids.length > 0 ? `Sources: ${insight.evidence_ids.join(', ')}` : undefined}
+                                            />
                                         </ListItem>
                                     ))}
                                 </List>
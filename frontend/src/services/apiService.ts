@@ -3,6 +3,7 @@ import type {
     AnalysisResponse,
     Analysis,
     AnalysisListResponse,
+    AnalysisUpdate,
     StatsResponse,
     HealthResponse,
     ErrorResponse,
@@ -89,11 +90,22 @@ class ApiService {
         return this.request<string>(`/v1/analyses/${id}.html`);
     }
 
+    // Replace the tags of an analysis
+    async updateAnalysis(id: string, update: AnalysisUpdate): Promise<Analysis> {
+        return this.request<Analysis>(`/v1/analyses/${id}`, {
+            method: 'PATCH',
+            body: JSON.stringify(update),
+        });
+    }
+
     // List analyses with pagination and search
     async listAnalyses(
         limit: number = 10,
         offset: number = 0,
-        query?: string
+        query?: string,
+        tag?: string,
+        flagged?: boolean,
+        pinned?: boolean
     ): Promise<AnalysisListResponse> {
         const params = new URLSearchParams({
             limit: limit.toString(),
@@ -104,6 +116,18 @@ class ApiService {
             params.append('q', query);
         }
 
+        if (tag) {
+            params.append('tag', tag);
+        }
+
+        if (flagged !== undefined) {
+            params.append('flagged', String(flagged));
+        }
+
+        if (pinned !== undefined) {
+            params.append('pinned', String(pinned));
+        }
+
         return this.request<AnalysisListResponse>(`/v1/analyses?${params}`);
     }
 
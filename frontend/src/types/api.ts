@@ -16,16 +16,62 @@ export interface AnalysisOptions {
   max_evidence?: number;
   location?: ApproxLocation;
   timeout?: string;
+  search_timeout?: string;
+  evidence_max_age_days?: number;
+  next_steps?: boolean;
+  review_threshold?: number;
+  expand_idea?: boolean;
+  force_refresh?: boolean;
+  fresh_search?: boolean;
 }
 
 export interface AnalysisRequest {
   idea: IdeaInput;
+  tags?: string[];
   options?: AnalysisOptions;
 }
 
+// Body of PATCH /v1/analyses/{id}
+export interface AnalysisUpdate {
+  tags?: string[];
+  pinned?: boolean;
+}
+
+// Server-sent "dimension" event of POST /v1/analyze/stream; result is preliminary
+export interface DimensionEvent {
+  dimension: 'market' | 'problem' | 'barriers' | 'execution' | 'risks' | 'graveyard';
+  result?: unknown;
+  error?: string;
+}
+
+// Server-sent "recommendation" event of POST /v1/analyze/stream?stream_verdict=true
+export interface RecommendationEvent {
+  text: string;
+}
+
 export interface AnalysisResponse {
   analysis_id: string;
+  status: 'completed' | 'failed' | 'running' | 'cancelled';
+  cached?: boolean;
+}
+
+export interface BatchAnalysisRequest {
+  ideas: AnalysisRequest[];
+  options?: AnalysisOptions;
+}
+
+export interface BatchItemResult {
+  index: number;
+  analysis_id?: string;
   status: 'completed' | 'failed';
+  cached?: boolean;
+  error?: string;
+}
+
+export interface BatchAnalysisResponse {
+  results: BatchItemResult[];
+  succeeded: number;
+  failed: number;
 }
 
 export interface Evidence {
@@ -36,6 +82,8 @@ export interface Evidence {
   published_at?: string;
   retrieved_at: string;
   source_type?: string;
+  content_type?: 'pdf' | 'document' | 'video' | 'paywalled';
+  intents?: string[];
 }
 
 export interface Competitor {
@@ -44,6 +92,20 @@ export interface Competitor {
   funding?: string;
   stage?: string;
   evidence_ids: string[];
+  overlap?: number;
+  threat_score?: number;
+  funding_normalized?: MoneyAmount;
+  unsupported?: boolean;
+}
+
+export interface MoneyAmount {
+  original: string;
+  usd?: number;
+  usd_min?: number;
+  usd_max?: number;
+  currency?: string;
+  currency_assumed?: boolean;
+  parsed: boolean;
 }
 
 export interface Risk {
@@ -53,6 +115,7 @@ export interface Risk {
   likelihood: number;
   mitigation?: string;
   evidence_ids: string[];
+  unsupported?: boolean;
 }
 
 export interface Barrier {
@@ -60,6 +123,7 @@ export interface Barrier {
   description: string;
   weight: number;
   evidence_ids: string[];
+  unsupported?: boolean;
 }
 
 export interface GraveyardCase {
@@ -68,6 +132,7 @@ export interface GraveyardCase {
   failure_cause: string;
   lessons: string;
   evidence_ids: string[];
+  unsupported?: boolean;
 }
 
 export interface MarketAnalysis {
@@ -104,6 +169,18 @@ export interface RiskAnalysis {
 export interface GraveyardAnalysis {
   cases: GraveyardCase[];
   evidence_ids: string[];
+  search?: PostmortemSearch;
+}
+
+export interface PostmortemSearch {
+  queries: number;
+  answered: number;
+  results: number;
+}
+
+export interface KeyInsight {
+  text: string;
+  evidence_ids: string[];
 }
 
 export interface Viability {
@@ -115,13 +192,43 @@ export interface Viability {
   risk_score: number;
   graveyard_score: number;
   recommendation: string;
-  key_insights: string[];
+  key_insights: KeyInsight[];
+  evidence_ids: string[];
+  score_rationale?: Record<string, string>;
+  fallback?: boolean;
+}
+
+export interface SourceDiversity {
+  domains: string[];
+  cited: number;
+  confidence: number;
+  narrow?: boolean;
+}
+
+export interface Coherence {
+  score: number;
+  minimum: number;
+  off_topic?: string[];
+  reason?: string;
+  suspect?: boolean;
+}
+
+export interface AnalyzerDebug {
+  raw_output: Record<string, any>;
+  stripped_evidence_ids?: string[];
+}
+
+export interface ValidationStep {
+  action: string;
+  dimension: string;
+  success_signal: string;
   evidence_ids: string[];
 }
 
 export interface Analysis {
   id: string;
   idea: IdeaInput;
+  tags?: string[];
   market: MarketAnalysis;
   problem: ProblemAnalysis;
   barriers: BarrierAnalysis;
@@ -129,10 +236,36 @@ export interface Analysis {
   risks: RiskAnalysis;
   graveyard: GraveyardAnalysis;
   verdict: Viability;
+  verdict_version?: number;
+  flagged?: boolean;
+  review_threshold?: number;
+  pinned?: boolean;
+  next_steps?: ValidationStep[];
   evidence: Evidence[];
+  evidence_citations?: Record<string, string[]>;
+  source_diversity?: Record<string, SourceDiversity>;
+  created_at: string;
+  partial?: boolean;
+  meta?: {
+    coherence?: Coherence;
+    models?: Record<string, string>;
+    debug?: Record<string, AnalyzerDebug>;
+    [key: string]: any;
+  };
+}
+
+// Scores-only projection returned with ?fields=scores
+export interface AnalysisScores {
+  id: string;
+  idea: IdeaInput;
+  tags?: string[];
+  verdict: Viability;
+  verdict_version?: number;
+  flagged?: boolean;
+  pinned?: boolean;
+  summary?: string;
   created_at: string;
   partial?: boolean;
-  meta?: any;
 }
 
 export interface AnalysisListResponse {
@@ -148,15 +281,23 @@ export interface StatsResponse {
   total_analyses: number;
   max_evidence: number;
   timeout: string;
+  search_timeout?: string;
+  analysis_queue?: {
+    workers: number;
+    running: number;
+    queued: number;
+    queue_depth: number;
+  };
 }
 
 export interface HealthResponse {
-  status: 'healthy' | 'unhealthy';
+  status: 'healthy' | 'unhealthy' | 'ready';
+  maintenance?: boolean;
 }
 
 export interface ErrorResponse {
   error: string;
-  code?: string;
+  code: string;
   details?: string;
 }
 